@@ -3,4 +3,47 @@ package v1alpha1
 type AuthenticationSpec struct {
 	// +kubebuilder:validation:Required
 	AuthenticationClass string `json:"authenticationClass,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// LDAP configures the Doris side of an LDAP AuthenticationClass.
+	LDAP *LDAPAuthenticationSpec `json:"ldap,omitempty"`
+}
+
+// LDAPAuthenticationSpec configures group lookup and group-to-role mapping for LDAP users.
+type LDAPAuthenticationSpec struct {
+	// +kubebuilder:validation:Optional
+	// GroupSearchBase is the base DN of the LDAP groups. Defaults to the searchBase of the AuthenticationClass.
+	GroupSearchBase string `json:"groupSearchBase,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// GroupRoleMappings grants privileges to the LDAP groups.
+	// Doris gives an LDAP user the roles named after its groups, so each mapping creates
+	// the role of the group if missing and grants it the privileges.
+	// Roles that already exist, e.g. declared by other means, are extended; privileges
+	// removed from a mapping are not revoked.
+	GroupRoleMappings []LDAPGroupRoleMapping `json:"groupRoleMappings,omitempty"`
+}
+
+// LDAPGroupRoleMapping maps an LDAP group to the Doris role of the same name.
+type LDAPGroupRoleMapping struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[A-Za-z][A-Za-z0-9_-]*$`
+	// Group is the common name of the LDAP group, and the name of its Doris role.
+	Group string `json:"group"`
+
+	// +kubebuilder:validation:Optional
+	Grants []RoleGrant `json:"grants,omitempty"`
+}
+
+// RoleGrant grants privileges on a resource.
+type RoleGrant struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Enum=NODE_PRIV;ADMIN_PRIV;GRANT_PRIV;SELECT_PRIV;LOAD_PRIV;ALTER_PRIV;CREATE_PRIV;DROP_PRIV;USAGE_PRIV;SHOW_VIEW_PRIV
+	Privileges []string `json:"privileges"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_%*-]+(\.[A-Za-z0-9_%*-]+){0,2}$`
+	// On is the resource of the grant, e.g. `*.*.*`, `internal.db1.*` or `db1.tbl1`.
+	On string `json:"on"`
 }
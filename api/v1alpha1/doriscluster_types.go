@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	commonsv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/commons/v1alpha1"
 	"github.com/zncdatadev/operator-go/pkg/status"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -48,6 +49,27 @@ type DorisClusterStatus struct {
 	// has been created and granted privileges in the Doris cluster.
 	AuthInitialized bool `json:"authInitialized,omitempty"`
 
+	// +kubebuilder:validation:Optional
+	// RootPasswordInitialized indicates whether the root password from the root password
+	// Secret has been set in the Doris cluster. From then on root no longer has an empty password.
+	RootPasswordInitialized bool `json:"rootPasswordInitialized,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// AuthSecretHash is the HMAC of the authSecret credentials applied to Doris, keyed with
+	// the `<name>-hash-key` Secret.
+	// When the Secret changes, the operator rotates the admin credentials in Doris.
+	AuthSecretHash string `json:"authSecretHash,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// LDAPHash is the HMAC of the LDAP bind password and group role mappings applied to Doris,
+	// keyed with the `<name>-hash-key` Secret.
+	LDAPHash string `json:"ldapHash,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// StaticUsersHash is the HMAC of the users of the static AuthenticationClasses applied to Doris,
+	// keyed with the `<name>-hash-key` Secret.
+	StaticUsersHash string `json:"staticUsersHash,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	FrontendNodes []NodeStatus `json:"frontendNodes,omitempty"`
 
@@ -56,6 +78,128 @@ type DorisClusterStatus struct {
 
 	// +kubebuilder:validation:Optional
 	BrokerNodes []NodeStatus `json:"brokerNodes,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Teardown reports the progress of the teardown once the DorisCluster is being deleted.
+	Teardown *TeardownStatus `json:"teardown,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// RollingRestart records the FE config the operator changed while BE pods restart, with
+	// the values to restore once the BEs rejoined and the tablets are healthy again.
+	RollingRestart *RollingRestartStatus `json:"rollingRestart,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Health summarizes the data health of the cluster, the score band and the reasons of
+	// the problems found are reported in the Healthy condition. It is evaluated while pods roll
+	// out as well, and holds off BE decommissions while tablets miss replicas; rollouts are not
+	// gated on it. The counts are only updated when they change by more than a tenth or reach
+	// or leave zero.
+	Health *HealthStatus `json:"health,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// SelfHealing records the nodes found dead and the last self-healing action.
+	SelfHealing *SelfHealingStatus `json:"selfHealing,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// OrphanNodes lists the FE and BE nodes registered in Doris that no pod of the cluster backs.
+	OrphanNodes []OrphanNodeStatus `json:"orphanNodes,omitempty"`
+}
+
+// OrphanNodeStatus represents a node registered in Doris without a pod of the cluster
+type OrphanNodeStatus struct {
+	// +kubebuilder:validation:Optional
+	// Component is fe or be
+	Component string `json:"component,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Host string `json:"host,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Port is the edit log port of a FE or the heartbeat port of a BE
+	Port int32 `json:"port,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Role is the FE role (FOLLOWER/OBSERVER), empty for BE
+	Role string `json:"role,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Alive bool `json:"alive,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Since is when the node was first found without a pod
+	Since metav1.Time `json:"since,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Message tells why the node is not cleaned up, when cleanup is enabled
+	Message string `json:"message,omitempty"`
+}
+
+// SelfHealingStatus represents the nodes waiting for self-healing
+type SelfHealingStatus struct {
+	// +kubebuilder:validation:Optional
+	// DeadSince maps the pods of the nodes Doris reports dead to when it was first observed
+	DeadSince map[string]metav1.Time `json:"deadSince,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// LastActionTime is when the last self-healing action was taken
+	LastActionTime *metav1.Time `json:"lastActionTime,omitempty"`
+}
+
+// HealthStatus represents the data health of the cluster observed in Doris
+type HealthStatus struct {
+	// +kubebuilder:validation:Optional
+	// Score goes from 0 to 100, a cluster without problems scores 100
+	Score int32 `json:"score"`
+
+	// +kubebuilder:validation:Optional
+	// TabletNum is the number of tablets of the cluster
+	TabletNum int32 `json:"tabletNum,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// UnhealthyTabletNum is the number of tablets not healthy for any reason
+	UnhealthyTabletNum int32 `json:"unhealthyTabletNum,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// UnderReplicatedTabletNum is the number of tablets missing replicas or data versions
+	UnderReplicatedTabletNum int32 `json:"underReplicatedTabletNum,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// MaxDiskUsedPercent is the usage percentage of the fullest BE data disk
+	MaxDiskUsedPercent int32 `json:"maxDiskUsedPercent,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// MaxJournalLag is the number of edit log entries the most lagging FE is behind the master
+	MaxJournalLag int64 `json:"maxJournalLag,omitempty"`
+}
+
+// RollingRestartStatus represents the tablet scheduling paused while BE pods restart
+type RollingRestartStatus struct {
+	// +kubebuilder:validation:Optional
+	// StartTime is the time the tablet scheduling was paused
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// FrontendConfig holds the values of the FE configs before they were changed
+	FrontendConfig map[string]string `json:"frontendConfig,omitempty"`
+}
+
+// TeardownStatus represents the progress of the DorisCluster teardown
+type TeardownStatus struct {
+	// +kubebuilder:validation:Optional
+	// Phase is the current teardown step: StoppingLoads / BackingUp / StoppingBackends /
+	// StoppingFrontends / ReleasingStorage / ClearingAuth / Completed
+	Phase string `json:"phase,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// BackupLabel is the snapshot label of the final backup
+	BackupLabel string `json:"backupLabel,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// PhaseStartTime is the time the current phase started
+	PhaseStartTime *metav1.Time `json:"phaseStartTime,omitempty"`
 }
 
 // NodeStatus represents the status of a Doris cluster node
@@ -76,6 +220,43 @@ type NodeStatus struct {
 	// +kubebuilder:validation:Optional
 	// Phase is the node lifecycle phase: Registered / Decommissioning / Decommissioned / ForceDropped
 	Phase string `json:"phase,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Version is the Doris version the node runs
+	Version string `json:"version,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// LastStartTime is when the node last started, in the time zone of FE
+	LastStartTime string `json:"lastStartTime,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// HeartbeatError is the error of the last failed heartbeat from FE to the node
+	HeartbeatError string `json:"heartbeatError,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ReplayedJournalID is the last edit log entry the FE replayed, FE only
+	ReplayedJournalID int64 `json:"replayedJournalId,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// CPUCores is the number of CPU cores of the BE, BE only
+	CPUCores int32 `json:"cpuCores,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// DataUsed is the size of the Doris data stored on the BE, BE only
+	DataUsed *resource.Quantity `json:"dataUsed,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// DiskUsed is the space used on the data disks of the BE, including files other than
+	// Doris data, BE only
+	DiskUsed *resource.Quantity `json:"diskUsed,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// DiskTotal is the capacity of the data disks of the BE, BE only
+	DiskTotal *resource.Quantity `json:"diskTotal,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Tags are the tags of the BE without the `tag.` prefix, e.g. location, BE only
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -95,6 +276,18 @@ type AuthSecretSpec struct {
 	//   - username: the admin user name (defaults to "root" if not set)
 	//   - password: the admin user password
 	SecretName string `json:"secretName"`
+
+	// +kubebuilder:validation:Optional
+	// DropPreviousUser drops the previous admin user once the username in the Secret changed
+	// and the new user is able to log in. The root user is never dropped.
+	DropPreviousUser bool `json:"dropPreviousUser,omitempty"`
+}
+
+// RootPasswordSecretSpec references the password of the Doris root user.
+type RootPasswordSecretSpec struct {
+	// +kubebuilder:validation:Required
+	// Name of the Secret in the same namespace as the DorisCluster, with the root password under `password`.
+	SecretName string `json:"secretName"`
 }
 
 // DorisClusterSpec defines the desired state of DorisCluster
@@ -123,8 +316,19 @@ type DorisClusterSpec struct {
 	// If configured, the operator will use these credentials to connect to Doris FE for scale management.
 	// If the specified user does not exist in Doris, the operator will create it with NODE_PRIV
 	// and GRANT_PRIV privileges on first cluster initialization.
-	// If not configured, the operator defaults to root with an empty password.
+	// Changes of the credentials are applied to Doris: the password is altered, a new username
+	// is created with the same privileges. The change is rolled back if the new login fails.
+	// If not configured, the operator uses root with the root password.
 	AuthSecret *AuthSecretSpec `json:"authSecret,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// RootPasswordSecret references a Secret containing the password of the Doris root user.
+	// The operator sets it with SET PASSWORD once the admin user exists, and never connects
+	// as root with an empty password afterwards. If not configured, the operator generates
+	// the Secret `<cluster>-root-password` with a random password.
+	// Changing the password after it has been set is not applied to Doris.
+	// Ignored when the authSecret user is root: the authSecret password is used.
+	RootPasswordSecret *RootPasswordSecretSpec `json:"rootPasswordSecret,omitempty"`
 }
 
 type ClusterConfigSpec struct {
@@ -146,10 +350,270 @@ type ClusterConfigSpec struct {
 	VectorAggregatorConfigMapName *string `json:"vectorAggregatorConfigMapName,omitempty"`
 
 	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=8
+	// +listType=map
+	// +listMapKey=authenticationClass
+	// Authentication lists the AuthenticationClasses used to log in to Doris:
+	//   - static: the users of the Secret are created as Doris users logging in with their password.
+	//   - ldap: at most one. Users found in LDAP log in with their LDAP password, other users
+	//     fall back to their Doris password, e.g. the admin user and static users.
+	//   - tls: at most one, requires clusterConfig.tls. The MySQL port only accepts connections
+	//     presenting a certificate issued by the CA of the clientCertSecretClass, e.g. from a
+	//     secret-operator volume of that SecretClass. This restricts which clients may connect,
+	//     it is not a login method: clients still log in with the password of a Doris user.
+	// Other providers, further LDAP or TLS classes and unresolvable classes are not rejected when
+	// the cluster is created or updated, but when it is reconciled: the cluster is not reconciled
+	// until they are fixed, and the Authentication condition explains why.
 	Authentication []AuthenticationSpec `json:"authentication,omitempty"`
 
 	// +kubebuilder:validation:Optional
 	ScaleDownPolicy *ScaleDownPolicySpec `json:"scaleDownPolicy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	PersistentVolumeClaimRetentionPolicy *PersistentVolumeClaimRetentionPolicySpec `json:"persistentVolumeClaimRetentionPolicy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Teardown *TeardownSpec `json:"teardown,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="30s"
+	// StatePollInterval is the interval at which the operator polls SHOW FRONTENDS / BACKENDS / BROKER.
+	// The cluster is reconciled as soon as the Doris node state changes. Set to 0 to disable polling.
+	StatePollInterval *metav1.Duration `json:"statePollInterval,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// TLS encrypts the FE MySQL port and the FE and BE HTTP servers with certificates issued
+	// by the secret-operator. It is not cluster-wide encryption: the Thrift and bRPC traffic
+	// between FE and BE, the FE edit log replication, Arrow Flight and the broker pods stay
+	// in plaintext, so the pod network must be trusted or encrypted by other means.
+	TLS *TLSSpec `json:"tls,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Ingress exposes the FE web UI and REST APIs on ingressHost.
+	Ingress *IngressSpec `json:"ingress,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Discovery *DiscoverySpec `json:"discovery,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ArrowFlight enables Arrow Flight SQL on FE and BE.
+	ArrowFlight *ArrowFlightSpec `json:"arrowFlight,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// SelfHealing restarts the FE and BE pods Doris reports dead while they are running, and
+	// replaces the BEs whose storage is lost.
+	SelfHealing *SelfHealingSpec `json:"selfHealing,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// OrphanNodes removes the FE and BE nodes registered in Doris that no pod of the cluster
+	// backs, e.g. left by a renamed role group, a changed clusterDomain or a failed scale-down.
+	// Orphan nodes are listed in the status whether or not their cleanup is enabled.
+	OrphanNodes *OrphanNodesSpec `json:"orphanNodes,omitempty"`
+}
+
+// OrphanNodesSpec configures the cleanup of orphan nodes. Alive BEs are decommissioned before
+// they are dropped, and FE followers are only dropped while the remaining alive followers keep
+// a quorum. The master FE is never dropped.
+type OrphanNodesSpec struct {
+	// +kubebuilder:validation:Optional
+	// AutoCleanup removes the orphan nodes from Doris, it is off by default.
+	AutoCleanup bool `json:"autoCleanup,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="10m"
+	// GracePeriod is how long a node stays without a pod before it is removed.
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+}
+
+// SelfHealingSpec configures the repair of the nodes Doris reports dead. Every action is
+// recorded as an Event on the DorisCluster, and at most one action is taken per interval.
+type SelfHealingSpec struct {
+	// +kubebuilder:validation:Optional
+	// Enabled turns self-healing on, it is off by default.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="10m"
+	// GracePeriod is how long a node stays dead in Doris before it is repaired.
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="30m"
+	// MinActionInterval is the minimum time between two self-healing actions on the cluster.
+	MinActionInterval *metav1.Duration `json:"minActionInterval,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ReplaceLostBackends drops a dead BE whose PVC is lost, or whose pod is annotated with
+	// `doris.kubedoop.dev/replace-storage: "true"` because its disk is corrupt, then deletes its
+	// PVCs and its pod so that it registers again with an empty disk. It is only done when the
+	// unhealthy tablets with a replica on the BE have a complete replica on another alive BE,
+	// and waits while more than 1000 tablets are unhealthy.
+	ReplaceLostBackends bool `json:"replaceLostBackends,omitempty"`
+}
+
+// ArrowFlightSpec enables Arrow Flight SQL. Clients send queries to the FE port and fetch the
+// results from the BE port of the BEs returned by FE. BEs advertise their pod address, or the
+// address of their listener Service when the backend listenerClass is external: BE pods then
+// wait for the operator to resolve it when they start.
+// +kubebuilder:validation:XValidation:rule="!(self.fePort in [8030, 8050, 9010, 9020, 9030])",message="fePort conflicts with another FE port"
+// +kubebuilder:validation:XValidation:rule="!(self.bePort in [8040, 8060, 9050, 9060])",message="bePort conflicts with another BE port"
+type ArrowFlightSpec struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=8070
+	// +kubebuilder:validation:Minimum=1024
+	// +kubebuilder:validation:Maximum=65535
+	FEPort int32 `json:"fePort,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=8050
+	// +kubebuilder:validation:Minimum=1024
+	// +kubebuilder:validation:Maximum=65535
+	BEPort int32 `json:"bePort,omitempty"`
+}
+
+// DiscoverySpec configures what the operator publishes for the clients of the cluster, besides
+// the `<cluster>-discovery` ConfigMap holding the FE and BE addresses and the JDBC URL.
+type DiscoverySpec struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=32
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z][a-zA-Z0-9_]{0,63}$`
+	// ConnectionSecretUsers lists the Doris users a `<cluster>-connection-<user>` Secret is
+	// published for, with the discovery keys and the `username` and `password` of the user.
+	// Only the authSecret user and the users of static AuthenticationClasses are supported,
+	// the operator does not know the password of other users and skips them.
+	ConnectionSecretUsers []string `json:"connectionSecretUsers,omitempty"`
+}
+
+// IngressSpec exposes the FE HTTP port of the `<cluster>-fe-service` Service, with an
+// Ingress, or with a Gateway API HTTPRoute when gateway is set.
+// With clusterConfig.tls FE only serves HTTPS, so the HTTPS port is exposed instead. The
+// Ingress then gets the `nginx.ingress.kubernetes.io/backend-protocol: HTTPS` annotation,
+// other ingress controllers need their own annotation. An HTTPRoute needs a
+// BackendTLSPolicy for the `<cluster>-fe-service` Service, which is not created.
+// +kubebuilder:validation:XValidation:rule="!has(self.gateway) || (!has(self.ingressClassName) && !has(self.tls))",message="ingressClassName and tls cannot be set with gateway, TLS is terminated by the Gateway"
+type IngressSpec struct {
+	// +kubebuilder:validation:Optional
+	// IngressClassName is the class of the Ingress. The default ingress class is used if not set.
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Annotations replace the annotations of the Ingress or HTTPRoute.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=16
+	// +kubebuilder:validation:items:Pattern=`^/`
+	// +kubebuilder:default={"/"}
+	// Paths are the path prefixes routed to FE.
+	Paths []string `json:"paths,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// TLS terminates TLS on the Ingress.
+	TLS *IngressTLSSpec `json:"tls,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Gateway creates an HTTPRoute attached to the Gateways instead of an Ingress.
+	Gateway *GatewaySpec `json:"gateway,omitempty"`
+}
+
+// IngressTLSSpec configures the certificate of the Ingress.
+type IngressTLSSpec struct {
+	// +kubebuilder:validation:Required
+	// SecretName is the name of a `kubernetes.io/tls` Secret in the DorisCluster namespace.
+	SecretName string `json:"secretName"`
+}
+
+// GatewaySpec attaches an HTTPRoute to Gateway API Gateways.
+type GatewaySpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	ParentRefs []GatewayParentReference `json:"parentRefs"`
+}
+
+// GatewayParentReference references a Gateway, and optionally one of its listeners.
+type GatewayParentReference struct {
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Optional
+	// Namespace of the Gateway. Defaults to the DorisCluster namespace.
+	Namespace string `json:"namespace,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// SectionName is the name of the Gateway listener.
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// TLSSpec enables TLS on the FE MySQL protocol and on the FE and BE HTTP servers.
+// FE and BE mount the certificates issued by the secret-operator, and the operator
+// only connects to FE over TLS, verifying the FE certificate.
+type TLSSpec struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="tls"
+	// ServerSecretClass is the SecretClass issuing the FE and BE server certificates.
+	ServerSecretClass string `json:"serverSecretClass,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// CASecret is the name of a Secret in the DorisCluster namespace with the CA certificate
+	// under `ca.crt`, used by the operator to verify FE.
+	// If not set, the CA of the `autoTls` backend of the server SecretClass is used.
+	CASecret string `json:"caSecret,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=VerifyCA;VerifyFull
+	// +kubebuilder:default="VerifyFull"
+	// Verification of the FE certificate: VerifyCA only checks the certificate chain,
+	// VerifyFull also checks that the certificate matches the FE host name.
+	Verification string `json:"verification,omitempty"`
+}
+
+// TeardownSpec defines the steps the operator runs before a DorisCluster is deleted.
+// Teardown runs in order: stop routine loads, final backup, stop BEs and brokers,
+// stop FEs, apply the PVC retention policy, close the operator connections to Doris.
+type TeardownSpec struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	// StopRoutineLoads pauses all running routine load jobs, so the final backup is consistent.
+	StopRoutineLoads *bool `json:"stopRoutineLoads,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Backup *TeardownBackupSpec `json:"backup,omitempty"`
+}
+
+// TeardownBackupSpec defines the final backup taken during teardown.
+type TeardownBackupSpec struct {
+	// +kubebuilder:validation:Required
+	// Repository is the name of an existing Doris repository, created with `CREATE REPOSITORY`.
+	Repository string `json:"repository"`
+
+	// +kubebuilder:validation:Optional
+	// Databases to back up. Defaults to all user databases.
+	Databases []string `json:"databases,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1h"
+	// Timeout is the maximum duration to wait for the backup jobs.
+	// If a backup fails or times out, teardown stops and reports the error in status;
+	// remove `backup` from the spec to finish the teardown without it.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// PersistentVolumeClaimRetentionPolicySpec defines the lifecycle of FE and BE PVCs.
+type PersistentVolumeClaimRetentionPolicySpec struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +kubebuilder:default=Retain
+	// WhenScaled controls the PVCs of pods removed by a scale-down.
+	// With `Delete`, the operator removes a PVC only after the pod is gone and Doris no longer
+	// lists the node, i.e. the BE was decommissioned or dropped and the FE observer was dropped.
+	WhenScaled string `json:"whenScaled,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +kubebuilder:default=Retain
+	// WhenDeleted controls the PVCs when the cluster is deleted.
+	// It is applied as the StatefulSet `persistentVolumeClaimRetentionPolicy.whenDeleted`.
+	WhenDeleted string `json:"whenDeleted,omitempty"`
 }
 
 // ScaleDownPolicySpec defines the scale-down policy for Doris cluster components.
@@ -172,19 +636,35 @@ type ScaleDownPolicySpec struct {
 	FrontendStrategy string `json:"frontendStrategy,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="(has(self.config) && has(self.config.storageVolumes) ? self.config.storageVolumes.map(v, v.name) : []) == (has(oldSelf.config) && has(oldSelf.config.storageVolumes) ? oldSelf.config.storageVolumes.map(v, v.name) : [])",message="storageVolumes of an existing role cannot be added, removed or renamed"
 type RoleSpec struct {
 	// +kubebuilder:validation:Optional
 	Config *ConfigSpec `json:"config,omitempty"`
 
 	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxProperties=32
 	RoleGroups map[string]RoleGroupSpec `json:"roleGroups,omitempty"`
 
 	// +kubebuilder:validation:Optional
 	RoleConfig *commonsv1alpha1.RoleConfigSpec `json:"roleConfig,omitempty"`
 
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=cluster-internal;external-unstable;external-stable
+	// ListenerClass exposes the role outside of Kubernetes: `cluster-internal` (the default)
+	// with a ClusterIP Service, `external-unstable` with a NodePort Service and
+	// `external-stable` with a LoadBalancer Service.
+	// On the frontend role, the `<cluster>-fe-service` Service gets the listener class and
+	// only exposes the query and HTTP ports when external.
+	// On the backend role, each pod gets its own `<pod>-listener` Service exposing the HTTP port,
+	// and its address is advertised to Doris as the BE `tag.public_endpoint`, so that Stream Load
+	// redirects work from outside with the `redirect-policy: public` header.
+	// Not used by the broker role.
+	ListenerClass string `json:"listenerClass,omitempty"`
+
 	*commonsv1alpha1.OverridesSpec `json:",inline"`
 }
 
+// +kubebuilder:validation:XValidation:rule="(has(self.config) && has(self.config.storageVolumes) ? self.config.storageVolumes.map(v, v.name) : []) == (has(oldSelf.config) && has(oldSelf.config.storageVolumes) ? oldSelf.config.storageVolumes.map(v, v.name) : [])",message="storageVolumes of an existing role group cannot be added, removed or renamed"
 type RoleGroupSpec struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=1
@@ -197,6 +677,59 @@ type RoleGroupSpec struct {
 }
 type ConfigSpec struct {
 	*commonsv1alpha1.RoleGroupConfigSpec `json:",inline"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=16
+	// StorageVolumes lists the data volumes of a BE node. Each entry is rendered as its own
+	// PVC and mount, and is registered in `storage_root_path` with its storage medium.
+	// When empty, BE uses a single volume sized from `resources.storage`.
+	// Entries defined on a role group replace role-level entries with the same name.
+	// The volumes of an existing role or role group cannot be added, removed or renamed: the
+	// volume claim templates of a StatefulSet are immutable, and a BE switching from its single
+	// volume would leave the data of that volume out of `storage_root_path`.
+	// Only used by the backend role.
+	StorageVolumes []StorageVolumeSpec `json:"storageVolumes,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// StartupProbe holds back the liveness probe until FE or BE started, e.g. while FE replays
+	// its metadata. FE may take 10 minutes and BE 5 minutes by default.
+	// Not used by the broker role.
+	StartupProbe *StartupProbeSpec `json:"startupProbe,omitempty"`
+}
+
+// StartupProbeSpec defines how long FE or BE may take to start.
+type StartupProbeSpec struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// FailureThreshold is the number of failed probes before the container is restarted.
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+}
+
+// StorageVolumeSpec defines a BE storage volume.
+type StorageVolumeSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=32
+	// Name of the volume. It is used in the PVC name and the mount path.
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="20Gi"
+	Capacity resource.Quantity `json:"capacity,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=SSD;HDD
+	// +kubebuilder:default=HDD
+	// Medium is the Doris storage medium of the volume, used for storage-medium cooldown.
+	Medium string `json:"medium,omitempty"`
 }
 
 func init() {
@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	"github.com/zncdatadev/doris-operator/internal/controller/fe"
+	authv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/authentication/v1alpha1"
+	"github.com/zncdatadev/operator-go/pkg/client"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// authenticationConditionType reports whether the AuthenticationClasses are supported
+	authenticationConditionType = "Authentication"
+
+	authenticationReasonApplied  = "Applied"
+	authenticationReasonRejected = "Rejected"
+)
+
+// resolveAuthentication resolves the AuthenticationClasses of the cluster and reports in the
+// Authentication condition whether they are supported. It returns false when the cluster
+// has unsupported or unresolvable classes: the cluster is then not reconciled, rather than
+// applied with some classes left out. It is resolved again when a referenced
+// AuthenticationClass changes, see mapAuthenticationClassToClusters.
+func (r *DorisClusterReconciler) resolveAuthentication(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (*fe.Authentication, bool, error) {
+	var authSpec []dorisv1alpha1.AuthenticationSpec
+	if instance.Spec.ClusterConfig != nil {
+		authSpec = instance.Spec.ClusterConfig.Authentication
+	}
+	auth := fe.ResolveAuthentication(ctx, &client.Client{Client: r.Client, OwnerReference: instance}, authSpec)
+
+	problems := slices.Clone(auth.Errors)
+	if auth.TLS != nil && !auth.ClientAuthEnabled(instance) {
+		problems = append(problems, "TLS client certificate authentication requires clusterConfig.tls")
+	}
+
+	condition := metav1.Condition{
+		Type:               authenticationConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             authenticationReasonApplied,
+		Message:            "All AuthenticationClasses are applied",
+		ObservedGeneration: instance.Generation,
+	}
+	if len(problems) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = authenticationReasonRejected
+		condition.Message = "Cluster not reconciled: " + strings.Join(problems, "; ")
+	}
+	if current := apimeta.FindStatusCondition(instance.Status.Conditions, condition.Type); current != nil &&
+		current.Status == condition.Status && current.Reason == condition.Reason &&
+		current.Message == condition.Message && current.ObservedGeneration == condition.ObservedGeneration {
+		return auth, len(problems) == 0, nil
+	}
+	if len(problems) > 0 {
+		r.recordEvent(instance, nil, corev1.EventTypeWarning, authenticationReasonRejected, "Reconcile",
+			"%s", condition.Message)
+	}
+	err := r.patchStatus(ctx, instance, func(status *dorisv1alpha1.DorisClusterStatus) {
+		apimeta.SetStatusCondition(&status.Conditions, condition)
+	})
+	return auth, len(problems) == 0, err
+}
+
+// reconcileStaticUsers creates the users of the static AuthenticationClasses in Doris and
+// applies their password changes. Users removed from the Secrets are not dropped, and the
+// root and admin users are never managed through them.
+func (r *DorisClusterReconciler) reconcileStaticUsers(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	target doris_client.ClusterTarget,
+	mgmtClient *doris_client.DorisClient,
+	providers []*authv1alpha1.StaticProvider,
+) error {
+	users, err := r.getStaticUsers(ctx, instance, providers)
+	if err != nil {
+		return err
+	}
+	for _, user := range []string{doris_client.DefaultAdminUser, target.User} {
+		if _, ok := users[user]; ok {
+			logger.Info("Ignoring static user managed by the operator", "cluster", instance.Name, "user", user)
+			delete(users, user)
+		}
+	}
+
+	var key []byte
+	if len(users) > 0 {
+		if key, err = r.hashKey(ctx, instance); err != nil {
+			return err
+		}
+	}
+	hash := staticUsersHash(key, users)
+	if hash == instance.Status.StaticUsersHash {
+		return nil
+	}
+
+	names := make([]string, 0, len(users))
+	for user := range users {
+		names = append(names, user)
+	}
+	slices.Sort(names)
+	for _, user := range names {
+		exists, err := mgmtClient.CheckUserExists(ctx, user)
+		if err != nil {
+			return err
+		}
+		if exists {
+			err = mgmtClient.AlterUserPassword(ctx, user, users[user])
+		} else {
+			err = mgmtClient.CreateUser(ctx, user, users[user])
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	logger.Info("Applied static users", "cluster", instance.Name, "users", len(names))
+	return r.patchStatus(ctx, instance, func(status *dorisv1alpha1.DorisClusterStatus) {
+		status.StaticUsersHash = hash
+	})
+}
+
+// getStaticUsers returns the passwords of the users of the static AuthenticationClasses, by user
+func (r *DorisClusterReconciler) getStaticUsers(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	providers []*authv1alpha1.StaticProvider,
+) (map[string]string, error) {
+	users := map[string]string{}
+	for _, provider := range providers {
+		if provider.UserCredentialsSecret == nil {
+			continue
+		}
+		secret := &corev1.Secret{}
+		key := types.NamespacedName{Name: provider.UserCredentialsSecret.Name, Namespace: instance.Namespace}
+		if err := r.Get(ctx, key, secret); err != nil {
+			return nil, fmt.Errorf("failed to get static users Secret %s: %w", key, err)
+		}
+		for user, password := range secret.Data {
+			users[user] = string(password)
+		}
+	}
+	return users, nil
+}
+
+// staticUsersHash returns the hash of the static users recorded in status
+func staticUsersHash(key []byte, users map[string]string) string {
+	if len(users) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(users))
+	for user := range users {
+		names = append(names, user)
+	}
+	slices.Sort(names)
+	parts := make([][]byte, 0, 2*len(names))
+	for _, user := range names {
+		parts = append(parts, []byte(user), []byte(users[user]))
+	}
+	return keyedHash(key, parts...)
+}
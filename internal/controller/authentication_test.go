@@ -0,0 +1,68 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	authv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/authentication/v1alpha1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestResolveAuthentication_Rejected(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = authv1alpha1.AddToScheme(s)
+	_ = dorisv1alpha1.AddToScheme(s)
+
+	instance := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testClusterName, Namespace: testClusterNamespace, Generation: 1},
+		Spec: dorisv1alpha1.DorisClusterSpec{
+			ClusterConfig: &dorisv1alpha1.ClusterConfigSpec{
+				Authentication: []dorisv1alpha1.AuthenticationSpec{{AuthenticationClass: "missing"}},
+			},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(instance).WithStatusSubresource(instance).Build()
+	r := &DorisClusterReconciler{Client: cli, Scheme: s}
+
+	_, supported, err := r.resolveAuthentication(ctx, instance)
+	if err != nil {
+		t.Fatalf("resolveAuthentication() error = %v", err)
+	}
+	if supported {
+		t.Error("expected an unresolvable AuthenticationClass to be rejected")
+	}
+	condition := apimeta.FindStatusCondition(instance.Status.Conditions, authenticationConditionType)
+	if condition == nil || condition.Reason != authenticationReasonRejected || !strings.Contains(condition.Message, "missing") {
+		t.Errorf("Authentication condition = %v, want the missing class rejected", condition)
+	}
+
+	instance.Spec.ClusterConfig.Authentication = nil
+	if _, supported, err := r.resolveAuthentication(ctx, instance); err != nil || !supported {
+		t.Errorf("resolveAuthentication() without classes = %t, %v, want supported", supported, err)
+	}
+}
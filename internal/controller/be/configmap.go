@@ -16,6 +16,14 @@ import (
 // BEConfigMapBuilder implements common.ConfigMapComponentBuilder
 type BEConfigMapBuilder struct {
 	*builder.ConfigMapBuilder
+	storageVolumes []dorisv1alpha1.StorageVolumeSpec
+	tlsSpec        *dorisv1alpha1.TLSSpec
+
+	// arrowFlightPort is the Arrow Flight SQL port, 0 when disabled
+	arrowFlightPort int32
+
+	// advertiseListener advertises the Arrow Flight endpoint of the pod listener Service
+	advertiseListener bool
 }
 
 func NewBEConfigMapReconciler(
@@ -24,6 +32,7 @@ func NewBEConfigMapReconciler(
 	roleGroupInfo *reconciler.RoleGroupInfo,
 	overrides *commonsv1alpha1.OverridesSpec,
 	roleConfig *commonsv1alpha1.RoleGroupConfigSpec,
+	storageVolumes []dorisv1alpha1.StorageVolumeSpec,
 	dorisCluster *dorisv1alpha1.DorisCluster,
 ) reconciler.ResourceReconciler[builder.ConfigBuilder] {
 	beBuilder := &BEConfigMapBuilder{
@@ -34,6 +43,11 @@ func NewBEConfigMapReconciler(
 				o.Labels = roleGroupInfo.GetLabels()
 				o.Annotations = roleGroupInfo.GetAnnotations()
 			}),
+		storageVolumes: storageVolumes,
+		tlsSpec:        common.GetTLSSpec(dorisCluster),
+
+		arrowFlightPort:   common.GetArrowFlightPort(dorisCluster, constants.ComponentTypeBE),
+		advertiseListener: common.IsExternal(common.GetListenerClass(dorisCluster.Spec.Backend)),
 	}
 	commonBuilder := common.NewConfigMapBuilder(
 		ctx,
@@ -65,14 +79,34 @@ func (b *BEConfigMapBuilder) BuildConfig(ctx context.Context) (map[string]string
 		"webserver_port=8040",
 		"heartbeat_service_port=9050",
 		"brpc_port=8060",
-		"arrow_flight_sql_port=-1",
-		"enable_https=false",
-		"ssl_certificate_path=\"$DORIS_HOME/conf/cert.pem\"",
-		"ssl_private_key_path=\"$DORIS_HOME/conf/key.pem\"",
+		"arrow_flight_sql_port=" + common.ArrowFlightPortConfig(b.arrowFlightPort),
 		"sys_log_level=INFO",
 		"aws_log_level=0",
 		"AWS_EC2_METADATA_DISABLED=true",
 	}
+	if b.arrowFlightPort != 0 && b.advertiseListener {
+		// Set by the start command from the endpoint resolved by the operator, BE expands
+		// environment variables in be.conf
+		beConfig = append(beConfig,
+			"public_host=${"+constants.ArrowFlightPublicHostEnvVar+"}",
+			"arrow_flight_sql_proxy_port=${"+constants.ArrowFlightProxyPortEnvVar+"}",
+		)
+	}
+	// The webserver serves HTTPS with the PEM certificate issued by the secret-operator
+	if b.tlsSpec != nil {
+		beConfig = append(beConfig,
+			"enable_https=true",
+			"ssl_certificate_path="+constants.TLSCertPath,
+			"ssl_private_key_path="+constants.TLSKeyPath,
+		)
+	} else {
+		beConfig = append(beConfig, "enable_https=false")
+	}
+	// Without explicit storage volumes BE falls back to its default storage root,
+	// which is where the single storage PVC is mounted.
+	if storageRootPath := buildStorageRootPath(b.storageVolumes); storageRootPath != "" {
+		beConfig = append(beConfig, "storage_root_path="+storageRootPath)
+	}
 	configs["be.conf"] = strings.Join(beConfig, "\n")
 	return configs, nil
 }
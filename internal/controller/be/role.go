@@ -10,6 +10,7 @@ import (
 	"github.com/zncdatadev/operator-go/pkg/client"
 	"github.com/zncdatadev/operator-go/pkg/reconciler"
 	opgoutil "github.com/zncdatadev/operator-go/pkg/util"
+	"k8s.io/utils/ptr"
 )
 
 // BEReconciler is the reconciler for BE component and implements ComponentReconciler interface
@@ -81,10 +82,18 @@ func (r *BEReconciler) RegisterResourceWithRoleGroup(
 		roleGroupInfo,
 		overrides,
 		roleGroupConfig,
+		getStorageVolumes(config),
 		r.DorisCluster,
 	)
 	reconcilers = append(reconcilers, configMapRec)
 
+	// Expose each BE for Stream Load redirects and Arrow Flight when the backend role is external
+	if listenerClass := common.GetListenerClass(r.DorisCluster.Spec.Backend); common.IsExternal(listenerClass) {
+		reconcilers = append(reconcilers,
+			NewBEPodListenerReconcilers(r.client, roleGroupInfo, ptr.Deref(replicas, 1), listenerClass,
+				common.GetArrowFlightPort(r.DorisCluster, constants.ComponentTypeBE))...)
+	}
+
 	return reconcilers, nil
 }
 
@@ -100,7 +109,8 @@ func (r *BEReconciler) CreateServiceReconcilers(
 	reconcilers = append(reconcilers, internalSvc)
 
 	// Create access service
-	accessSvc := NewBEAccessServiceReconciler(client, roleGroupInfo)
+	accessSvc := NewBEAccessServiceReconciler(client, roleGroupInfo,
+		common.GetArrowFlightPort(r.DorisCluster, constants.ComponentTypeBE))
 	reconcilers = append(reconcilers, accessSvc)
 
 	return reconcilers
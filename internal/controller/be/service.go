@@ -1,16 +1,22 @@
 package be
 
 import (
+	"fmt"
+	"maps"
+
 	"github.com/zncdatadev/doris-operator/internal/controller/common"
 	"github.com/zncdatadev/doris-operator/internal/controller/constants"
 	"github.com/zncdatadev/operator-go/pkg/builder"
 	"github.com/zncdatadev/operator-go/pkg/client"
+	opconstants "github.com/zncdatadev/operator-go/pkg/constants"
 	"github.com/zncdatadev/operator-go/pkg/reconciler"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 )
 
-// GetBEServiceConfig returns the default service configuration for BE
-func GetBEServiceConfig() *common.ComponentServiceConfig {
+// GetBEServiceConfig returns the default service configuration for BE.
+// The access service also exposes the Arrow Flight port unless it is 0.
+func GetBEServiceConfig(arrowFlightPort int32) *common.ComponentServiceConfig {
 	// Define the BE container ports - use the same names as in the StatefulSet!
 	beHeartbeatPort := corev1.ContainerPort{
 		Name:          constants.BEHeartbeatPortName, // Use constant port name
@@ -40,6 +46,9 @@ func GetBEServiceConfig() *common.ComponentServiceConfig {
 			Protocol:      corev1.ProtocolTCP,
 		},
 	}
+	if arrowFlightPort != 0 {
+		accessPorts = append(accessPorts, arrowFlightContainerPort(arrowFlightPort))
+	}
 
 	return &common.ComponentServiceConfig{
 		ComponentType: constants.ComponentTypeBE,
@@ -54,7 +63,7 @@ func NewBEInternalServiceReconciler(
 	roleGroupInfo *reconciler.RoleGroupInfo,
 ) reconciler.ResourceReconciler[builder.ServiceBuilder] {
 	// Use BE service configuration
-	beServiceConfig := GetBEServiceConfig()
+	beServiceConfig := GetBEServiceConfig(0)
 
 	// Create internal service using the common implementation
 	return common.NewInternalServiceReconciler(client, roleGroupInfo, beServiceConfig)
@@ -64,10 +73,71 @@ func NewBEInternalServiceReconciler(
 func NewBEAccessServiceReconciler(
 	client *client.Client,
 	roleGroupInfo *reconciler.RoleGroupInfo,
+	arrowFlightPort int32,
 ) reconciler.ResourceReconciler[builder.ServiceBuilder] {
 	// Use BE service configuration
-	beServiceConfig := GetBEServiceConfig()
+	beServiceConfig := GetBEServiceConfig(arrowFlightPort)
 
 	// Create access service using the common implementation
 	return common.NewAccessServiceReconciler(client, roleGroupInfo, beServiceConfig)
 }
+
+// PodListenerServiceName returns the name of the Service exposing a single BE pod
+func PodListenerServiceName(podName string) string {
+	return podName + constants.ServiceListenerSuffix
+}
+
+// NewBEPodListenerReconcilers creates a Service per BE pod exposing its HTTP port and Arrow
+// Flight port with the listener class, since Stream Load and Arrow Flight clients are sent
+// by FE to a specific BE.
+func NewBEPodListenerReconcilers(
+	client *client.Client,
+	roleGroupInfo *reconciler.RoleGroupInfo,
+	replicas int32,
+	listenerClass opconstants.ListenerClass,
+	arrowFlightPort int32,
+) []reconciler.Reconciler {
+	ports := []corev1.ContainerPort{
+		{
+			Name:          constants.BEHttpPortName,
+			ContainerPort: constants.BEHttpPort,
+			Protocol:      corev1.ProtocolTCP,
+		},
+	}
+	if arrowFlightPort != 0 {
+		ports = append(ports, arrowFlightContainerPort(arrowFlightPort))
+	}
+	labels := map[string]string{
+		constants.OwnerReferenceLabelKey: roleGroupInfo.ClusterName,
+		constants.ServiceRoleLabelKey:    string(common.ServiceTypeListener),
+		constants.ComponentLabelKey:      string(constants.ComponentTypeBE),
+	}
+
+	reconcilers := make([]reconciler.Reconciler, 0, replicas)
+	for i := range replicas {
+		podName := fmt.Sprintf("%s-%d", roleGroupInfo.GetFullName(), i)
+		matchingLabels := maps.Clone(roleGroupInfo.GetLabels())
+		matchingLabels[appsv1.StatefulSetPodNameLabel] = podName
+		svcBuilder := builder.NewServiceBuilder(
+			client,
+			PodListenerServiceName(podName),
+			ports,
+			func(sbo *builder.ServiceBuilderOptions) {
+				sbo.ListenerClass = listenerClass
+				sbo.Labels = labels
+				sbo.MatchingLabels = matchingLabels
+			},
+		)
+		reconcilers = append(reconcilers, reconciler.NewGenericResourceReconciler(client, svcBuilder))
+	}
+	return reconcilers
+}
+
+// arrowFlightContainerPort returns the BE Arrow Flight SQL port
+func arrowFlightContainerPort(port int32) corev1.ContainerPort {
+	return corev1.ContainerPort{
+		Name:          constants.ArrowFlightPortName,
+		ContainerPort: port,
+		Protocol:      corev1.ProtocolTCP,
+	}
+}
@@ -13,7 +13,6 @@ import (
 	opgoutil "github.com/zncdatadev/operator-go/pkg/util"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -65,6 +64,10 @@ func (b *BeStatefulSetBuilder) GetMainContainer() *corev1.Container {
 			Protocol:      corev1.ProtocolTCP,
 		},
 	}
+	arrowFlightPort := common.GetArrowFlightPort(b.GetDorisCluster(), constants.ComponentTypeBE)
+	if arrowFlightPort != 0 {
+		ports = append(ports, arrowFlightContainerPort(arrowFlightPort))
+	}
 
 	// BE specific health checks
 	livenessProbe := b.CreateTcpProbe(constants.BEHeartbeatPort, constants.DefaultInitialDelaySeconds, constants.DefaultPeriodSeconds)
@@ -111,17 +114,54 @@ func (b *BeStatefulSetBuilder) GetMainContainer() *corev1.Container {
 		readinessProbe,
 	)
 
+	// BE drains its running queries and loads before it stops
+	container.StartupProbe = b.CreateStartupProbe(livenessProbe, b.beRole, constants.BEStartupFailureThreshold)
+	container.Lifecycle = b.CreateDrainPreStopHook(constants.BEHttpPort, beDrainMetrics)
+
+	// BEs behind external listeners advertise the Arrow Flight endpoint resolved by the operator
+	if arrowFlightPort != 0 && common.IsExternal(common.GetListenerClass(b.GetDorisCluster().Spec.Backend)) {
+		container.Command = []string{"sh", "-c", arrowFlightEndpointScript}
+		container.Args = nil
+	}
+
 	// Add BE specific volume mounts
-	container.VolumeMounts = append(container.VolumeMounts,
-		corev1.VolumeMount{
-			Name:      constants.BEStorageVolume,
-			MountPath: constants.BEStoragePath,
-		},
-	)
+	if volumes := getStorageVolumes(b.beRole); len(volumes) > 0 {
+		container.VolumeMounts = append(container.VolumeMounts, getStorageVolumeMounts(volumes)...)
+	} else {
+		container.VolumeMounts = append(container.VolumeMounts,
+			corev1.VolumeMount{
+				Name:      constants.BEStorageVolume,
+				MountPath: constants.BEStoragePath,
+			},
+		)
+	}
 
 	return container
 }
 
+// beDrainMetrics are the BE metrics of the running query fragments and load channels
+const beDrainMetrics = "doris_be_fragment_instance_count|doris_be_load_channel_count"
+
+// arrowFlightEndpointScript waits for the operator to annotate the pod with the Arrow Flight
+// endpoint of its listener Service, and exports it for be.conf before starting BE.
+// BE falls back to its own address if the endpoint is not resolved in time.
+const arrowFlightEndpointScript = `endpoint=""
+for i in $(seq 1 60); do
+  endpoint=$(sed -n 's|^` + constants.ArrowFlightEndpointAnnotationKey + `="\(.*\)"$|\1|p' ` + constants.PodinfoMountPath + `/annotations)
+  [ -n "$endpoint" ] && break
+  echo "Waiting for the Arrow Flight endpoint of the pod"
+  sleep 5
+done
+if [ -n "$endpoint" ]; then
+  export ` + constants.ArrowFlightPublicHostEnvVar + `="${endpoint%:*}"
+  export ` + constants.ArrowFlightProxyPortEnvVar + `="${endpoint##*:}"
+else
+  export ` + constants.ArrowFlightPublicHostEnvVar + `=""
+  export ` + constants.ArrowFlightProxyPortEnvVar + `="-1"
+fi
+exec ` + constants.BEEntrypoint + ` "$` + constants.FEAddrEnvVar + `"
+`
+
 // GetInitContainers implements BE initialization containers
 func (b *BeStatefulSetBuilder) GetInitContainers() []corev1.Container {
 	return []corev1.Container{
@@ -157,8 +197,14 @@ func (b *BeStatefulSetBuilder) GetVolumes() []corev1.Volume {
 	}
 }
 
-// GetVolumeClaimTemplates implements ComponentInterface, returns BE storage PVCs
+// GetVolumeClaimTemplates implements ComponentInterface, returns BE storage PVCs.
+// When storage volumes are configured, one PVC is created per volume; otherwise a single
+// PVC is sized from the storage resources.
 func (b *BeStatefulSetBuilder) GetVolumeClaimTemplates() []corev1.PersistentVolumeClaim {
+	if volumes := getStorageVolumes(b.beRole); len(volumes) > 0 {
+		return getStorageVolumeClaimTemplates(volumes)
+	}
+
 	storageSize := resource.MustParse(constants.BEStorageSize)
 	var storageClassName *string
 
@@ -177,24 +223,16 @@ func (b *BeStatefulSetBuilder) GetVolumeClaimTemplates() []corev1.PersistentVolu
 	}
 
 	return []corev1.PersistentVolumeClaim{
-		{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: constants.BEStorageVolume,
-			},
-			Spec: corev1.PersistentVolumeClaimSpec{
-				VolumeMode:  ptr.To(corev1.PersistentVolumeFilesystem),
-				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
-				Resources: corev1.VolumeResourceRequirements{
-					Requests: corev1.ResourceList{
-						corev1.ResourceStorage: storageSize,
-					},
-				},
-				StorageClassName: storageClassName,
-			},
-		},
+		newStoragePVC(constants.BEStorageVolume, storageSize, storageClassName),
 	}
 }
 
+// VolumeClaimTemplates returns the desired BE PVC templates for a merged role group config.
+// It is used to detect storage changes on StatefulSets that already exist.
+func VolumeClaimTemplates(config *dorisv1alpha1.ConfigSpec) []corev1.PersistentVolumeClaim {
+	return NewBeStatefulSetBuilder(nil, config).GetVolumeClaimTemplates()
+}
+
 // GetAdditionalEnvVars implements ComponentInterface, returns BE specific environment variables
 func (b *BeStatefulSetBuilder) GetAdditionalEnvVars() []corev1.EnvVar {
 	// BE component doesn't need additional environment variables
@@ -0,0 +1,112 @@
+package be
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// getStorageVolumes returns the BE storage volumes of a role group.
+// Role and role group configs are merged by appending slices, so entries with
+// the same name are collapsed here: the last definition wins, the first position is kept.
+func getStorageVolumes(config *dorisv1alpha1.ConfigSpec) []dorisv1alpha1.StorageVolumeSpec {
+	if config == nil || len(config.StorageVolumes) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int, len(config.StorageVolumes))
+	volumes := make([]dorisv1alpha1.StorageVolumeSpec, 0, len(config.StorageVolumes))
+	for _, v := range config.StorageVolumes {
+		if i, ok := index[v.Name]; ok {
+			volumes[i] = v
+			continue
+		}
+		index[v.Name] = len(volumes)
+		volumes = append(volumes, v)
+	}
+	return volumes
+}
+
+// storageVolumeName returns the PVC template name of a BE storage volume
+func storageVolumeName(volume dorisv1alpha1.StorageVolumeSpec) string {
+	return constants.BEStorageVolume + "-" + volume.Name
+}
+
+// storageVolumePath returns the mount path of a BE storage volume
+func storageVolumePath(volume dorisv1alpha1.StorageVolumeSpec) string {
+	return path.Join(constants.BEStoragePath, volume.Name)
+}
+
+// storageVolumeMedium returns the Doris storage medium of a volume, HDD by default
+func storageVolumeMedium(volume dorisv1alpha1.StorageVolumeSpec) string {
+	if volume.Medium == "" {
+		return constants.BEStorageMediumHDD
+	}
+	return volume.Medium
+}
+
+// getStorageVolumeMounts returns the volume mounts of the BE storage volumes
+func getStorageVolumeMounts(volumes []dorisv1alpha1.StorageVolumeSpec) []corev1.VolumeMount {
+	mounts := make([]corev1.VolumeMount, 0, len(volumes))
+	for _, v := range volumes {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      storageVolumeName(v),
+			MountPath: storageVolumePath(v),
+		})
+	}
+	return mounts
+}
+
+// getStorageVolumeClaimTemplates returns one PVC template per BE storage volume
+func getStorageVolumeClaimTemplates(volumes []dorisv1alpha1.StorageVolumeSpec) []corev1.PersistentVolumeClaim {
+	pvcs := make([]corev1.PersistentVolumeClaim, 0, len(volumes))
+	for _, v := range volumes {
+		capacity := v.Capacity
+		if capacity.IsZero() {
+			capacity = resource.MustParse(constants.BEStorageSize)
+		}
+		var storageClassName *string
+		if v.StorageClass != "" {
+			storageClassName = ptr.To(v.StorageClass)
+		}
+		pvcs = append(pvcs, newStoragePVC(storageVolumeName(v), capacity, storageClassName))
+	}
+	return pvcs
+}
+
+// newStoragePVC creates a ReadWriteOnce filesystem PVC template
+func newStoragePVC(name string, capacity resource.Quantity, storageClassName *string) corev1.PersistentVolumeClaim {
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			VolumeMode:  ptr.To(corev1.PersistentVolumeFilesystem),
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: capacity,
+				},
+			},
+			StorageClassName: storageClassName,
+		},
+	}
+}
+
+// buildStorageRootPath renders the be.conf `storage_root_path` value,
+// e.g. `/opt/apache-doris/be/storage/nvme,medium:SSD;/opt/apache-doris/be/storage/hdd,medium:HDD`.
+// It returns an empty string when no storage volumes are configured.
+func buildStorageRootPath(volumes []dorisv1alpha1.StorageVolumeSpec) string {
+	paths := make([]string, 0, len(volumes))
+	for _, v := range volumes {
+		paths = append(paths, fmt.Sprintf("%s,medium:%s", storageVolumePath(v), storageVolumeMedium(v)))
+	}
+	return strings.Join(paths, ";")
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package be
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+)
+
+func TestGetStorageVolumes_LastDefinitionWins(t *testing.T) {
+	config := &dorisv1alpha1.ConfigSpec{
+		StorageVolumes: []dorisv1alpha1.StorageVolumeSpec{
+			{Name: "nvme", Capacity: resource.MustParse("100Gi"), Medium: "SSD"},
+			{Name: "hdd", Capacity: resource.MustParse("1Ti"), Medium: "HDD"},
+			// role group override of the role-level "nvme" entry
+			{Name: "nvme", Capacity: resource.MustParse("200Gi"), Medium: "SSD"},
+		},
+	}
+
+	volumes := getStorageVolumes(config)
+	if len(volumes) != 2 {
+		t.Fatalf("expected 2 volumes, got %d", len(volumes))
+	}
+	if volumes[0].Name != "nvme" || volumes[1].Name != "hdd" {
+		t.Errorf("unexpected volume order: %s, %s", volumes[0].Name, volumes[1].Name)
+	}
+	if !volumes[0].Capacity.Equal(resource.MustParse("200Gi")) {
+		t.Errorf("expected nvme capacity 200Gi, got %s", volumes[0].Capacity.String())
+	}
+
+	if getStorageVolumes(nil) != nil {
+		t.Error("expected no volumes for nil config")
+	}
+}
+
+func TestBuildStorageRootPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		volumes []dorisv1alpha1.StorageVolumeSpec
+		want    string
+	}{
+		{
+			name: "no volumes",
+			want: "",
+		},
+		{
+			name: "mixed mediums",
+			volumes: []dorisv1alpha1.StorageVolumeSpec{
+				{Name: "nvme", Medium: "SSD"},
+				{Name: "hdd", Medium: "HDD"},
+			},
+			want: "/opt/apache-doris/be/storage/nvme,medium:SSD;/opt/apache-doris/be/storage/hdd,medium:HDD",
+		},
+		{
+			name: "medium defaults to HDD",
+			volumes: []dorisv1alpha1.StorageVolumeSpec{
+				{Name: "data"},
+			},
+			want: "/opt/apache-doris/be/storage/data,medium:HDD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildStorageRootPath(tt.volumes); got != tt.want {
+				t.Errorf("buildStorageRootPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetStorageVolumeClaimTemplates(t *testing.T) {
+	volumes := []dorisv1alpha1.StorageVolumeSpec{
+		{Name: "nvme", Capacity: resource.MustParse("100Gi"), StorageClass: "local-nvme"},
+		{Name: "hdd"},
+	}
+
+	pvcs := getStorageVolumeClaimTemplates(volumes)
+	if len(pvcs) != 2 {
+		t.Fatalf("expected 2 PVCs, got %d", len(pvcs))
+	}
+
+	if pvcs[0].Name != "be-storage-nvme" {
+		t.Errorf("unexpected PVC name %q", pvcs[0].Name)
+	}
+	if pvcs[0].Spec.StorageClassName == nil || *pvcs[0].Spec.StorageClassName != "local-nvme" {
+		t.Errorf("expected storage class local-nvme, got %v", pvcs[0].Spec.StorageClassName)
+	}
+
+	size := pvcs[1].Spec.Resources.Requests[corev1.ResourceStorage]
+	if !size.Equal(resource.MustParse("20Gi")) {
+		t.Errorf("expected default capacity 20Gi, got %s", size.String())
+	}
+	if pvcs[1].Spec.StorageClassName != nil {
+		t.Errorf("expected default storage class, got %q", *pvcs[1].Spec.StorageClassName)
+	}
+}
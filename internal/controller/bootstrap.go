@@ -0,0 +1,217 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	"github.com/zncdatadev/doris-operator/internal/controller/storage"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// rootPasswordKey is the key of the root password in the root password Secret
+	rootPasswordKey = "password"
+
+	// rootPasswordLength is the length of generated root passwords
+	rootPasswordLength = 32
+
+	rootPasswordAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// bootstrapResult records the bootstrap steps completed during a reconcile
+type bootstrapResult struct {
+	// adminUser is true when the authSecret admin user was created
+	adminUser bool
+	// rootPassword is true when the root password was set
+	rootPassword bool
+}
+
+// rootPasswordSecretName returns the Secret holding the root password: the configured
+// rootPasswordSecret, or the Secret generated by the operator.
+func rootPasswordSecretName(instance *dorisv1alpha1.DorisCluster) string {
+	if instance.Spec.RootPasswordSecret != nil && instance.Spec.RootPasswordSecret.SecretName != "" {
+		return instance.Spec.RootPasswordSecret.SecretName
+	}
+	return instance.Name + "-root-password"
+}
+
+// ensureRootPasswordSecret generates the root password Secret when no rootPasswordSecret
+// is configured. The generated Secret is only owned by the cluster when its PVCs are deleted
+// with it; with retained PVCs the password is kept to reach the retained FE metadata again.
+func (r *DorisClusterReconciler) ensureRootPasswordSecret(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) error {
+	if instance.Spec.RootPasswordSecret != nil {
+		return nil
+	}
+
+	name := rootPasswordSecretName(instance)
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: instance.Namespace}, &corev1.Secret{})
+	if err == nil || !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	password, err := generatePassword(rootPasswordLength)
+	if err != nil {
+		return err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.Namespace,
+			Labels:    map[string]string{opgpconstants.LabelKubernetesInstance: instance.Name},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{rootPasswordKey: []byte(password)},
+	}
+	if storage.GetWhenDeleted(&instance.Spec) == storage.RetentionPolicyDelete {
+		if err := controllerutil.SetControllerReference(instance, secret, r.Scheme); err != nil {
+			return err
+		}
+	}
+	if err := r.Create(ctx, secret); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create root password Secret %s: %w", name, err)
+	}
+	logger.Info("Generated root password Secret", "cluster", instance.Name, "secret", name)
+	return nil
+}
+
+// getRootPassword returns the root password and the resourceVersion of its Secret.
+func (r *DorisClusterReconciler) getRootPassword(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (string, string, error) {
+	name := rootPasswordSecretName(instance)
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: instance.Namespace}, secret); err != nil {
+		return "", "", fmt.Errorf("failed to get root password Secret %s: %w", name, err)
+	}
+	password := string(secret.Data[rootPasswordKey])
+	if password == "" {
+		return "", "", fmt.Errorf("root password Secret %s has no %q", name, rootPasswordKey)
+	}
+	return password, secret.ResourceVersion, nil
+}
+
+// rootClusterTarget returns the target logging in as root with the root password.
+// The authSecret password is the root password when the admin user is root.
+func (r *DorisClusterReconciler) rootClusterTarget(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	target doris_client.ClusterTarget,
+) (doris_client.ClusterTarget, error) {
+	if target.User == doris_client.DefaultAdminUser {
+		return target, nil
+	}
+	password, version, err := r.getRootPassword(ctx, instance)
+	if err != nil {
+		return target, err
+	}
+	rootTarget := target
+	rootTarget.User, rootTarget.Password = doris_client.DefaultAdminUser, password
+	rootTarget.CredentialVersion = version + "/" + target.CredentialVersion
+	return rootTarget, nil
+}
+
+// connectRoot connects to Doris FE as root. Until the root password is set root is reached
+// with an empty password; the root password is tried as well, in case it was set before the
+// status was recorded. It returns whether the root password is set.
+func (r *DorisClusterReconciler) connectRoot(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	rootTarget doris_client.ClusterTarget,
+) (*doris_client.DorisClient, bool, error) {
+	if !instance.Status.RootPasswordInitialized {
+		initialTarget := rootTarget
+		initialTarget.Password = ""
+		initialTarget.CredentialVersion = "initial/" + rootTarget.CredentialVersion
+		if c, err := r.DorisClients.Get(ctx, initialTarget); err == nil {
+			return c, false, nil
+		}
+	}
+	c, err := r.DorisClients.Get(ctx, rootTarget)
+	if err != nil {
+		return nil, false, err
+	}
+	return c, true, nil
+}
+
+// bootstrapAuth connects as root to create the admin user of the authSecret, then sets
+// the root password. It returns false when the FE is not reachable yet.
+func (r *DorisClusterReconciler) bootstrapAuth(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	target doris_client.ClusterTarget,
+) (bootstrapResult, bool, error) {
+	result := bootstrapResult{}
+
+	rootTarget, err := r.rootClusterTarget(ctx, instance, target)
+	if err != nil {
+		return result, false, err
+	}
+	rootPassword := rootTarget.Password
+	if target.User != doris_client.DefaultAdminUser {
+		// The root client is only needed once
+		defer r.DorisClients.Release(instance.UID, doris_client.DefaultAdminUser)
+	}
+
+	rootClient, passwordSet, err := r.connectRoot(ctx, instance, rootTarget)
+	if err != nil {
+		logger.Info("Failed to connect to Doris FE as root for auth bootstrap",
+			"host", target.ServiceHost, "error", err)
+		return result, false, nil
+	}
+	defer r.DorisClients.Put(rootClient)
+
+	if instance.Spec.AuthSecret != nil && !instance.Status.AuthInitialized {
+		exists, err := rootClient.CheckUserExists(ctx, target.User)
+		if err != nil {
+			return result, false, err
+		}
+		if !exists {
+			if err := rootClient.InitializeAdminUser(ctx, target.User, target.Password); err != nil {
+				return result, false, err
+			}
+		} else if err := rootClient.AlterUserPassword(ctx, target.User, target.Password); err != nil {
+			// The user was created outside the operator: adopt it with the authSecret password
+			return result, false, err
+		}
+		result.adminUser = true
+	}
+
+	if !instance.Status.RootPasswordInitialized {
+		if !passwordSet {
+			if err := rootClient.SetUserPassword(ctx, doris_client.DefaultAdminUser, rootPassword); err != nil {
+				return result, false, err
+			}
+			// The empty-password connection must not be reused
+			r.DorisClients.Release(instance.UID, doris_client.DefaultAdminUser)
+		}
+		result.rootPassword = true
+	}
+	return result, true, nil
+}
+
+// generatePassword returns a random alphanumeric password
+func generatePassword(length int) (string, error) {
+	password := make([]byte, length)
+	alphabetSize := big.NewInt(int64(len(rootPasswordAlphabet)))
+	for i := range password {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		password[i] = rootPasswordAlphabet[n.Int64()]
+	}
+	return string(password), nil
+}
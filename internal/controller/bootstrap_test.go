@@ -0,0 +1,129 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/storage"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEnsureRootPasswordSecret(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = dorisv1alpha1.AddToScheme(s)
+
+	tests := []struct {
+		name        string
+		whenDeleted string
+		wantOwner   bool
+	}{
+		{name: "retained storage keeps the password", whenDeleted: storage.RetentionPolicyRetain, wantOwner: false},
+		{name: "deleted storage deletes the password", whenDeleted: storage.RetentionPolicyDelete, wantOwner: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := &dorisv1alpha1.DorisCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: testClusterName, Namespace: testClusterNamespace, UID: "uid"},
+				Spec: dorisv1alpha1.DorisClusterSpec{
+					ClusterConfig: &dorisv1alpha1.ClusterConfigSpec{
+						PersistentVolumeClaimRetentionPolicy: &dorisv1alpha1.PersistentVolumeClaimRetentionPolicySpec{
+							WhenDeleted: tt.whenDeleted,
+						},
+					},
+				},
+			}
+			cli := fake.NewClientBuilder().WithScheme(s).WithObjects(instance).Build()
+			r := &DorisClusterReconciler{Client: cli, Scheme: s}
+
+			if err := r.ensureRootPasswordSecret(ctx, instance); err != nil {
+				t.Fatalf("ensureRootPasswordSecret() error = %v", err)
+			}
+			password, _, err := r.getRootPassword(ctx, instance)
+			if err != nil {
+				t.Fatalf("getRootPassword() error = %v", err)
+			}
+			if len(password) != rootPasswordLength {
+				t.Errorf("generated password length = %d, want %d", len(password), rootPasswordLength)
+			}
+
+			secret := &corev1.Secret{}
+			key := types.NamespacedName{Name: testClusterName + "-root-password", Namespace: testClusterNamespace}
+			if err := cli.Get(ctx, key, secret); err != nil {
+				t.Fatalf("generated Secret not found: %v", err)
+			}
+			if got := len(secret.OwnerReferences) > 0; got != tt.wantOwner {
+				t.Errorf("Secret owned = %v, want %v", got, tt.wantOwner)
+			}
+
+			// The password is generated once
+			if err := r.ensureRootPasswordSecret(ctx, instance); err != nil {
+				t.Fatalf("ensureRootPasswordSecret() error = %v", err)
+			}
+			again, _, _ := r.getRootPassword(ctx, instance)
+			if again != password {
+				t.Errorf("password regenerated")
+			}
+		})
+	}
+}
+
+func TestGetRootPassword_ConfiguredSecret(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = dorisv1alpha1.AddToScheme(s)
+
+	instance := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testClusterName, Namespace: testClusterNamespace},
+		Spec: dorisv1alpha1.DorisClusterSpec{
+			RootPasswordSecret: &dorisv1alpha1.RootPasswordSecretSpec{SecretName: "root"},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "root", Namespace: testClusterNamespace},
+		Data:       map[string][]byte{rootPasswordKey: []byte("s3cret")},
+	}
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(instance, secret).Build()
+	r := &DorisClusterReconciler{Client: cli, Scheme: s}
+
+	if err := r.ensureRootPasswordSecret(ctx, instance); err != nil {
+		t.Fatalf("ensureRootPasswordSecret() error = %v", err)
+	}
+	password, _, err := r.getRootPassword(ctx, instance)
+	if err != nil {
+		t.Fatalf("getRootPassword() error = %v", err)
+	}
+	if password != "s3cret" {
+		t.Errorf("password = %q, want the configured password", password)
+	}
+	if err := cli.Get(ctx, types.NamespacedName{Name: testClusterName + "-root-password", Namespace: testClusterNamespace}, &corev1.Secret{}); err == nil {
+		t.Errorf("no Secret should be generated when rootPasswordSecret is configured")
+	}
+}
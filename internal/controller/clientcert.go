@@ -0,0 +1,329 @@
+package controller
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	"github.com/zncdatadev/doris-operator/internal/controller/fe"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// clientCertLifetime is the validity of the operator client certificate
+	clientCertLifetime = 365 * 24 * time.Hour
+
+	// clientCertRenewBefore is how long before expiry the operator client certificate is renewed
+	clientCertRenewBefore = 30 * 24 * time.Hour
+
+	// operatorClientCALifetime is the validity of the CA of the operator client certificate.
+	// FE only loads the CA at startup, so it is not rotated.
+	operatorClientCALifetime = 10 * 365 * 24 * time.Hour
+
+	// operatorClientCommonName is the common name of the certificate the operator connects with
+	operatorClientCommonName = "doris-operator"
+)
+
+// clientCertLabel marks the client certificate Secrets of the operator, with the common name
+// of the certificate or `ca`
+var clientCertLabel = dorisv1alpha1.GroupVersion.Group + "/client-cert"
+
+// clientCA is the CA issuing client certificates
+type clientCA struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+	pem  []byte
+}
+
+// operatorClientCertSecretName returns the Secret holding the client certificate of the operator
+func operatorClientCertSecretName(instance *dorisv1alpha1.DorisCluster) string {
+	return instance.Name + "-operator-client-cert"
+}
+
+// reconcileClientCertificates issues the client certificate the operator presents when FE
+// requires client certificates, and deletes it otherwise. The certificate is issued by a CA
+// of the operator, which FE trusts besides the CA of the clientCertSecretClass: the clients
+// of users get their certificates from secret-operator volumes of that SecretClass.
+func (r *DorisClusterReconciler) reconcileClientCertificates(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	auth *fe.Authentication,
+) error {
+	wanted := map[string]bool{}
+	if auth.ClientAuthEnabled(instance) {
+		wanted[fe.OperatorClientCASecretName(instance.Name)] = true
+		wanted[operatorClientCertSecretName(instance)] = true
+	}
+	if err := r.deleteStaleClientCertificates(ctx, instance, wanted); err != nil {
+		return err
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	ca, err := r.ensureOperatorClientCA(ctx, instance)
+	if err != nil {
+		return err
+	}
+	return r.ensureClientCertSecret(ctx, instance, operatorClientCertSecretName(instance), operatorClientCommonName, ca)
+}
+
+// getOperatorClientCertificate returns the client certificate of the operator and the version
+// of its Secret, or nil when FE does not require client certificates.
+func (r *DorisClusterReconciler) getOperatorClientCertificate(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (*tls.Certificate, string, error) {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Name: operatorClientCertSecretName(instance), Namespace: instance.Namespace}
+	if err := r.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("failed to get operator client certificate %s: %w", key, err)
+	}
+	cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid operator client certificate %s: %w", key, err)
+	}
+	return &cert, secret.ResourceVersion, nil
+}
+
+// deleteStaleClientCertificates deletes the client certificate Secrets of the cluster not wanted anymore
+func (r *DorisClusterReconciler) deleteStaleClientCertificates(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	wanted map[string]bool,
+) error {
+	secrets := &corev1.SecretList{}
+	if err := r.List(ctx, secrets, ctrlclient.InNamespace(instance.Namespace),
+		ctrlclient.MatchingLabels{opgpconstants.LabelKubernetesInstance: instance.Name},
+		ctrlclient.HasLabels{clientCertLabel}); err != nil {
+		return fmt.Errorf("failed to list client certificate Secrets: %w", err)
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if wanted[secret.Name] {
+			continue
+		}
+		if err := r.Delete(ctx, secret); ctrlclient.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete client certificate Secret %s: %w", secret.Name, err)
+		}
+		logger.Info("Deleted client certificate Secret", "cluster", instance.Name, "secret", secret.Name)
+	}
+	return nil
+}
+
+// ensureOperatorClientCA returns the CA of the operator client certificate, generating it
+// when missing.
+func (r *DorisClusterReconciler) ensureOperatorClientCA(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (*clientCA, error) {
+	name := fe.OperatorClientCASecretName(instance.Name)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: instance.Namespace},
+	}
+	var ca *clientCA
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels[opgpconstants.LabelKubernetesInstance] = instance.Name
+		secret.Labels[clientCertLabel] = "ca"
+
+		var err error
+		if ca, err = parseClientCA(secret.Data[constants.TLSCACertKey], secret.Data[constants.TLSCAKeyKey]); err != nil {
+			certPEM, keyPEM, err := newClientCA(instance.Name+"-operator-client-ca", time.Now())
+			if err != nil {
+				return err
+			}
+			if ca, err = parseClientCA(certPEM, keyPEM); err != nil {
+				return err
+			}
+			secret.Data = map[string][]byte{
+				constants.TLSCACertKey: certPEM,
+				constants.TLSCAKeyKey:  keyPEM,
+			}
+			logger.Info("Generated the CA of the operator client certificate", "cluster", instance.Name, "secret", name)
+		}
+		return controllerutil.SetControllerReference(instance, secret, r.Scheme)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to reconcile operator client CA Secret %s: %w", name, err)
+	}
+	return ca, nil
+}
+
+// ensureClientCertSecret creates or renews the Secret holding a client certificate issued by the CA.
+// The certificate is kept until it comes close to expiry or was issued by another CA.
+func (r *DorisClusterReconciler) ensureClientCertSecret(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	name, commonName string,
+	ca *clientCA,
+) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: instance.Namespace},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels[opgpconstants.LabelKubernetesInstance] = instance.Name
+		secret.Labels[clientCertLabel] = commonName
+		secret.Type = corev1.SecretTypeTLS
+		if !clientCertValid(secret.Data[corev1.TLSCertKey], commonName, ca, time.Now()) {
+			certPEM, keyPEM, err := issueClientCertificate(ca, commonName, time.Now())
+			if err != nil {
+				return err
+			}
+			secret.Data = map[string][]byte{
+				corev1.TLSCertKey:       certPEM,
+				corev1.TLSPrivateKeyKey: keyPEM,
+				constants.TLSCACertKey:  ca.pem,
+			}
+			logger.Info("Issued client certificate", "cluster", instance.Name, "secret", name, "commonName", commonName)
+		}
+		return controllerutil.SetControllerReference(instance, secret, r.Scheme)
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile client certificate Secret %s: %w", name, err)
+	}
+	return nil
+}
+
+// newClientCA generates a self-signed CA, returning its PEM encoded certificate and private key
+func newClientCA(commonName string, now time.Time) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.Add(operatorClientCALifetime),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate client CA: %w", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// parseClientCA parses a PEM encoded CA certificate and private key
+func parseClientCA(certPEM, keyPEM []byte) (*clientCA, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, errors.New("no CA certificate found")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA certificate: %w", err)
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, errors.New("no CA private key found")
+	}
+	var key any
+	switch keyBlock.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(keyBlock.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA private key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("CA private key cannot sign")
+	}
+	return &clientCA{cert: cert, key: signer, pem: certPEM}, nil
+}
+
+// issueClientCertificate issues a client certificate for the common name
+func issueClientCertificate(ca *clientCA, commonName string, now time.Time) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	notAfter := now.Add(clientCertLifetime)
+	if notAfter.After(ca.cert.NotAfter) {
+		notAfter = ca.cert.NotAfter
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to issue client certificate for %s: %w", commonName, err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// clientCertValid reports whether a PEM encoded client certificate can be kept: issued by
+// the CA for the common name, and not close to expiry.
+func clientCertValid(certPEM []byte, commonName string, ca *clientCA, now time.Time) bool {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false
+	}
+	if cert.Subject.CommonName != commonName || cert.CheckSignatureFrom(ca.cert) != nil {
+		return false
+	}
+	// A certificate expiring with the CA cannot be renewed for longer
+	if !cert.NotAfter.Before(ca.cert.NotAfter) {
+		return now.Before(cert.NotAfter)
+	}
+	return now.Add(clientCertRenewBefore).Before(cert.NotAfter)
+}
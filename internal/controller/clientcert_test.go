@@ -0,0 +1,182 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/fe"
+	authv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/authentication/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestClientCA(t *testing.T, notAfter time.Time) *clientCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := parseClientCA(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	)
+	if err != nil {
+		t.Fatalf("parseClientCA() error = %v", err)
+	}
+	return ca
+}
+
+func TestIssueClientCertificate(t *testing.T) {
+	now := time.Now()
+	ca := newTestClientCA(t, now.Add(10*365*24*time.Hour))
+
+	certPEM, keyPEM, err := issueClientCertificate(ca, "alice", now)
+	if err != nil {
+		t.Fatalf("issueClientCertificate() error = %v", err)
+	}
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		t.Errorf("issued key pair is invalid: %v", err)
+	}
+
+	if !clientCertValid(certPEM, "alice", ca, now) {
+		t.Error("clientCertValid() = false for a fresh certificate")
+	}
+	if clientCertValid(certPEM, "bob", ca, now) {
+		t.Error("clientCertValid() = true for another user")
+	}
+	if clientCertValid(certPEM, "alice", ca, now.Add(clientCertLifetime-clientCertRenewBefore/2)) {
+		t.Error("clientCertValid() = true for a certificate due for renewal")
+	}
+	if clientCertValid(certPEM, "alice", newTestClientCA(t, now.Add(time.Hour*24*365)), now) {
+		t.Error("clientCertValid() = true for a certificate of another CA")
+	}
+}
+
+func TestIssueClientCertificate_ShortLivedCA(t *testing.T) {
+	now := time.Now()
+	ca := newTestClientCA(t, now.Add(7*24*time.Hour))
+
+	certPEM, _, err := issueClientCertificate(ca, "alice", now)
+	if err != nil {
+		t.Fatalf("issueClientCertificate() error = %v", err)
+	}
+	block, _ := pem.Decode(certPEM)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.NotAfter.After(ca.cert.NotAfter) {
+		t.Errorf("certificate expires at %v, after its CA at %v", cert.NotAfter, ca.cert.NotAfter)
+	}
+	// Reissuing would not extend the certificate, so it is kept until it expires
+	if !clientCertValid(certPEM, "alice", ca, now) {
+		t.Error("clientCertValid() = false for a certificate expiring with its CA")
+	}
+}
+
+func TestReconcileClientCertificates(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = dorisv1alpha1.AddToScheme(s)
+
+	instance := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testClusterName, Namespace: testClusterNamespace, UID: "uid"},
+		Spec: dorisv1alpha1.DorisClusterSpec{
+			ClusterConfig: &dorisv1alpha1.ClusterConfigSpec{TLS: &dorisv1alpha1.TLSSpec{}},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(instance).Build()
+	r := &DorisClusterReconciler{Client: cli, Scheme: s}
+	auth := &fe.Authentication{TLS: &authv1alpha1.TLSProvider{ClientCertSecretClass: "client"}}
+
+	if err := r.reconcileClientCertificates(ctx, instance, auth); err != nil {
+		t.Fatalf("reconcileClientCertificates() error = %v", err)
+	}
+	cert, version, err := r.getOperatorClientCertificate(ctx, instance)
+	if err != nil || cert == nil {
+		t.Fatalf("getOperatorClientCertificate() = %v, %v, want the operator certificate", cert, err)
+	}
+
+	caSecret := &corev1.Secret{}
+	caKey := types.NamespacedName{Name: fe.OperatorClientCASecretName(instance.Name), Namespace: instance.Namespace}
+	if err := cli.Get(ctx, caKey, caSecret); err != nil {
+		t.Fatalf("expected the operator client CA Secret: %v", err)
+	}
+	ca, err := parseClientCA(caSecret.Data["ca.crt"], caSecret.Data["ca.key"])
+	if err != nil {
+		t.Fatalf("parseClientCA() error = %v", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil || leaf.CheckSignatureFrom(ca.cert) != nil || leaf.Subject.CommonName != operatorClientCommonName {
+		t.Errorf("operator certificate %v is not issued by the operator client CA", leaf.Subject)
+	}
+
+	// A valid certificate is kept
+	if err := r.reconcileClientCertificates(ctx, instance, auth); err != nil {
+		t.Fatalf("reconcileClientCertificates() error = %v", err)
+	}
+	if _, again, _ := r.getOperatorClientCertificate(ctx, instance); again != version {
+		t.Errorf("certificate Secret version = %s, want %s kept", again, version)
+	}
+
+	// Both Secrets are deleted once client certificates are not required
+	if err := r.reconcileClientCertificates(ctx, instance, &fe.Authentication{}); err != nil {
+		t.Fatalf("reconcileClientCertificates() error = %v", err)
+	}
+	if err := cli.Get(ctx, caKey, caSecret); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the operator client CA Secret deleted, got %v", err)
+	}
+	if cert, _, err := r.getOperatorClientCertificate(ctx, instance); err != nil || cert != nil {
+		t.Errorf("getOperatorClientCertificate() = %v, %v, want no certificate", cert, err)
+	}
+}
@@ -0,0 +1,38 @@
+package common
+
+import (
+	"strconv"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+)
+
+// GetArrowFlightPort returns the Arrow Flight SQL port of a component, or 0 when Arrow Flight is disabled
+func GetArrowFlightPort(dorisCluster *dorisv1alpha1.DorisCluster, componentType constants.ComponentType) int32 {
+	if dorisCluster == nil || dorisCluster.Spec.ClusterConfig == nil || dorisCluster.Spec.ClusterConfig.ArrowFlight == nil {
+		return 0
+	}
+	spec := dorisCluster.Spec.ClusterConfig.ArrowFlight
+	switch componentType {
+	case constants.ComponentTypeFE:
+		if spec.FEPort == 0 {
+			return constants.FEArrowFlightPort
+		}
+		return spec.FEPort
+	case constants.ComponentTypeBE:
+		if spec.BEPort == 0 {
+			return constants.BEArrowFlightPort
+		}
+		return spec.BEPort
+	default:
+		return 0
+	}
+}
+
+// ArrowFlightPortConfig returns the arrow_flight_sql_port setting, -1 disables Arrow Flight
+func ArrowFlightPortConfig(port int32) string {
+	if port == 0 {
+		return "-1"
+	}
+	return strconv.Itoa(int(port))
+}
@@ -0,0 +1,80 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	"github.com/zncdatadev/operator-go/pkg/builder"
+	"github.com/zncdatadev/operator-go/pkg/client"
+	"github.com/zncdatadev/operator-go/pkg/reconciler"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IsPDBEnabled returns whether a PodDisruptionBudget is created for the role, which is the
+// default unless it is disabled in the role config
+func IsPDBEnabled(spec *dorisv1alpha1.RoleSpec) bool {
+	if spec == nil || spec.RoleConfig == nil || spec.RoleConfig.PodDisruptionBudget == nil {
+		return true
+	}
+	return spec.RoleConfig.PodDisruptionBudget.Enabled
+}
+
+// GetPDBMaxUnavailable returns the number of pods of the role allowed to be disrupted at once:
+// the configured value, otherwise a minority of the FE followers so that FE keeps its quorum,
+// and one BE or broker at a time so that tablet replicas are not lost together.
+// One or two FE followers have no minority to spare: no FE pod may be evicted, so node
+// drains wait until the PodDisruptionBudget is configured or disabled.
+func GetPDBMaxUnavailable(componentType constants.ComponentType, spec *dorisv1alpha1.RoleSpec) int32 {
+	if spec != nil && spec.RoleConfig != nil && spec.RoleConfig.PodDisruptionBudget != nil &&
+		spec.RoleConfig.PodDisruptionBudget.MaxUnavailable != nil {
+		return *spec.RoleConfig.PodDisruptionBudget.MaxUnavailable
+	}
+	if componentType != constants.ComponentTypeFE || spec == nil {
+		return 1
+	}
+
+	var replicas int32
+	for _, roleGroup := range spec.RoleGroups {
+		replicas += ptr.Deref(roleGroup.Replicas, 1)
+	}
+	electNumber, _ := strconv.Atoi(constants.DefaultElectNumber)
+	followers := min(replicas, int32(electNumber))
+	return (followers - 1) / 2
+}
+
+// NewRolePDBReconciler creates the PodDisruptionBudget covering all the pods of a role
+func NewRolePDBReconciler(
+	client *client.Client,
+	roleInfo reconciler.RoleInfo,
+	maxUnavailable int32,
+) (reconciler.Reconciler, error) {
+	return reconciler.NewPDBReconciler(client, roleInfo.GetFullName(), func(opt *builder.PDBBuilderOptions) {
+		opt.Labels = roleInfo.GetLabels()
+		opt.Annotations = roleInfo.GetAnnotations()
+		opt.MaxUnavailableAmount = &maxUnavailable
+	})
+}
+
+// deleteRolePDB deletes the PodDisruptionBudget of a role once it is disabled
+func deleteRolePDB(ctx context.Context, client *client.Client, roleInfo reconciler.RoleInfo) error {
+	pdb := &policyv1.PodDisruptionBudget{}
+	key := types.NamespacedName{Name: roleInfo.GetFullName(), Namespace: client.GetOwnerNamespace()}
+	if err := client.Client.Get(ctx, key, pdb); err != nil {
+		return ctrlclient.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(pdb, client.OwnerReference) {
+		return nil
+	}
+	if err := client.Client.Delete(ctx, pdb); ctrlclient.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete PodDisruptionBudget %s: %w", key, err)
+	}
+	logger.Info("Deleted PodDisruptionBudget", "namespace", key.Namespace, "name", key.Name)
+	return nil
+}
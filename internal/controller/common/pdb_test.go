@@ -0,0 +1,75 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	commonsv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/commons/v1alpha1"
+	"k8s.io/utils/ptr"
+)
+
+func TestGetPDBMaxUnavailable(t *testing.T) {
+	roleGroups := func(replicas ...int32) map[string]dorisv1alpha1.RoleGroupSpec {
+		groups := map[string]dorisv1alpha1.RoleGroupSpec{}
+		for i, r := range replicas {
+			groups[string(rune('a'+i))] = dorisv1alpha1.RoleGroupSpec{Replicas: ptr.To(r)}
+		}
+		return groups
+	}
+
+	tests := []struct {
+		name          string
+		componentType constants.ComponentType
+		spec          *dorisv1alpha1.RoleSpec
+		want          int32
+	}{
+		{"single FE", constants.ComponentTypeFE, &dorisv1alpha1.RoleSpec{RoleGroups: roleGroups(1)}, 0},
+		{"two FE followers", constants.ComponentTypeFE, &dorisv1alpha1.RoleSpec{RoleGroups: roleGroups(2)}, 0},
+		{"three FE followers", constants.ComponentTypeFE, &dorisv1alpha1.RoleSpec{RoleGroups: roleGroups(3)}, 1},
+		{"FE followers and observers", constants.ComponentTypeFE, &dorisv1alpha1.RoleSpec{RoleGroups: roleGroups(3, 4)}, 1},
+		{"BE", constants.ComponentTypeBE, &dorisv1alpha1.RoleSpec{RoleGroups: roleGroups(10)}, 1},
+		{"broker", constants.ComponentTypeBroker, &dorisv1alpha1.RoleSpec{RoleGroups: roleGroups(2)}, 1},
+		{"configured", constants.ComponentTypeBE, &dorisv1alpha1.RoleSpec{
+			RoleGroups: roleGroups(10),
+			RoleConfig: &commonsv1alpha1.RoleConfigSpec{
+				PodDisruptionBudget: &commonsv1alpha1.PodDisruptionBudgetSpec{Enabled: true, MaxUnavailable: ptr.To[int32](3)},
+			},
+		}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetPDBMaxUnavailable(tt.componentType, tt.spec); got != tt.want {
+				t.Errorf("GetPDBMaxUnavailable() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPDBEnabled(t *testing.T) {
+	if !IsPDBEnabled(&dorisv1alpha1.RoleSpec{}) {
+		t.Error("expected PodDisruptionBudget to be enabled by default")
+	}
+	spec := &dorisv1alpha1.RoleSpec{RoleConfig: &commonsv1alpha1.RoleConfigSpec{
+		PodDisruptionBudget: &commonsv1alpha1.PodDisruptionBudgetSpec{Enabled: false},
+	}}
+	if IsPDBEnabled(spec) {
+		t.Error("expected PodDisruptionBudget to be disabled")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
 	commonsv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/commons/v1alpha1"
 	"github.com/zncdatadev/operator-go/pkg/client"
 	"github.com/zncdatadev/operator-go/pkg/reconciler"
@@ -129,9 +130,35 @@ func (r *BaseDorisRoleReconciler) RegisterResources(ctx context.Context) error {
 			logger.Info("registered resource", "role", r.GetName(), "roleGroup", name, "reconciler", reconciler.GetName())
 		}
 	}
+
+	// The PodDisruptionBudget covers the pods of all the role groups, so it is registered once per role
+	if IsPDBEnabled(r.Spec) {
+		pdb, err := NewRolePDBReconciler(r.Client, r.RoleInfo,
+			GetPDBMaxUnavailable(constants.ComponentType(r.ComponentType), r.Spec))
+		if err != nil {
+			return err
+		}
+		r.AddResource(pdb)
+	}
 	return nil
 }
 
+// Reconcile reconciles the registered resources of the role. It replaces the one of operator-go,
+// which creates a PodDisruptionBudget without the Doris defaults when the role config has one.
+func (r *BaseDorisRoleReconciler) Reconcile(ctx context.Context) (ctrl.Result, error) {
+	for _, resource := range r.GetResources() {
+		if res, err := resource.Reconcile(ctx); !res.IsZero() || err != nil {
+			return res, err
+		}
+	}
+	if !IsPDBEnabled(r.Spec) {
+		if err := deleteRolePDB(ctx, r.Client, r.RoleInfo); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
 // RegisterStandardResources registers common resources for a Doris component
 func RegisterStandardResources(
 	ctx context.Context,
@@ -152,7 +179,7 @@ func RegisterStandardResources(
 	reconcilers = append(reconcilers, serviceReconcilers...)
 
 	// Create metrics service
-	metricsSvc := NewRoleGroupMetricsService(client, roleGroupInfo)
+	metricsSvc := NewRoleGroupMetricsService(client, roleGroupInfo, dorisCluster)
 	if metricsSvc != nil {
 		reconcilers = append(reconcilers, metricsSvc)
 	}
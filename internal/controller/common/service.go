@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
 	"github.com/zncdatadev/doris-operator/internal/controller/constants"
 	"github.com/zncdatadev/operator-go/pkg/builder"
 	"github.com/zncdatadev/operator-go/pkg/client"
@@ -22,6 +23,9 @@ const (
 
 	// ServiceTypeAccess is for externally accessible services
 	ServiceTypeAccess ServiceType = "access"
+
+	// ServiceTypeListener is for the services exposing a single pod
+	ServiceTypeListener ServiceType = "listener"
 )
 
 // ComponentServiceConfig defines the port configuration for a specific component
@@ -34,6 +38,9 @@ type ComponentServiceConfig struct {
 
 	// AccessPorts defines which ports to expose in the access service
 	AccessPorts []corev1.ContainerPort
+
+	// ListenerClass exposes the access service, the internal service is always cluster internal
+	ListenerClass opconstants.ListenerClass
 }
 
 // DorisServiceBuilder implements the ServiceBuilder interface for Doris services
@@ -73,13 +80,10 @@ func NewDorisServiceBuilder(
 		constants.ComponentLabelKey:      string(componentType),
 	}
 
-	// If this is an internal service, set it to headless
-	// var listenerClass opconstants.ListenerClass
-	// if serviceType == ServiceTypeInternal {
-	// 	listenerClass = opconstants.ClusterInternal
-	// } else {
-	// 	listenerClass = opconstants.ExternalUnstable
-	// }
+	listenerClass := opconstants.ClusterInternal
+	if serviceType == ServiceTypeAccess && config.ListenerClass != "" {
+		listenerClass = config.ListenerClass
+	}
 
 	// Create the BaseServiceBuilder with container ports
 	// ServiceBuilder will convert these to ServicePort internally
@@ -89,7 +93,7 @@ func NewDorisServiceBuilder(
 		ports,
 		func(sbo *builder.ServiceBuilderOptions) {
 			sbo.Headless = (serviceType == ServiceTypeInternal)
-			sbo.ListenerClass = opconstants.ClusterInternal
+			sbo.ListenerClass = listenerClass
 			sbo.Labels = svcLabels
 			sbo.MatchingLabels = matchLabels
 		},
@@ -164,10 +168,12 @@ func NewAccessServiceReconciler(
 func NewRoleGroupMetricsService(
 	client *client.Client,
 	roleGroupInfo *reconciler.RoleGroupInfo,
+	dorisCluster *dorisv1alpha1.DorisCluster,
 ) reconciler.Reconciler {
 	roleName := roleGroupInfo.GetRoleName()
+	tlsEnabled := GetTLSSpec(dorisCluster) != nil
 	// Get metrics port
-	metricsPort, err := GetMetricsPort(roleName)
+	metricsPort, err := GetMetricsPort(roleName, tlsEnabled)
 	if err != nil {
 		// Return empty reconciler on error - should not happen
 		fmt.Printf("GetMetricsPort error for role %v: %v. Skipping metrics service creation.\n", roleName, err)
@@ -201,7 +207,7 @@ func NewRoleGroupMetricsService(
 	annotations["prometheus.io/scrape"] = "true"
 	annotations["prometheus.io/path"] = "/metrics" // Default metrics path is /metrics
 	annotations["prometheus.io/port"] = strconv.Itoa(int(metricsPort))
-	annotations["prometheus.io/scheme"] = constants.HttpScheme
+	annotations["prometheus.io/scheme"] = GetHttpScheme(dorisCluster)
 
 	// Create base service builder
 	baseBuilder := builder.NewServiceBuilder(
@@ -223,10 +229,27 @@ func NewRoleGroupMetricsService(
 	)
 }
 
-// GetMetricsPort returns the metrics port for the given role
-func GetMetricsPort(role string) (int32, error) {
+// GetListenerClass returns the listener class of a role, cluster internal by default
+func GetListenerClass(roleSpec *dorisv1alpha1.RoleSpec) opconstants.ListenerClass {
+	if roleSpec == nil || roleSpec.ListenerClass == "" {
+		return opconstants.ClusterInternal
+	}
+	return opconstants.ListenerClass(roleSpec.ListenerClass)
+}
+
+// IsExternal reports whether the listener class exposes the role outside of Kubernetes
+func IsExternal(listenerClass opconstants.ListenerClass) bool {
+	return listenerClass != "" && listenerClass != opconstants.ClusterInternal
+}
+
+// GetMetricsPort returns the metrics port for the given role.
+// With TLS enabled FE serves its metrics on the HTTPS port, BE on the same webserver port.
+func GetMetricsPort(role string, tlsEnabled bool) (int32, error) {
 	switch role {
 	case "fe":
+		if tlsEnabled {
+			return constants.FEHttpsPort, nil
+		}
 		return constants.FEHttpPort, nil
 	case "be":
 		return constants.BEHttpPort, nil
@@ -2,9 +2,11 @@ package common
 
 import (
 	"context"
+	"fmt"
 
 	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
 	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	"github.com/zncdatadev/doris-operator/internal/controller/storage"
 	commonsv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/commons/v1alpha1"
 	"github.com/zncdatadev/operator-go/pkg/builder"
 	"github.com/zncdatadev/operator-go/pkg/client"
@@ -14,6 +16,7 @@ import (
 	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -94,7 +97,11 @@ type StatefulSetComponentBuilder interface {
 // Build constructs the StatefulSet object combining common and component-specific configurations
 func (b *StatefulSetBuilder) Build(ctx context.Context, component StatefulSetComponentBuilder) (ctrlclient.Object, error) {
 	// Add component-specific container
-	b.AddContainer(component.GetMainContainer())
+	container := component.GetMainContainer()
+	if GetComponentTLSSpec(b.dorisCluster, b.componentType) != nil {
+		container.VolumeMounts = append(container.VolumeMounts, TLSVolumeMount())
+	}
+	b.AddContainer(container)
 
 	// Add init containers if any
 	initContainers := component.GetInitContainers()
@@ -120,6 +127,17 @@ func (b *StatefulSetBuilder) Build(ctx context.Context, component StatefulSetCom
 	// Set parallel pod management for faster scaling
 	sts.Spec.PodManagementPolicy = appv1.ParallelPodManagement
 
+	// FE and BE pods only receive traffic once the operator found them serving in Doris
+	if b.componentType == constants.ComponentTypeFE || b.componentType == constants.ComponentTypeBE {
+		sts.Spec.Template.Spec.ReadinessGates = []corev1.PodReadinessGate{
+			{ConditionType: constants.RegisteredConditionType},
+			{ConditionType: constants.ServingConditionType},
+		}
+	}
+
+	// PVCs of scaled-down pods are cleaned up by the operator once Doris released the node
+	sts.Spec.PersistentVolumeClaimRetentionPolicy = storage.StatefulSetRetentionPolicy(&b.dorisCluster.Spec)
+
 	return sts, nil
 }
 
@@ -144,7 +162,7 @@ func (b *StatefulSetBuilder) GetObject() (*appv1.StatefulSet, error) {
 
 // getCommonVolumes returns volumes common to both BE and FE components
 func (b *StatefulSetBuilder) getCommonVolumes() []corev1.Volume {
-	return []corev1.Volume{
+	volumes := []corev1.Volume{
 		{
 			Name: constants.LogVolumeName,
 			VolumeSource: corev1.VolumeSource{
@@ -185,6 +203,18 @@ func (b *StatefulSetBuilder) getCommonVolumes() []corev1.Volume {
 			},
 		},
 	}
+	if tlsSpec := GetComponentTLSSpec(b.dorisCluster, b.componentType); tlsSpec != nil {
+		volumes = append(volumes, NewTLSVolume(tlsSpec, GetTLSFormat(b.componentType),
+			GetServiceName(b.clusterName, b.componentType, ServiceTypeInternal),
+			GetServiceName(b.clusterName, b.componentType, ServiceTypeAccess),
+		))
+	}
+	return volumes
+}
+
+// GetDorisCluster returns the DorisCluster the StatefulSet belongs to
+func (b *StatefulSetBuilder) GetDorisCluster() *dorisv1alpha1.DorisCluster {
+	return b.dorisCluster
 }
 
 // getFeServiceAddress returns the FE service address for BE to connect to
@@ -205,13 +235,19 @@ func (b *StatefulSetBuilder) CreateTcpProbe(port int32, initialDelay, period int
 	}
 }
 
-// CreateHttpProbe creates an HTTP probe for health checking
+// CreateHttpProbe creates an HTTP probe for health checking.
+// The probe uses HTTPS when the component serves TLS.
 func (b *StatefulSetBuilder) CreateHttpProbe(port int32, path string, initialDelay, period int32) *corev1.Probe {
+	scheme := corev1.URISchemeHTTP
+	if GetComponentTLSSpec(b.dorisCluster, b.componentType) != nil {
+		scheme = corev1.URISchemeHTTPS
+	}
 	return &corev1.Probe{
 		ProbeHandler: corev1.ProbeHandler{
 			HTTPGet: &corev1.HTTPGetAction{
-				Path: path,
-				Port: intstr.FromInt32(port),
+				Path:   path,
+				Port:   intstr.FromInt32(port),
+				Scheme: scheme,
 			},
 		},
 		InitialDelaySeconds: initialDelay,
@@ -219,6 +255,61 @@ func (b *StatefulSetBuilder) CreateHttpProbe(port int32, path string, initialDel
 	}
 }
 
+// CreateStartupProbe creates a startup probe checking the same as the liveness probe, holding
+// it back until the component started
+func (b *StatefulSetBuilder) CreateStartupProbe(
+	livenessProbe *corev1.Probe,
+	config *dorisv1alpha1.ConfigSpec,
+	defaultFailureThreshold int32,
+) *corev1.Probe {
+	probe := livenessProbe.DeepCopy()
+	probe.InitialDelaySeconds = 0
+	probe.PeriodSeconds = constants.DefaultPeriodSeconds
+	probe.FailureThreshold = defaultFailureThreshold
+	if config != nil && config.StartupProbe != nil {
+		spec := config.StartupProbe
+		if spec.PeriodSeconds > 0 {
+			probe.PeriodSeconds = spec.PeriodSeconds
+		}
+		if spec.FailureThreshold > 0 {
+			probe.FailureThreshold = spec.FailureThreshold
+		}
+	}
+	return probe
+}
+
+// CreateDrainPreStopHook creates a preStop hook waiting until the metrics of the component
+// matching the pattern sum up to zero, i.e. until its running work drained. It waits at most
+// the termination grace period less the time kept for Doris to stop, and gives up when the
+// metrics cannot be fetched. It returns nil when no time is left to drain; an invalid grace
+// period is reported when the pod template is built.
+func (b *StatefulSetBuilder) CreateDrainPreStopHook(port int32, metricsPattern string) *corev1.Lifecycle {
+	gracePeriod, err := b.GetTerminationGracePeriodSeconds()
+	if err != nil {
+		return nil
+	}
+	drainSeconds := ptr.Deref(gracePeriod, corev1.DefaultTerminationGracePeriodSeconds) - constants.PreStopStopSeconds
+	if drainSeconds <= 0 {
+		return nil
+	}
+	scheme := "http"
+	if GetComponentTLSSpec(b.dorisCluster, b.componentType) != nil {
+		scheme = "https"
+	}
+	script := fmt.Sprintf(`deadline=$(( $(date +%%s) + %d ))
+while [ "$(date +%%s)" -lt "$deadline" ]; do
+  metrics=$(curl -skf %s://127.0.0.1:%d%s) || break
+  echo "$metrics" | awk '/^(%s)[ {]/ { n += $NF } END { exit (n > 0) }' && break
+  sleep 2
+done
+`, drainSeconds, scheme, port, constants.MetricsPath, metricsPattern)
+	return &corev1.Lifecycle{
+		PreStop: &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{Command: []string{"sh", "-c", script}},
+		},
+	}
+}
+
 // CreateBaseContainer creates a basic container with common configuration that BE and FE can extend
 func (b *StatefulSetBuilder) CreateBaseContainer(
 	containerName string,
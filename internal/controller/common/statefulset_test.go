@@ -0,0 +1,96 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	commonsv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/commons/v1alpha1"
+	"github.com/zncdatadev/operator-go/pkg/client"
+	"github.com/zncdatadev/operator-go/pkg/reconciler"
+	opgoutil "github.com/zncdatadev/operator-go/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func newTestStatefulSetBuilder(config *dorisv1alpha1.ConfigSpec) *StatefulSetBuilder {
+	return NewStatefulSetBuilder(
+		context.Background(),
+		&client.Client{},
+		constants.ComponentTypeBE,
+		&reconciler.RoleGroupInfo{
+			RoleInfo: reconciler.RoleInfo{
+				ClusterInfo: reconciler.ClusterInfo{
+					GVK: &metav1.GroupVersionKind{
+						Group:   dorisv1alpha1.GroupVersion.Group,
+						Version: dorisv1alpha1.GroupVersion.Version,
+						Kind:    "DorisCluster",
+					},
+					ClusterName: "test",
+				},
+				RoleName: "be",
+			},
+			RoleGroupName: "default",
+		},
+		&opgoutil.Image{},
+		ptr.To[int32](1),
+		config,
+		nil,
+		&dorisv1alpha1.DorisCluster{},
+	)
+}
+
+func TestCreateStartupProbe(t *testing.T) {
+	b := newTestStatefulSetBuilder(nil)
+	liveness := b.CreateTcpProbe(constants.BEHeartbeatPort, constants.DefaultInitialDelaySeconds, constants.DefaultPeriodSeconds)
+
+	probe := b.CreateStartupProbe(liveness, nil, constants.BEStartupFailureThreshold)
+	if probe.FailureThreshold != constants.BEStartupFailureThreshold || probe.InitialDelaySeconds != 0 {
+		t.Errorf("default startup probe = %+v", probe)
+	}
+	if probe.TCPSocket == nil || probe.TCPSocket.Port.IntVal != constants.BEHeartbeatPort {
+		t.Errorf("startup probe does not check the liveness port: %+v", probe.ProbeHandler)
+	}
+
+	config := &dorisv1alpha1.ConfigSpec{StartupProbe: &dorisv1alpha1.StartupProbeSpec{PeriodSeconds: 5, FailureThreshold: 100}}
+	probe = b.CreateStartupProbe(liveness, config, constants.BEStartupFailureThreshold)
+	if probe.PeriodSeconds != 5 || probe.FailureThreshold != 100 {
+		t.Errorf("configured startup probe = %+v", probe)
+	}
+}
+
+func TestCreateDrainPreStopHook(t *testing.T) {
+	hook := newTestStatefulSetBuilder(nil).CreateDrainPreStopHook(constants.BEHttpPort, "doris_be_fragment_instance_count")
+	if hook == nil || hook.PreStop == nil || hook.PreStop.Exec == nil {
+		t.Fatal("expected a preStop hook with the default grace period")
+	}
+	script := hook.PreStop.Exec.Command[2]
+	if !strings.Contains(script, "+ 20 ))") || !strings.Contains(script, "http://127.0.0.1:8040/metrics") {
+		t.Errorf("unexpected preStop script:\n%s", script)
+	}
+
+	config := &dorisv1alpha1.ConfigSpec{
+		RoleGroupConfigSpec: &commonsv1alpha1.RoleGroupConfigSpec{GracefulShutdownTimeout: "5s"},
+	}
+	if hook := newTestStatefulSetBuilder(config).CreateDrainPreStopHook(constants.BEHttpPort, "x"); hook != nil {
+		t.Error("expected no preStop hook when the grace period leaves no time to drain")
+	}
+}
@@ -0,0 +1,80 @@
+package common
+
+import (
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	"github.com/zncdatadev/operator-go/pkg/builder"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SecretClassGVK is the SecretClass of the secret-operator
+var SecretClassGVK = schema.GroupVersionKind{Group: "secrets.kubedoop.dev", Version: "v1alpha1", Kind: "SecretClass"}
+
+// GetTLSSpec returns the TLS configuration of the cluster, or nil when TLS is disabled
+func GetTLSSpec(dorisCluster *dorisv1alpha1.DorisCluster) *dorisv1alpha1.TLSSpec {
+	if dorisCluster == nil || dorisCluster.Spec.ClusterConfig == nil {
+		return nil
+	}
+	return dorisCluster.Spec.ClusterConfig.TLS
+}
+
+// GetComponentTLSSpec returns the TLS configuration of a component, or nil when the component
+// serves no TLS: only the FE and BE servers are secured, brokers are not.
+func GetComponentTLSSpec(
+	dorisCluster *dorisv1alpha1.DorisCluster,
+	componentType constants.ComponentType,
+) *dorisv1alpha1.TLSSpec {
+	if componentType != constants.ComponentTypeFE && componentType != constants.ComponentTypeBE {
+		return nil
+	}
+	return GetTLSSpec(dorisCluster)
+}
+
+// GetTLSSecretClass returns the SecretClass issuing the server certificates
+func GetTLSSecretClass(tlsSpec *dorisv1alpha1.TLSSpec) string {
+	if tlsSpec == nil || tlsSpec.ServerSecretClass == "" {
+		return constants.DefaultTLSSecretClass
+	}
+	return tlsSpec.ServerSecretClass
+}
+
+// GetTLSFormat returns the certificate format a component reads: the Java FE uses
+// PKCS12 stores, BE uses PEM files.
+func GetTLSFormat(componentType constants.ComponentType) opgpconstants.SecretFormat {
+	if componentType == constants.ComponentTypeFE {
+		return opgpconstants.TLSP12
+	}
+	return opgpconstants.TLSPEM
+}
+
+// NewTLSVolume creates the secret-operator volume providing the server certificate in the given format.
+// The certificate is issued for the pod and the given services, so clients can verify
+// the host name of both the pod FQDN and the service names.
+func NewTLSVolume(tlsSpec *dorisv1alpha1.TLSSpec, format opgpconstants.SecretFormat, services ...string) corev1.Volume {
+	volume := builder.NewSecretOperatorVolume(constants.TLSVolumeName, GetTLSSecretClass(tlsSpec))
+	volume.SetScope(&builder.SecretVolumeScope{Pod: true, Service: services})
+	volume.SetFormatName(format)
+	if format == opgpconstants.TLSP12 {
+		volume.SetPKCS12Password(constants.TLSKeystorePassword)
+	}
+	return *volume.Builde()
+}
+
+// GetHttpScheme returns the scheme of the FE and BE HTTP servers
+func GetHttpScheme(dorisCluster *dorisv1alpha1.DorisCluster) string {
+	if GetTLSSpec(dorisCluster) != nil {
+		return constants.HttpsScheme
+	}
+	return constants.HttpScheme
+}
+
+// TLSVolumeMount mounts the TLS volume into a container
+func TLSVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      constants.TLSVolumeName,
+		MountPath: constants.TLSMountPath,
+		ReadOnly:  true,
+	}
+}
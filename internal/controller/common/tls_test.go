@@ -0,0 +1,44 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+)
+
+func TestGetComponentTLSSpec(t *testing.T) {
+	cluster := &dorisv1alpha1.DorisCluster{
+		Spec: dorisv1alpha1.DorisClusterSpec{
+			ClusterConfig: &dorisv1alpha1.ClusterConfigSpec{TLS: &dorisv1alpha1.TLSSpec{}},
+		},
+	}
+
+	for _, componentType := range []constants.ComponentType{constants.ComponentTypeFE, constants.ComponentTypeBE} {
+		if GetComponentTLSSpec(cluster, componentType) == nil {
+			t.Errorf("expected %s to serve TLS", componentType)
+		}
+	}
+	if GetComponentTLSSpec(cluster, constants.ComponentTypeBroker) != nil {
+		t.Error("expected brokers not to mount the server certificates")
+	}
+	if GetComponentTLSSpec(&dorisv1alpha1.DorisCluster{}, constants.ComponentTypeFE) != nil {
+		t.Error("expected no TLS when clusterConfig.tls is not set")
+	}
+}
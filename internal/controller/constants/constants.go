@@ -27,6 +27,7 @@ const (
 	PodinfoVolumeName  = "podinfo"
 	DefaultElectNumber = "3"
 	HttpScheme         = "http"
+	HttpsScheme        = "https"
 )
 
 // Service related constants
@@ -34,6 +35,7 @@ const (
 	// Service naming patterns
 	ServiceInternalSuffix = "-internal"
 	ServiceAccessSuffix   = "-service"
+	ServiceListenerSuffix = "-listener"
 )
 
 // Image related constants
@@ -54,6 +56,9 @@ const (
 	BEContainerName     = string(ComponentTypeBE)
 	BrokerContainerName = string(ComponentTypeBroker)
 	InitContainerName   = "default-init"
+
+	// ClientTrustInitContainerName builds the FE truststore of client certificates
+	ClientTrustInitContainerName = "client-trust"
 )
 
 // Path related constants
@@ -77,6 +82,7 @@ const (
 const (
 	// FE ports
 	FEHttpPort       = 8030
+	FEHttpsPort      = 8050
 	FERpcPort        = 9020
 	FEQueryPort      = 9030
 	FEEditLogPort    = 9010
@@ -88,6 +94,10 @@ const (
 	BEHeartbeatPort = 9050
 	BEBrpcPort      = 8060
 
+	// Default Arrow Flight SQL ports
+	FEArrowFlightPort = 8070
+	BEArrowFlightPort = 8050
+
 	// Broker ports
 	BrokerIpcPort = 8000
 )
@@ -96,10 +106,14 @@ const (
 const (
 	// FE port names
 	FEHttpPortName    = string(ComponentTypeFE) + "-http"
+	FEHttpsPortName   = string(ComponentTypeFE) + "-https"
 	FERpcPortName     = string(ComponentTypeFE) + "-rpc"
 	FEQueryPortName   = string(ComponentTypeFE) + "-query"
 	FEEditLogPortName = string(ComponentTypeFE) + "-edit-log"
 
+	// ArrowFlightPortName is the Arrow Flight SQL port of FE and BE
+	ArrowFlightPortName = "arrow-flight"
+
 	// BE port names
 	BERpcPortName       = string(ComponentTypeBE) + "-rpc"
 	BEHttpPortName      = string(ComponentTypeBE) + "-http"
@@ -124,6 +138,68 @@ const (
 	ConfigVolumeName = "doris-config"
 
 	LogVolumeName = "log"
+
+	// TLSVolumeName is the name of the secret-operator volume holding the server certificates
+	TLSVolumeName = "tls"
+
+	// LDAPTLSVolumeName is the name of the secret-operator volume holding the LDAP server CA
+	LDAPTLSVolumeName = "ldap-tls"
+
+	// ClientTLSVolumeName is the name of the secret-operator volume holding the CA of client certificates
+	ClientTLSVolumeName = "client-tls"
+
+	// OperatorClientCAVolumeName is the name of the volume holding the CA of the operator client certificate
+	OperatorClientCAVolumeName = "operator-client-ca"
+
+	// ClientTrustVolumeName is the name of the volume holding the truststore FE verifies client certificates with
+	ClientTrustVolumeName = "client-trust"
+)
+
+// TLS related constants
+const (
+	// TLSMountPath is where the secret-operator mounts the certificates: PKCS12 stores
+	// for FE, PEM files for BE and broker
+	TLSMountPath      = "/kubedoop/tls"
+	TLSKeystorePath   = TLSMountPath + "/keystore.p12"
+	TLSTruststorePath = TLSMountPath + "/truststore.p12"
+	TLSCertPath       = TLSMountPath + "/tls.crt"
+	TLSKeyPath        = TLSMountPath + "/tls.key"
+	TLSCAPath         = TLSMountPath + "/ca.crt"
+
+	// TLSKeystorePassword protects the PKCS12 stores; they only live in the pod volume
+	TLSKeystorePassword = "changeit"
+
+	// DefaultTLSSecretClass is used when `clusterConfig.tls.serverSecretClass` is not set
+	DefaultTLSSecretClass = "tls"
+
+	// TLS verification modes of the FE certificate
+	TLSVerifyCA   = "VerifyCA"
+	TLSVerifyFull = "VerifyFull"
+
+	// TLSCACertKey is the key of the CA certificate in a CA Secret
+	TLSCACertKey = "ca.crt"
+	// TLSCAKeyKey is the key of the CA private key in a CA Secret
+	TLSCAKeyKey = "ca.key"
+
+	// LDAPTLSMountPath is where the truststore of the LDAP server CA is mounted in FE
+	LDAPTLSMountPath      = "/kubedoop/ldap-tls"
+	LDAPTruststorePath    = LDAPTLSMountPath + "/truststore.p12"
+	LDAPDefaultPort       = 389
+	LDAPDefaultSecurePort = 636
+
+	// ClientTLSMountPath is where the truststore of the client certificate CA is mounted in FE
+	ClientTLSMountPath      = "/kubedoop/client-tls"
+	ClientTLSTruststorePath = ClientTLSMountPath + "/truststore.p12"
+
+	// OperatorClientCAMountPath is where the CA of the operator client certificate is mounted in FE
+	OperatorClientCAMountPath = "/kubedoop/operator-client-ca"
+
+	// ClientTrustMountPath is where the truststore of both client certificate CAs is built in FE
+	ClientTrustMountPath      = "/kubedoop/client-trust"
+	ClientTrustTruststorePath = ClientTrustMountPath + "/truststore.p12"
+
+	// SecretClassLabel selects the Secrets of a SecretClass with a k8sSearch backend
+	SecretClassLabel = "secrets.kubedoop.dev/class"
 )
 
 // Resource related constants
@@ -140,13 +216,26 @@ const (
 	// Storage sizes
 	FEStorageSize = "10Gi"
 	BEStorageSize = "20Gi"
+
+	// BE storage mediums
+	BEStorageMediumSSD = "SSD"
+	BEStorageMediumHDD = "HDD"
 )
 
 // Health check related constants
 const (
 	HealthCheckPath            = "/api/health"
+	MetricsPath                = "/metrics"
 	DefaultInitialDelaySeconds = 30
 	DefaultPeriodSeconds       = 10
+
+	// Startup probe failures allowed by default, FE replays its metadata before it listens
+	FEStartupFailureThreshold = 60
+	BEStartupFailureThreshold = 30
+
+	// PreStopStopSeconds is the part of the termination grace period kept for Doris to stop
+	// after the preStop hook drained the node
+	PreStopStopSeconds = 10
 )
 
 // Environment variable related constants
@@ -166,6 +255,10 @@ const (
 	// Default environment variable values
 	DefaultUser      = "root"
 	DefaultDorisRoot = BaseDorisPath
+
+	// Arrow Flight endpoint advertised by BE, exported by the BE start command
+	ArrowFlightPublicHostEnvVar = "ARROW_FLIGHT_PUBLIC_HOST"
+	ArrowFlightProxyPortEnvVar  = "ARROW_FLIGHT_PROXY_PORT"
 )
 
 // Command related constants
@@ -180,4 +273,24 @@ const (
 	ServiceRoleLabelKey    = "app.doris.service/role"
 	ComponentLabelKey      = "app.kubernetes.io/component"
 	HashAnnotationKey      = "app.doris.components/hash"
+
+	// DorisClusterFinalizer holds the DorisCluster deletion until the teardown completed
+	DorisClusterFinalizer = "doris.kubedoop.dev/teardown"
+
+	// ArrowFlightEndpointAnnotationKey holds the Arrow Flight endpoint a BE pod advertises,
+	// set by the operator on the pod from its listener Service
+	ArrowFlightEndpointAnnotationKey = "doris.kubedoop.dev/arrow-flight-endpoint"
+
+	// ReplaceStorageAnnotationKey marks a BE pod whose disk is corrupt, self-healing replaces
+	// its storage when it is set to "true"
+	ReplaceStorageAnnotationKey = "doris.kubedoop.dev/replace-storage"
+)
+
+// Readiness gates of FE and BE pods, set by the operator from SHOW FRONTENDS and SHOW BACKENDS
+const (
+	// RegisteredConditionType is true once the node is registered in Doris
+	RegisteredConditionType = "doris.kubedoop.dev/registered"
+	// ServingConditionType is true while the node is alive, joined for FE, and not being
+	// decommissioned for BE
+	ServingConditionType = "doris.kubedoop.dev/serving"
 )
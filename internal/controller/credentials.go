@@ -0,0 +1,270 @@
+package controller
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// authSnapshotSecretName returns the operator-owned Secret holding the admin credentials
+// last applied to Doris. They are needed to log in once the authSecret changed.
+func authSnapshotSecretName(instance *dorisv1alpha1.DorisCluster) string {
+	return instance.Name + "-auth-snapshot"
+}
+
+// hashKeySecretName returns the operator-owned Secret holding the key of the hashes of
+// secrets recorded in status.
+func hashKeySecretName(instance *dorisv1alpha1.DorisCluster) string {
+	return instance.Name + "-hash-key"
+}
+
+// hashKeySecretKey is the Secret key holding the hash key
+const hashKeySecretKey = "key"
+
+// hashKey returns the key of the hashes of secrets recorded in status, generating it on
+// first use. The hashes are keyed so that they cannot be brute-forced by whoever can read
+// the DorisCluster.
+func (r *DorisClusterReconciler) hashKey(ctx context.Context, instance *dorisv1alpha1.DorisCluster) ([]byte, error) {
+	secret := &corev1.Secret{}
+	name := types.NamespacedName{Name: hashKeySecretName(instance), Namespace: instance.Namespace}
+	err := r.Get(ctx, name, secret)
+	if err == nil {
+		if key := secret.Data[hashKeySecretKey]; len(key) > 0 {
+			return key, nil
+		}
+		return nil, fmt.Errorf("hash key Secret %s has no %s", name, hashKeySecretKey)
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get hash key Secret %s: %w", name, err)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name.Name,
+			Namespace: name.Namespace,
+			Labels:    map[string]string{opgpconstants.LabelKubernetesInstance: instance.Name},
+		},
+		Data: map[string][]byte{hashKeySecretKey: key},
+	}
+	if err := controllerutil.SetControllerReference(instance, secret, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Create(ctx, secret); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return r.hashKey(ctx, instance)
+		}
+		return nil, fmt.Errorf("failed to create hash key Secret %s: %w", name, err)
+	}
+	return key, nil
+}
+
+// keyedHash returns the HMAC-SHA256 of the parts, separated by NUL
+func keyedHash(key []byte, parts ...[]byte) string {
+	mac := hmac.New(sha256.New, key)
+	for i, part := range parts {
+		if i > 0 {
+			mac.Write([]byte{0})
+		}
+		mac.Write(part)
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// credentialsHash returns the hash of admin credentials recorded in status
+func credentialsHash(key []byte, username, password string) string {
+	return keyedHash(key, []byte(username), []byte(password))
+}
+
+// recordAppliedCredentials stores the credentials applied to Doris in the snapshot Secret
+// and their hash in status.
+func (r *DorisClusterReconciler) recordAppliedCredentials(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	username, password string,
+) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      authSnapshotSecretName(instance),
+			Namespace: instance.Namespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels[opgpconstants.LabelKubernetesInstance] = instance.Name
+		secret.Type = corev1.SecretTypeBasicAuth
+		secret.Data = map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte(username),
+			corev1.BasicAuthPasswordKey: []byte(password),
+		}
+		return controllerutil.SetControllerReference(instance, secret, r.Scheme)
+	}); err != nil {
+		return fmt.Errorf("failed to record applied admin credentials: %w", err)
+	}
+	key, err := r.hashKey(ctx, instance)
+	if err != nil {
+		return err
+	}
+	return r.setAuthSecretHash(ctx, instance, credentialsHash(key, username, password))
+}
+
+// getAppliedCredentials returns the credentials last applied to Doris from the snapshot Secret.
+func (r *DorisClusterReconciler) getAppliedCredentials(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (string, string, error) {
+	secret := &corev1.Secret{}
+	name := authSnapshotSecretName(instance)
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: instance.Namespace}, secret); err != nil {
+		return "", "", fmt.Errorf("failed to get applied admin credentials %s: %w", name, err)
+	}
+	return string(secret.Data[corev1.BasicAuthUsernameKey]), string(secret.Data[corev1.BasicAuthPasswordKey]), nil
+}
+
+// setAuthSecretHash records the hash of the applied credentials in status right away, so that
+// it stays consistent with the snapshot Secret whatever happens later in the reconcile.
+func (r *DorisClusterReconciler) setAuthSecretHash(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	hash string,
+) error {
+	return r.patchStatus(ctx, instance, func(status *dorisv1alpha1.DorisClusterStatus) {
+		status.AuthSecretHash = hash
+	})
+}
+
+// patchStatus applies a status change right away, then to the in-memory instance.
+func (r *DorisClusterReconciler) patchStatus(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	mutate func(status *dorisv1alpha1.DorisClusterStatus),
+) error {
+	// Patch a copy: the in-memory spec of the instance may be gated
+	latest := instance.DeepCopy()
+	patch := ctrlclient.MergeFrom(instance.DeepCopy())
+	mutate(&latest.Status)
+	if err := r.Status().Patch(ctx, latest, patch); err != nil {
+		return err
+	}
+	mutate(&instance.Status)
+	return nil
+}
+
+// reconcileAdminCredentials applies authSecret changes to Doris once the admin user exists.
+// It logs in with the previously applied credentials, alters the password or creates the new
+// user, adopting an existing user with the new password, verifies the new login and rolls
+// back on failure.
+func (r *DorisClusterReconciler) reconcileAdminCredentials(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	target doris_client.ClusterTarget,
+) error {
+	key, err := r.hashKey(ctx, instance)
+	if err != nil {
+		return err
+	}
+	hash := credentialsHash(key, target.User, target.Password)
+	if hash == instance.Status.AuthSecretHash {
+		return nil
+	}
+
+	// Freshly bootstrapped, or bootstrapped before the hash was recorded:
+	// the current credentials are the applied ones
+	if instance.Status.AuthSecretHash == "" {
+		if err := r.DorisClients.Verify(target); err != nil {
+			return fmt.Errorf("failed to log in to Doris as %s: %w", target.User, err)
+		}
+		return r.recordAppliedCredentials(ctx, instance, target.User, target.Password)
+	}
+
+	previousUser, previousPassword, err := r.getAppliedCredentials(ctx, instance)
+	if err != nil {
+		return fmt.Errorf("authSecret changed but the previous credentials are unknown: %w", err)
+	}
+	switch credentialsHash(key, previousUser, previousPassword) {
+	case hash:
+		// Rotated, but the status was not updated
+		return r.setAuthSecretHash(ctx, instance, hash)
+	case instance.Status.AuthSecretHash:
+	default:
+		return fmt.Errorf("applied admin credentials %s do not match status.authSecretHash",
+			authSnapshotSecretName(instance))
+	}
+
+	previous := target
+	previous.User, previous.Password = previousUser, previousPassword
+	previous.CredentialVersion = "previous/" + instance.Status.AuthSecretHash
+	previousClient, err := r.DorisClients.Get(ctx, previous)
+	if err != nil {
+		return fmt.Errorf("failed to log in to Doris with the previous credentials of %s: %w", previousUser, err)
+	}
+	defer r.DorisClients.Put(previousClient)
+	defer r.DorisClients.Release(instance.UID, previousUser)
+
+	logger.Info("authSecret changed, rotating admin credentials",
+		"cluster", instance.Name, "previousUser", previousUser, "user", target.User)
+
+	if previousUser == target.User {
+		if err := previousClient.AlterUserPassword(ctx, target.User, target.Password); err != nil {
+			return err
+		}
+		if err := r.DorisClients.Verify(target); err != nil {
+			rollbackErr := previousClient.AlterUserPassword(ctx, previousUser, previousPassword)
+			return errors.Join(fmt.Errorf("new password of %s rejected, rolled back: %w", target.User, err), rollbackErr)
+		}
+	} else {
+		exists, err := previousClient.CheckUserExists(ctx, target.User)
+		if err != nil {
+			return err
+		}
+		if err := previousClient.InitializeAdminUser(ctx, target.User, target.Password); err != nil {
+			return err
+		}
+		// CREATE USER IF NOT EXISTS keeps the password of an existing user
+		if exists {
+			if err := previousClient.AlterUserPassword(ctx, target.User, target.Password); err != nil {
+				return err
+			}
+		}
+		if err := r.DorisClients.Verify(target); err != nil {
+			var rollbackErr error
+			if !exists {
+				rollbackErr = previousClient.DropUser(ctx, target.User)
+			}
+			return errors.Join(fmt.Errorf("new admin user %s cannot log in, rolled back: %w", target.User, err), rollbackErr)
+		}
+		if instance.Spec.AuthSecret.DropPreviousUser && previousUser != doris_client.DefaultAdminUser {
+			// The previous user is dropped by the new one
+			newClient, err := r.DorisClients.Get(ctx, target)
+			if err != nil {
+				return err
+			}
+			defer r.DorisClients.Put(newClient)
+			if err := newClient.DropUser(ctx, previousUser); err != nil {
+				return err
+			}
+		}
+	}
+
+	logger.Info("Admin credentials rotated", "cluster", instance.Name, "user", target.User)
+	return r.recordAppliedCredentials(ctx, instance, target.User, target.Password)
+}
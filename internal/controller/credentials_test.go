@@ -0,0 +1,126 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileAdminCredentials_Snapshot(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = dorisv1alpha1.AddToScheme(s)
+
+	instance := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testClusterName, Namespace: testClusterNamespace, UID: "uid"},
+		Spec: dorisv1alpha1.DorisClusterSpec{
+			AuthSecret: &dorisv1alpha1.AuthSecretSpec{SecretName: "admin"},
+		},
+	}
+	cli := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(instance).
+		WithStatusSubresource(instance).
+		Build()
+	r := &DorisClusterReconciler{Client: cli, Scheme: s, DorisClients: doris_client.NewClientManager()}
+
+	if err := r.recordAppliedCredentials(ctx, instance, "admin", "old"); err != nil {
+		t.Fatalf("recordAppliedCredentials() error = %v", err)
+	}
+	key, err := r.hashKey(ctx, instance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if instance.Status.AuthSecretHash != credentialsHash(key, "admin", "old") {
+		t.Fatalf("status.authSecretHash not recorded")
+	}
+	user, password, err := r.getAppliedCredentials(ctx, instance)
+	if err != nil || user != "admin" || password != "old" {
+		t.Fatalf("getAppliedCredentials() = %q, %q, %v", user, password, err)
+	}
+
+	// Unchanged credentials need no Doris connection
+	target := doris_client.ClusterTarget{UID: instance.UID, User: "admin", Password: "old"}
+	if err := r.reconcileAdminCredentials(ctx, instance, target); err != nil {
+		t.Fatalf("reconcileAdminCredentials() with unchanged credentials error = %v", err)
+	}
+
+	// A rotation applied before the status was updated is only recorded
+	if err := r.recordAppliedCredentials(ctx, instance, "admin", "new"); err != nil {
+		t.Fatal(err)
+	}
+	instance.Status.AuthSecretHash = credentialsHash(key, "admin", "old")
+	target.Password = "new"
+	if err := r.reconcileAdminCredentials(ctx, instance, target); err != nil {
+		t.Fatalf("reconcileAdminCredentials() after an interrupted rotation error = %v", err)
+	}
+	latest := &dorisv1alpha1.DorisCluster{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: testClusterName, Namespace: testClusterNamespace}, latest); err != nil {
+		t.Fatal(err)
+	}
+	if latest.Status.AuthSecretHash != credentialsHash(key, "admin", "new") {
+		t.Errorf("status.authSecretHash = %s, want the hash of the new credentials", latest.Status.AuthSecretHash)
+	}
+
+	// A snapshot matching neither the status nor the Secret is refused
+	instance.Status.AuthSecretHash = credentialsHash(key, "admin", "unknown")
+	target.Password = "newer"
+	if err := r.reconcileAdminCredentials(ctx, instance, target); err == nil {
+		t.Errorf("reconcileAdminCredentials() with a mismatching snapshot should fail")
+	}
+}
+
+func TestHashKey(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = dorisv1alpha1.AddToScheme(s)
+
+	instance := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testClusterName, Namespace: testClusterNamespace, UID: "uid"},
+	}
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(instance).Build()
+	r := &DorisClusterReconciler{Client: cli, Scheme: s}
+
+	key, err := r.hashKey(ctx, instance)
+	if err != nil {
+		t.Fatalf("hashKey() error = %v", err)
+	}
+	again, err := r.hashKey(ctx, instance)
+	if err != nil || string(again) != string(key) {
+		t.Errorf("expected the hash key to be kept in a Secret, got %v", err)
+	}
+
+	if credentialsHash(key, "admin", "secret") == credentialsHash([]byte("other"), "admin", "secret") {
+		t.Error("expected the hash to depend on the key")
+	}
+	if credentialsHash(key, "admin", "secret") == credentialsHash(key, "admi", "nsecret") {
+		t.Error("expected the user and password to be separated")
+	}
+}
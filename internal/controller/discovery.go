@@ -0,0 +1,211 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/common"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	"github.com/zncdatadev/doris-operator/internal/controller/fe"
+	"github.com/zncdatadev/operator-go/pkg/client"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Keys of the discovery ConfigMap and of the connection Secrets
+const (
+	discoveryFEQueryAddressKey = "FE_QUERY_ADDRESS"
+	discoveryFEHTTPAddressKey  = "FE_HTTP_ADDRESS"
+	discoveryFEHTTPURLKey      = "FE_HTTP_URL"
+	discoveryJDBCURLKey        = "JDBC_URL"
+	discoveryBEHTTPAddressKey  = "BE_HTTP_ADDRESS"
+
+	// discoveryFEArrowFlightAddressKey is only published when Arrow Flight is enabled, clients
+	// fetch the results from the BE endpoints returned by FE
+	discoveryFEArrowFlightAddressKey = "FE_ARROW_FLIGHT_ADDRESS"
+	// discoveryBEArrowFlightEndpointsKey lists the comma-separated BE endpoints clients outside
+	// of Kubernetes fetch the results from, once the addresses of the BE listeners are known
+	discoveryBEArrowFlightEndpointsKey = "BE_ARROW_FLIGHT_ENDPOINTS"
+)
+
+// connectionUserLabel marks the connection Secrets published by the operator, with the user
+var connectionUserLabel = dorisv1alpha1.GroupVersion.Group + "/connection-user"
+
+// discoveryConfigMapName returns the ConfigMap publishing the addresses of the cluster
+func discoveryConfigMapName(instance *dorisv1alpha1.DorisCluster) string {
+	return instance.Name + "-discovery"
+}
+
+// connectionSecretName returns the Secret publishing how a Doris user connects to the cluster.
+// Doris user names may contain characters not allowed in Secret names.
+func connectionSecretName(instance *dorisv1alpha1.DorisCluster, user string) string {
+	return instance.Name + "-connection-" + strings.ToLower(strings.ReplaceAll(user, "_", "-"))
+}
+
+// serviceAddress returns the in-cluster address of a Doris Service port
+func serviceAddress(instance *dorisv1alpha1.DorisCluster, componentType constants.ComponentType, port int32) string {
+	host := fmt.Sprintf("%s.%s.svc.%s",
+		common.GetServiceName(instance.Name, componentType, common.ServiceTypeAccess),
+		instance.Namespace, clusterDomain(instance))
+	return net.JoinHostPort(host, strconv.Itoa(int(port)))
+}
+
+// discoveryData returns the addresses clients in Kubernetes connect to the cluster with, and
+// the sorted Arrow Flight endpoints of the BE listeners
+func discoveryData(instance *dorisv1alpha1.DorisCluster, beFlightEndpoints []string) map[string]string {
+	tlsEnabled := common.GetTLSSpec(instance) != nil
+	scheme := common.GetHttpScheme(instance)
+
+	feHTTPPort := int32(constants.FEHttpPort)
+	jdbcURL := fmt.Sprintf("jdbc:mysql://%s/", serviceAddress(instance, constants.ComponentTypeFE, constants.FEQueryPort))
+	if tlsEnabled {
+		feHTTPPort = constants.FEHttpsPort
+		jdbcURL += "?sslMode=REQUIRED"
+	}
+	feHTTPAddress := serviceAddress(instance, constants.ComponentTypeFE, feHTTPPort)
+
+	data := map[string]string{
+		discoveryFEQueryAddressKey: serviceAddress(instance, constants.ComponentTypeFE, constants.FEQueryPort),
+		discoveryFEHTTPAddressKey:  feHTTPAddress,
+		discoveryFEHTTPURLKey:      scheme + "://" + feHTTPAddress,
+		discoveryJDBCURLKey:        jdbcURL,
+		discoveryBEHTTPAddressKey:  serviceAddress(instance, constants.ComponentTypeBE, constants.BEHttpPort),
+	}
+	if port := common.GetArrowFlightPort(instance, constants.ComponentTypeFE); port != 0 {
+		data[discoveryFEArrowFlightAddressKey] = serviceAddress(instance, constants.ComponentTypeFE, port)
+	}
+	if len(beFlightEndpoints) > 0 {
+		data[discoveryBEArrowFlightEndpointsKey] = strings.Join(beFlightEndpoints, ",")
+	}
+	return data
+}
+
+// reconcileDiscovery publishes the discovery ConfigMap of the cluster and the connection
+// Secrets of the configured users, and deletes the connection Secrets no longer configured.
+func (r *DorisClusterReconciler) reconcileDiscovery(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	beFlightEndpoints []string,
+) error {
+	data := discoveryData(instance, beFlightEndpoints)
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: discoveryConfigMapName(instance), Namespace: instance.Namespace},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels[opgpconstants.LabelKubernetesInstance] = instance.Name
+		configMap.Data = data
+		return controllerutil.SetControllerReference(instance, configMap, r.Scheme)
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile discovery ConfigMap %s: %w", configMap.Name, err)
+	}
+
+	credentials, err := r.connectionCredentials(ctx, instance)
+	if err != nil {
+		return err
+	}
+	wanted := map[string]struct{}{}
+	for _, user := range slices.Sorted(maps.Keys(credentials)) {
+		password := credentials[user]
+		name := connectionSecretName(instance, user)
+		if _, ok := wanted[name]; ok {
+			logger.Info("Skipping connection Secret of user, the name is already used by another user",
+				"cluster", instance.Name, "user", user, "secret", name)
+			continue
+		}
+		wanted[name] = struct{}{}
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: instance.Namespace}}
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+			if secret.Labels == nil {
+				secret.Labels = map[string]string{}
+			}
+			secret.Labels[opgpconstants.LabelKubernetesInstance] = instance.Name
+			secret.Labels[connectionUserLabel] = strings.ToLower(strings.ReplaceAll(user, "_", "-"))
+			secret.Type = corev1.SecretTypeOpaque
+			secret.Data = map[string][]byte{
+				corev1.BasicAuthUsernameKey: []byte(user),
+				corev1.BasicAuthPasswordKey: []byte(password),
+			}
+			for k, v := range data {
+				secret.Data[k] = []byte(v)
+			}
+			return controllerutil.SetControllerReference(instance, secret, r.Scheme)
+		}); err != nil {
+			return fmt.Errorf("failed to reconcile connection Secret %s: %w", name, err)
+		}
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := r.List(ctx, secrets, ctrlclient.InNamespace(instance.Namespace),
+		ctrlclient.MatchingLabels{opgpconstants.LabelKubernetesInstance: instance.Name},
+		ctrlclient.HasLabels{connectionUserLabel}); err != nil {
+		return fmt.Errorf("failed to list connection Secrets: %w", err)
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if _, ok := wanted[secret.Name]; ok || !metav1.IsControlledBy(secret, instance) {
+			continue
+		}
+		if err := r.Delete(ctx, secret); ctrlclient.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete connection Secret %s: %w", secret.Name, err)
+		}
+		logger.Info("Deleted connection Secret", "cluster", instance.Name, "secret", secret.Name)
+	}
+	return nil
+}
+
+// connectionCredentials returns the passwords of the users connection Secrets are published
+// for. Users whose password is not known to the operator are skipped.
+func (r *DorisClusterReconciler) connectionCredentials(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (map[string]string, error) {
+	credentials := map[string]string{}
+	if instance.Spec.ClusterConfig == nil || instance.Spec.ClusterConfig.Discovery == nil ||
+		len(instance.Spec.ClusterConfig.Discovery.ConnectionSecretUsers) == 0 {
+		return credentials, nil
+	}
+
+	auth := fe.ResolveAuthentication(ctx, &client.Client{Client: r.Client, OwnerReference: instance},
+		instance.Spec.ClusterConfig.Authentication)
+	known, err := r.getStaticUsers(ctx, instance, auth.Static)
+	if err != nil {
+		return nil, err
+	}
+	delete(known, doris_client.DefaultAdminUser)
+	if instance.Spec.AuthSecret != nil {
+		secret := &corev1.Secret{}
+		key := types.NamespacedName{Name: instance.Spec.AuthSecret.SecretName, Namespace: instance.Namespace}
+		if err := r.Get(ctx, key, secret); ctrlclient.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to get authSecret %s: %w", key, err)
+		} else if err == nil {
+			user, password := doris_client.GetClusterAuthCredentials(secret.Data)
+			known[user] = password
+		}
+	}
+
+	for _, user := range instance.Spec.ClusterConfig.Discovery.ConnectionSecretUsers {
+		password, ok := known[user]
+		if !ok {
+			logger.Info("Skipping connection Secret of user, the password is not managed by the operator",
+				"cluster", instance.Name, "user", user)
+			continue
+		}
+		credentials[user] = password
+	}
+	return credentials, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDiscoveryData(t *testing.T) {
+	instance := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testClusterName, Namespace: testClusterNamespace},
+	}
+	data := discoveryData(instance, nil)
+	want := map[string]string{
+		discoveryFEQueryAddressKey: "test-fe-service.default.svc.cluster.local:9030",
+		discoveryFEHTTPURLKey:      "http://test-fe-service.default.svc.cluster.local:8030",
+		discoveryJDBCURLKey:        "jdbc:mysql://test-fe-service.default.svc.cluster.local:9030/",
+		discoveryBEHTTPAddressKey:  "test-be-service.default.svc.cluster.local:8040",
+	}
+	for k, v := range want {
+		if data[k] != v {
+			t.Errorf("%s = %q, want %q", k, data[k], v)
+		}
+	}
+
+	for _, key := range []string{discoveryFEArrowFlightAddressKey, discoveryBEArrowFlightEndpointsKey} {
+		if _, ok := data[key]; ok {
+			t.Errorf("expected no %s without Arrow Flight", key)
+		}
+	}
+
+	instance.Spec.ClusterConfig = &dorisv1alpha1.ClusterConfigSpec{
+		ClusterDomain: "example.local",
+		TLS:           &dorisv1alpha1.TLSSpec{},
+		ArrowFlight:   &dorisv1alpha1.ArrowFlightSpec{FEPort: 8070},
+	}
+	data = discoveryData(instance, []string{"203.0.113.10:8050", "203.0.113.11:8050"})
+	if got, want := data[discoveryFEArrowFlightAddressKey], "test-fe-service.default.svc.example.local:8070"; got != want {
+		t.Errorf("%s = %q, want %q", discoveryFEArrowFlightAddressKey, got, want)
+	}
+	if got, want := data[discoveryBEArrowFlightEndpointsKey], "203.0.113.10:8050,203.0.113.11:8050"; got != want {
+		t.Errorf("%s = %q, want %q", discoveryBEArrowFlightEndpointsKey, got, want)
+	}
+	if got, want := data[discoveryFEHTTPURLKey], "https://test-fe-service.default.svc.example.local:8050"; got != want {
+		t.Errorf("%s = %q, want %q", discoveryFEHTTPURLKey, got, want)
+	}
+	if got, want := data[discoveryJDBCURLKey], "jdbc:mysql://test-fe-service.default.svc.example.local:9030/?sslMode=REQUIRED"; got != want {
+		t.Errorf("%s = %q, want %q", discoveryJDBCURLKey, got, want)
+	}
+}
+
+func TestReconcileDiscovery(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = dorisv1alpha1.AddToScheme(s)
+
+	instance := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testClusterName, Namespace: testClusterNamespace, UID: "uid"},
+		Spec: dorisv1alpha1.DorisClusterSpec{
+			AuthSecret: &dorisv1alpha1.AuthSecretSpec{SecretName: "admin"},
+			ClusterConfig: &dorisv1alpha1.ClusterConfigSpec{
+				Discovery: &dorisv1alpha1.DiscoverySpec{ConnectionSecretUsers: []string{"doris_admin", "unknown"}},
+			},
+		},
+	}
+	authSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin", Namespace: testClusterNamespace},
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte("doris_admin"),
+			corev1.BasicAuthPasswordKey: []byte("secret"),
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(instance, authSecret).Build()
+	r := &DorisClusterReconciler{Client: cli, Scheme: s}
+
+	if err := r.reconcileDiscovery(ctx, instance, nil); err != nil {
+		t.Fatalf("reconcileDiscovery() error = %v", err)
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: "test-discovery", Namespace: testClusterNamespace}, configMap); err != nil {
+		t.Fatalf("expected discovery ConfigMap: %v", err)
+	}
+	if configMap.Data[discoveryJDBCURLKey] == "" {
+		t.Errorf("expected %s in the discovery ConfigMap", discoveryJDBCURLKey)
+	}
+
+	key := types.NamespacedName{Name: "test-connection-doris-admin", Namespace: testClusterNamespace}
+	secret := &corev1.Secret{}
+	if err := cli.Get(ctx, key, secret); err != nil {
+		t.Fatalf("expected connection Secret %s: %v", key, err)
+	}
+	if string(secret.Data[corev1.BasicAuthUsernameKey]) != "doris_admin" || string(secret.Data[corev1.BasicAuthPasswordKey]) != "secret" {
+		t.Errorf("connection Secret credentials = %s/%s, want doris_admin/secret",
+			secret.Data[corev1.BasicAuthUsernameKey], secret.Data[corev1.BasicAuthPasswordKey])
+	}
+	if err := cli.Get(ctx, types.NamespacedName{Name: "test-connection-unknown", Namespace: testClusterNamespace}, &corev1.Secret{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected no connection Secret for a user unknown to the operator, got %v", err)
+	}
+
+	instance.Spec.ClusterConfig.Discovery = nil
+	if err := r.reconcileDiscovery(ctx, instance, nil); err != nil {
+		t.Fatalf("reconcileDiscovery() error = %v", err)
+	}
+	if err := cli.Get(ctx, key, &corev1.Secret{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected connection Secret to be deleted, got %v", err)
+	}
+}
@@ -3,7 +3,11 @@ package doris_client
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"math"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -29,6 +33,17 @@ const (
 	DefaultAdminUser = "root"
 )
 
+var (
+	// grantablePrivileges are the privileges GrantToRole accepts
+	grantablePrivileges = []string{
+		"NODE_PRIV", "ADMIN_PRIV", "GRANT_PRIV", "SELECT_PRIV", "LOAD_PRIV",
+		"ALTER_PRIV", "CREATE_PRIV", "DROP_PRIV", "USAGE_PRIV", "SHOW_VIEW_PRIV",
+	}
+
+	rolePattern          = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+	grantResourcePattern = regexp.MustCompile(`^[A-Za-z0-9_%*-]+(\.[A-Za-z0-9_%*-]+){0,2}$`)
+)
+
 // FrontendInfo represents information about a Doris FE node
 type FrontendInfo struct {
 	Name        string
@@ -37,34 +52,68 @@ type FrontendInfo struct {
 	QueryPort   int
 	Role        string // FOLLOWER, OBSERVER, MASTER
 	IsMaster    bool
+	Join        bool // whether the FE joined the BDBJE group
 	Alive       bool
+	// ReplayedJournalID is the last edit log entry the FE replayed, followers lag behind the master
+	ReplayedJournalID int
+	Version           string
+	LastStartTime     string
+	LastHeartbeat     string
+	// ErrMsg is the error of the last failed heartbeat
+	ErrMsg string
 }
 
 // BackendInfo represents information about a Doris BE node
 type BackendInfo struct {
+	ID           int64
 	Name         string
 	Host         string
 	Port         int
 	Alive        bool
 	Decommission bool
 	TabletNum    int
+	// MaxDiskUsedPct is the usage percentage of the fullest data disk of the BE
+	MaxDiskUsedPct float64
+	// DataUsedBytes is the size of the Doris data, AvailBytes and TotalBytes sum up the data disks
+	DataUsedBytes int64
+	AvailBytes    int64
+	TotalBytes    int64
+	CPUCores      int
+	Version       string
+	LastStartTime string
+	LastHeartbeat string
+	// ErrMsg is the error of the last failed heartbeat
+	ErrMsg string
+	// Tags are the BE tags without the `tag.` prefix, e.g. location and public_endpoint
+	Tags map[string]string
 }
 
 // BrokerInfo represents information about a Doris Broker node
 type BrokerInfo struct {
-	Name  string
-	Host  string
-	Port  int
-	Alive bool
+	Name          string
+	Host          string
+	Port          int
+	Alive         bool
+	LastStartTime string
+	// ErrMsg is the error of the last failed heartbeat
+	ErrMsg string
 }
 
 // DorisClient wraps a MySQL connection to Doris FE
 type DorisClient struct {
-	db *sql.DB
+	db   *sql.DB
+	host string
+	port int
 }
 
 // NewDorisClient creates a new DorisClient connecting to the FE service
 func NewDorisClient(feHost string, fePort int, user, password string) (*DorisClient, error) {
+	return NewDorisClientWithTLS(feHost, fePort, user, password, "")
+}
+
+// NewDorisClientWithTLS creates a new DorisClient connecting to the FE service over TLS,
+// with a configuration registered by RegisterTLSConfig. An empty name disables TLS.
+func NewDorisClientWithTLS(feHost string, fePort int, user, password, tlsConfig string) (*DorisClient, error) {
 	if fePort == 0 {
 		fePort = defaultQueryPort
 	}
@@ -77,6 +126,9 @@ func NewDorisClient(feHost string, fePort int, user, password string) (*DorisCli
 	dsn.Timeout = defaultConnectionTimeout
 	dsn.ReadTimeout = defaultQueryTimeout
 	dsn.WriteTimeout = defaultQueryTimeout
+	if tlsConfig != "" {
+		dsn.TLSConfig = tlsConfig
+	}
 
 	db, err := sql.Open("mysql", dsn.FormatDSN())
 
@@ -96,7 +148,19 @@ func NewDorisClient(feHost string, fePort int, user, password string) (*DorisCli
 	}
 
 	clientLogger.Info("Connected to Doris FE", "host", feHost, "port", fePort)
-	return &DorisClient{db: db}, nil
+	return &DorisClient{db: db, host: feHost, port: fePort}, nil
+}
+
+// Host returns the FE host the client is connected to
+func (c *DorisClient) Host() string {
+	return c.host
+}
+
+// Ping verifies that the FE connection is still alive
+func (c *DorisClient) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultConnectionTimeout)
+	defer cancel()
+	return c.db.PingContext(ctx)
 }
 
 // Close closes the MySQL connection
@@ -164,9 +228,27 @@ func (c *DorisClient) ShowFrontends(ctx context.Context) ([]FrontendInfo, error)
 		if idx, ok := colIdx["ISMASTER"]; ok {
 			fe.IsMaster = strings.EqualFold(values[idx].String, "true")
 		}
+		if idx, ok := colIdx["JOIN"]; ok {
+			fe.Join = strings.EqualFold(values[idx].String, "true")
+		}
 		if idx, ok := colIdx["ALIVE"]; ok {
 			fe.Alive = strings.EqualFold(values[idx].String, "true")
 		}
+		if idx, ok := colIdx["REPLAYEDJOURNALID"]; ok && values[idx].Valid {
+			fe.ReplayedJournalID = parseInt(values[idx].String)
+		}
+		if idx, ok := colIdx["VERSION"]; ok {
+			fe.Version = values[idx].String
+		}
+		if idx, ok := colIdx["LASTSTARTTIME"]; ok {
+			fe.LastStartTime = values[idx].String
+		}
+		if idx, ok := colIdx["LASTHEARTBEAT"]; ok {
+			fe.LastHeartbeat = values[idx].String
+		}
+		if idx, ok := colIdx["ERRMSG"]; ok {
+			fe.ErrMsg = values[idx].String
+		}
 
 		frontends = append(frontends, fe)
 	}
@@ -240,6 +322,9 @@ func (c *DorisClient) ShowBackends(ctx context.Context) ([]BackendInfo, error) {
 		}
 
 		be := BackendInfo{}
+		if idx, ok := colIdx["BACKENDID"]; ok && values[idx].Valid {
+			be.ID, _ = strconv.ParseInt(values[idx].String, 10, 64)
+		}
 		if idx, ok := colIdx["NAME"]; ok {
 			be.Name = values[idx].String
 		}
@@ -258,6 +343,36 @@ func (c *DorisClient) ShowBackends(ctx context.Context) ([]BackendInfo, error) {
 		if idx, ok := colIdx["TABLETNUM"]; ok && values[idx].Valid {
 			be.TabletNum = parseInt(values[idx].String)
 		}
+		if idx, ok := colIdx["MAXDISKUSEDPCT"]; ok && values[idx].Valid {
+			be.MaxDiskUsedPct = parsePercent(values[idx].String)
+		}
+		if idx, ok := colIdx["DATAUSEDCAPACITY"]; ok && values[idx].Valid {
+			be.DataUsedBytes = parseCapacity(values[idx].String)
+		}
+		if idx, ok := colIdx["AVAILCAPACITY"]; ok && values[idx].Valid {
+			be.AvailBytes = parseCapacity(values[idx].String)
+		}
+		if idx, ok := colIdx["TOTALCAPACITY"]; ok && values[idx].Valid {
+			be.TotalBytes = parseCapacity(values[idx].String)
+		}
+		if idx, ok := colIdx["CPUCORES"]; ok && values[idx].Valid {
+			be.CPUCores = parseInt(values[idx].String)
+		}
+		if idx, ok := colIdx["VERSION"]; ok {
+			be.Version = values[idx].String
+		}
+		if idx, ok := colIdx["LASTSTARTTIME"]; ok {
+			be.LastStartTime = values[idx].String
+		}
+		if idx, ok := colIdx["LASTHEARTBEAT"]; ok {
+			be.LastHeartbeat = values[idx].String
+		}
+		if idx, ok := colIdx["ERRMSG"]; ok {
+			be.ErrMsg = values[idx].String
+		}
+		if idx, ok := colIdx["TAG"]; ok && values[idx].Valid {
+			be.Tags = parseBackendTags(values[idx].String)
+		}
 
 		backends = append(backends, be)
 	}
@@ -311,6 +426,12 @@ func (c *DorisClient) ShowBrokers(ctx context.Context) ([]BrokerInfo, error) {
 		if idx, ok := colIdx["ALIVE"]; ok {
 			bi.Alive = strings.EqualFold(values[idx].String, "true")
 		}
+		if idx, ok := colIdx["LASTSTARTTIME"]; ok {
+			bi.LastStartTime = values[idx].String
+		}
+		if idx, ok := colIdx["ERRMSG"]; ok {
+			bi.ErrMsg = values[idx].String
+		}
 
 		brokers = append(brokers, bi)
 	}
@@ -327,6 +448,39 @@ func (c *DorisClient) DecommissionBackend(ctx context.Context, host string, port
 	return c.exec(ctx, query)
 }
 
+// SetBackendTags replaces the tags of a BE node. Doris replaces all the tags of the node,
+// so tags must contain the tags to keep, e.g. location.
+func (c *DorisClient) SetBackendTags(ctx context.Context, host string, port int, tags map[string]string) error {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	properties := make([]string, 0, len(keys))
+	for _, key := range keys {
+		properties = append(properties, fmt.Sprintf("'tag.%s' = '%s'", escapeSQLString(key), escapeSQLString(tags[key])))
+	}
+	query := fmt.Sprintf("ALTER SYSTEM MODIFY BACKEND \"%s:%d\" SET (%s)", host, port, strings.Join(properties, ", "))
+	if err := c.exec(ctx, query); err != nil {
+		return fmt.Errorf("failed to set tags of BE %s:%d: %w", host, port, err)
+	}
+	return nil
+}
+
+// parseBackendTags parses the Tag column of SHOW BACKENDS, a JSON object such as
+// `{"location" : "default"}`. An unparsable value returns no tags.
+func parseBackendTags(value string) map[string]string {
+	var raw map[string]any
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return nil
+	}
+	tags := make(map[string]string, len(raw))
+	for key, v := range raw {
+		tags[key] = fmt.Sprint(v)
+	}
+	return tags
+}
+
 // DropBackend forcibly removes a BE node
 func (c *DorisClient) DropBackend(ctx context.Context, host string, port int) error {
 	query := fmt.Sprintf("ALTER SYSTEM DROP BACKEND \"%s:%d\"", host, port)
@@ -339,6 +493,12 @@ func (c *DorisClient) DropObserver(ctx context.Context, host string, port int) e
 	return c.exec(ctx, query)
 }
 
+// DropFollower removes an FE follower node, the remaining followers must keep a quorum
+func (c *DorisClient) DropFollower(ctx context.Context, host string, port int) error {
+	query := fmt.Sprintf("ALTER SYSTEM DROP FOLLOWER \"%s:%d\"", host, port)
+	return c.exec(ctx, query)
+}
+
 // exec executes a DDL/management statement
 func (c *DorisClient) exec(ctx context.Context, query string) error {
 	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
@@ -378,6 +538,55 @@ func (c *DorisClient) InitializeAdminUser(ctx context.Context, username, passwor
 	return nil
 }
 
+// CreateUser creates a user without privileges if it does not exist yet
+func (c *DorisClient) CreateUser(ctx context.Context, username, password string) error {
+	createUserSQL := fmt.Sprintf(
+		"CREATE USER IF NOT EXISTS '%s'@'%%' IDENTIFIED BY '%s'",
+		escapeSQLString(username), escapeSQLString(password),
+	)
+	if err := c.exec(ctx, createUserSQL); err != nil {
+		return fmt.Errorf("failed to create user %s: %w", username, err)
+	}
+	authLogger.Info("Created user", "user", username)
+	return nil
+}
+
+// SetUserPassword sets the password of a user
+func (c *DorisClient) SetUserPassword(ctx context.Context, username, password string) error {
+	setPasswordSQL := fmt.Sprintf(
+		"SET PASSWORD FOR '%s'@'%%' = PASSWORD('%s')",
+		escapeSQLString(username), escapeSQLString(password),
+	)
+	if err := c.exec(ctx, setPasswordSQL); err != nil {
+		return fmt.Errorf("failed to set password of user %s: %w", username, err)
+	}
+	authLogger.Info("Set user password", "user", username)
+	return nil
+}
+
+// AlterUserPassword changes the password of a user
+func (c *DorisClient) AlterUserPassword(ctx context.Context, username, password string) error {
+	alterUserSQL := fmt.Sprintf(
+		"ALTER USER '%s'@'%%' IDENTIFIED BY '%s'",
+		escapeSQLString(username), escapeSQLString(password),
+	)
+	if err := c.exec(ctx, alterUserSQL); err != nil {
+		return fmt.Errorf("failed to alter password of user %s: %w", username, err)
+	}
+	authLogger.Info("Altered user password", "user", username)
+	return nil
+}
+
+// DropUser drops a user if it exists
+func (c *DorisClient) DropUser(ctx context.Context, username string) error {
+	dropUserSQL := fmt.Sprintf("DROP USER IF EXISTS '%s'@'%%'", escapeSQLString(username))
+	if err := c.exec(ctx, dropUserSQL); err != nil {
+		return fmt.Errorf("failed to drop user %s: %w", username, err)
+	}
+	authLogger.Info("Dropped user", "user", username)
+	return nil
+}
+
 // CheckUserExists checks if a Doris user exists by querying the mysql.user table.
 func (c *DorisClient) CheckUserExists(ctx context.Context, username string) (bool, error) {
 	query := fmt.Sprintf(
@@ -397,6 +606,102 @@ func (c *DorisClient) CheckUserExists(ctx context.Context, username string) (boo
 	return count > 0, nil
 }
 
+// SetLDAPAdminPassword stores the password Doris binds to the LDAP server with
+func (c *DorisClient) SetLDAPAdminPassword(ctx context.Context, password string) error {
+	setPasswordSQL := fmt.Sprintf("SET LDAP_ADMIN_PASSWORD = PASSWORD('%s')", escapeSQLString(password))
+	if err := c.exec(ctx, setPasswordSQL); err != nil {
+		return fmt.Errorf("failed to set LDAP admin password: %w", err)
+	}
+	authLogger.Info("Set LDAP admin password")
+	return nil
+}
+
+// CheckRoleExists checks if a Doris role exists
+func (c *DorisClient) CheckRoleExists(ctx context.Context, role string) (bool, error) {
+	rows, err := c.queryRows(ctx, "SHOW ROLES")
+	if err != nil {
+		return false, fmt.Errorf("failed to show roles: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return false, fmt.Errorf("failed to get role columns: %w", err)
+	}
+	nameIdx := -1
+	for i, name := range columns {
+		if strings.EqualFold(name, "Name") {
+			nameIdx = i
+		}
+	}
+	if nameIdx < 0 {
+		return false, fmt.Errorf("SHOW ROLES returned no Name column")
+	}
+
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return false, fmt.Errorf("failed to scan role row: %w", err)
+		}
+		if values[nameIdx].String == role {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// CreateRole creates a role if it does not exist yet
+func (c *DorisClient) CreateRole(ctx context.Context, role string) error {
+	if !rolePattern.MatchString(role) {
+		return fmt.Errorf("invalid role name %q", role)
+	}
+	exists, err := c.CheckRoleExists(ctx, role)
+	if err != nil || exists {
+		return err
+	}
+	if err := c.exec(ctx, fmt.Sprintf("CREATE ROLE '%s'", role)); err != nil {
+		return fmt.Errorf("failed to create role %s: %w", role, err)
+	}
+	authLogger.Info("Created role", "role", role)
+	return nil
+}
+
+// GrantToRole grants privileges on a resource to a role
+func (c *DorisClient) GrantToRole(ctx context.Context, role string, privileges []string, on string) error {
+	grantSQL, err := grantToRoleSQL(role, privileges, on)
+	if err != nil {
+		return err
+	}
+	if err := c.exec(ctx, grantSQL); err != nil {
+		return fmt.Errorf("failed to grant %s on %s to role %s: %w", strings.Join(privileges, ", "), on, role, err)
+	}
+	return nil
+}
+
+// grantToRoleSQL builds a GRANT statement. Names cannot be escaped in the resource of a
+// GRANT, so every part of the statement is validated instead.
+func grantToRoleSQL(role string, privileges []string, on string) (string, error) {
+	if !rolePattern.MatchString(role) {
+		return "", fmt.Errorf("invalid role name %q", role)
+	}
+	if len(privileges) == 0 {
+		return "", fmt.Errorf("no privileges to grant to role %s", role)
+	}
+	for _, privilege := range privileges {
+		if !slices.Contains(grantablePrivileges, privilege) {
+			return "", fmt.Errorf("invalid privilege %q", privilege)
+		}
+	}
+	if !grantResourcePattern.MatchString(on) {
+		return "", fmt.Errorf("invalid grant resource %q", on)
+	}
+	return fmt.Sprintf("GRANT %s ON %s TO ROLE '%s'", strings.Join(privileges, ", "), on, role), nil
+}
+
 // IsDecommissionComplete checks if a BE has finished decommissioning
 func IsDecommissionComplete(be BackendInfo) bool {
 	return be.Decommission && be.TabletNum == 0
@@ -410,25 +715,36 @@ func ResolvePodHost(podName, namespace, clusterDomain string) string {
 	return fmt.Sprintf("%s.%s.svc.%s", podName, namespace, clusterDomain)
 }
 
-// MatchPodToBackend matches a K8s pod name to a Doris BE node by hostname substring match.
-// Doris registers nodes using their pod hostname, so string matching is sufficient.
+// HostOfPod reports whether a node registered in Doris with host runs in the pod. The host is
+// the pod name, a DNS name of the pod, or the pod IP when podIP is set; a prefix match would
+// give pod be-1 the node of be-10. When dnsSuffix (".<namespace>.svc.<cluster domain>") is
+// set, a cluster DNS name must end with it: nodes registered before a clusterDomain change
+// do not run in the pod anymore.
+func HostOfPod(host, podName, podIP, dnsSuffix string) bool {
+	if host == podName || (podIP != "" && host == podIP) {
+		return true
+	}
+	if !strings.HasPrefix(host, podName+".") {
+		return false
+	}
+	return dnsSuffix == "" || !strings.Contains(host, ".svc.") || strings.HasSuffix(host, dnsSuffix)
+}
+
+// MatchPodToBackend returns the Doris BE node registered with the host of a K8s pod
 func MatchPodToBackend(podName string, backends []BackendInfo) *BackendInfo {
 	for i := range backends {
-		be := &backends[i]
-		if strings.Contains(be.Host, podName) || be.Host == podName {
-			return be
+		if HostOfPod(backends[i].Host, podName, "", "") {
+			return &backends[i]
 		}
 	}
 	return nil
 }
 
-// MatchPodToFrontend matches a K8s pod name to a Doris FE node by hostname substring match.
-// Doris registers nodes using their pod hostname, so string matching is sufficient.
+// MatchPodToFrontend returns the Doris FE node registered with the host of a K8s pod
 func MatchPodToFrontend(podName string, frontends []FrontendInfo) *FrontendInfo {
 	for i := range frontends {
-		fe := &frontends[i]
-		if strings.Contains(fe.Host, podName) || fe.Host == podName {
-			return fe
+		if HostOfPod(frontends[i].Host, podName, "", "") {
+			return &frontends[i]
 		}
 	}
 	return nil
@@ -444,6 +760,31 @@ func parseInt(s string) int {
 	return v
 }
 
+// parsePercent parses a percentage shown by Doris, e.g. "12.34 %"
+func parsePercent(s string) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "%")), 64)
+	if err != nil {
+		clientLogger.V(1).Info("Failed to parse percent value", "value", s, "error", err)
+		return 0
+	}
+	return v
+}
+
+// capacityUnits are the units of the capacities shown by Doris, in powers of 1024
+var capacityUnits = []string{"B", "KB", "MB", "GB", "TB", "PB"}
+
+// parseCapacity parses a capacity shown by Doris, e.g. "1.234 GB", into bytes
+func parseCapacity(s string) int64 {
+	value, unit, _ := strings.Cut(strings.TrimSpace(s), " ")
+	exponent := slices.Index(capacityUnits, strings.ToUpper(strings.TrimSpace(unit)))
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil || exponent < 0 {
+		clientLogger.V(1).Info("Failed to parse capacity value", "value", s)
+		return 0
+	}
+	return int64(math.Round(v * math.Pow(1024, float64(exponent))))
+}
+
 // escapeSQLString escapes single quotes and backslashes in SQL string values
 // using MySQL double-escape convention (” for ', \\ for \).
 func escapeSQLString(s string) string {
@@ -16,7 +16,10 @@ limitations under the License.
 
 package doris_client
 
-import "testing"
+import (
+	"slices"
+	"testing"
+)
 
 const (
 	testBEPodFQDN = "doris-sample-be-default-0"
@@ -93,6 +96,71 @@ func TestEscapeSQLString(t *testing.T) {
 	}
 }
 
+func TestGrantToRoleSQL(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       string
+		privileges []string
+		on         string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "catalog level grant",
+			role:       "analysts",
+			privileges: []string{"SELECT_PRIV", "SHOW_VIEW_PRIV"},
+			on:         "internal.sales.*",
+			want:       "GRANT SELECT_PRIV, SHOW_VIEW_PRIV ON internal.sales.* TO ROLE 'analysts'",
+		},
+		{
+			name:       "global grant",
+			role:       "doris-admins",
+			privileges: []string{"ADMIN_PRIV"},
+			on:         "*.*.*",
+			want:       "GRANT ADMIN_PRIV ON *.*.* TO ROLE 'doris-admins'",
+		},
+		{
+			name:       "quote in role name",
+			role:       "x' OR '1",
+			privileges: []string{"SELECT_PRIV"},
+			on:         "*.*.*",
+			wantErr:    true,
+		},
+		{
+			name:       "unknown privilege",
+			role:       "analysts",
+			privileges: []string{"ALL"},
+			on:         "*.*.*",
+			wantErr:    true,
+		},
+		{
+			name:       "statement in resource",
+			role:       "analysts",
+			privileges: []string{"SELECT_PRIV"},
+			on:         "*.*.*; DROP DATABASE db1",
+			wantErr:    true,
+		},
+		{
+			name:    "no privileges",
+			role:    "analysts",
+			on:      "*.*.*",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := grantToRoleSQL(tt.role, tt.privileges, tt.on)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("grantToRoleSQL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("grantToRoleSQL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetClusterAuthCredentials(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -151,6 +219,37 @@ func TestGetClusterAuthCredentials(t *testing.T) {
 	}
 }
 
+func TestHostOfPod(t *testing.T) {
+	const dnsSuffix = ".default.svc.cluster.local"
+	tests := []struct {
+		host      string
+		dnsSuffix string
+		want      bool
+	}{
+		{"test-be-default-1", dnsSuffix, true},
+		{"10.0.0.11", dnsSuffix, true},
+		{"test-be-default-1.test-be-default", dnsSuffix, true},
+		{"test-be-default-1.test-be-default.default.svc.cluster.local", dnsSuffix, true},
+		// Registered before the clusterDomain changed
+		{"test-be-default-1.test-be-default.default.svc.old.local", dnsSuffix, false},
+		{"test-be-default-1.test-be-default.other.svc.cluster.local", dnsSuffix, false},
+		// Any DNS name of the pod without a suffix
+		{"test-be-default-1.test-be-default.default.svc.old.local", "", true},
+		// Not matched by a pod name prefix
+		{"test-be-default-10.test-be-default", dnsSuffix, false},
+		{"test-be-default-10", "", false},
+		{"test-be-old-1.test-be-old", dnsSuffix, false},
+	}
+	for _, tt := range tests {
+		if got := HostOfPod(tt.host, "test-be-default-1", "10.0.0.11", tt.dnsSuffix); got != tt.want {
+			t.Errorf("HostOfPod(%q, %q) = %v, want %v", tt.host, tt.dnsSuffix, got, tt.want)
+		}
+	}
+	if HostOfPod("10.0.0.11", "test-be-default-1", "", "") {
+		t.Error("HostOfPod() matched an IP without a pod IP")
+	}
+}
+
 func TestMatchPodToBackend(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -167,13 +266,21 @@ func TestMatchPodToBackend(t *testing.T) {
 			want: true,
 		},
 		{
-			name:    "substring match",
-			podName: "be-default-0",
+			name:    "DNS name of the pod",
+			podName: testBEPodFQDN,
 			backends: []BackendInfo{
-				{Host: testBEPodFQDN, Port: 9050},
+				{Host: testBEPodFQDN + ".doris-sample-be-default.default.svc.cluster.local", Port: 9050},
 			},
 			want: true,
 		},
+		{
+			name:    "pod name prefix of another pod",
+			podName: "doris-sample-be-default-1",
+			backends: []BackendInfo{
+				{Host: "doris-sample-be-default-10.doris-sample-be-default.default.svc.cluster.local", Port: 9050},
+			},
+			want: false,
+		},
 		{
 			name:    "no match",
 			podName: "other-pod-0",
@@ -190,7 +297,7 @@ func TestMatchPodToBackend(t *testing.T) {
 		},
 		{
 			name:    "matches correct one among multiple",
-			podName: "be-default-1",
+			podName: "doris-sample-be-default-1",
 			backends: []BackendInfo{
 				{Host: testBEPodFQDN, Port: 9050},
 				{Host: "doris-sample-be-default-1", Port: 9050},
@@ -226,13 +333,21 @@ func TestMatchPodToFrontend(t *testing.T) {
 			want: true,
 		},
 		{
-			name:    "substring match",
-			podName: "fe-default-0",
+			name:    "DNS name of the pod",
+			podName: testFEPodFQDN,
 			frontends: []FrontendInfo{
-				{Host: testFEPodFQDN},
+				{Host: testFEPodFQDN + ".doris-sample-fe-default.default.svc.cluster.local"},
 			},
 			want: true,
 		},
+		{
+			name:    "pod name prefix of another pod",
+			podName: "doris-sample-fe-default-1",
+			frontends: []FrontendInfo{
+				{Host: "doris-sample-fe-default-10.doris-sample-fe-default.default.svc.cluster.local"},
+			},
+			want: false,
+		},
 		{
 			name:      "no match",
 			podName:   "other-pod-0",
@@ -251,6 +366,20 @@ func TestMatchPodToFrontend(t *testing.T) {
 	}
 }
 
+func TestParseUnhealthyTabletIDs(t *testing.T) {
+	row := map[string]string{
+		"UNRECOVERABLETABLETS":     "10012",
+		"REPLICAMISSINGTABLETS":    "10013, 10014",
+		"REDUNDANTTABLETS":         "10015",
+		"VERSIONINCOMPLETETABLETS": "",
+	}
+	got := parseUnhealthyTabletIDs(row)
+	want := []int64{10012, 10013, 10014}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseUnhealthyTabletIDs() = %v, want %v", got, want)
+	}
+}
+
 func TestIsDecommissionComplete(t *testing.T) {
 	tests := []struct {
 		name string
@@ -287,3 +416,55 @@ func TestIsDecommissionComplete(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePercent(t *testing.T) {
+	for value, want := range map[string]float64{"12.34 %": 12.34, "0.00 %": 0, "85%": 85, "": 0, "n/a": 0} {
+		if got := parsePercent(value); got != want {
+			t.Errorf("parsePercent(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestParseCapacity(t *testing.T) {
+	for value, want := range map[string]int64{
+		"0.000 B":   0,
+		"512.000 B": 512,
+		"1.500 KB":  1536,
+		"2.000 GB":  2 << 30,
+		"1.000 TB":  1 << 40,
+		"invalid":   0,
+	} {
+		if got := parseCapacity(value); got != want {
+			t.Errorf("parseCapacity(%q) = %d, want %d", value, got, want)
+		}
+	}
+}
+
+func TestParseBackendTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  map[string]string
+	}{
+		{name: "location", value: `{"location" : "default"}`, want: map[string]string{"location": "default"}},
+		{
+			name:  "public endpoint",
+			value: `{"location" : "default", "public_endpoint" : "203.0.113.10:8040"}`,
+			want:  map[string]string{"location": "default", "public_endpoint": "203.0.113.10:8040"},
+		},
+		{name: "invalid", value: "location:default", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseBackendTags(tt.value)
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseBackendTags(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("parseBackendTags(%q)[%s] = %q, want %q", tt.value, k, got[k], v)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package doris_client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TabletHealth sums up the tablets reported by SHOW PROC '/cluster_health/tablet_health'
+type TabletHealth struct {
+	TabletNum  int
+	HealthyNum int
+	// ReplicaMissingNum counts the tablets with fewer healthy replicas than configured
+	ReplicaMissingNum int
+	// VersionIncompleteNum counts the tablets with replicas missing data versions
+	VersionIncompleteNum int
+	// UnrecoverableNum counts the tablets without any healthy replica left
+	UnrecoverableNum int
+	// CompactionTooSlowNum counts the tablets with replicas having too many versions to compact
+	CompactionTooSlowNum int
+}
+
+// TabletReplica is a replica of a tablet
+type TabletReplica struct {
+	BackendID int64
+	// Version is the last data version of the replica
+	Version int64
+	// LastFailedVersion is the version the replica failed to load, -1 when none failed
+	LastFailedVersion int64
+	Bad               bool
+}
+
+// unhealthyTabletColumns are the columns of SHOW PROC '/cluster_health/tablet_health/<dbId>'
+// listing the tablets missing healthy replicas
+var unhealthyTabletColumns = []string{
+	"UNRECOVERABLETABLETS",
+	"REPLICAMISSINGTABLETS",
+	"VERSIONINCOMPLETETABLETS",
+	"REPLICARELOCATINGTABLETS",
+	"NEEDFURTHERREPAIRTABLETS",
+	"COLOCATEMISMATCHTABLETS",
+}
+
+// IsHealthy returns true when all the tablets are healthy
+func (h TabletHealth) IsHealthy() bool {
+	return h.HealthyNum >= h.TabletNum
+}
+
+// GetFrontendConfig returns the value of a config of the FE the client is connected to
+func (c *DorisClient) GetFrontendConfig(ctx context.Context, key string) (string, error) {
+	rows, err := queryMaps(ctx, c.db, fmt.Sprintf("SHOW FRONTEND CONFIG LIKE '%s'", escapeSQLString(key)))
+	if err != nil {
+		return "", fmt.Errorf("failed to show frontend config %s: %w", key, err)
+	}
+	// The pattern of LIKE matches more than the key when it contains underscores
+	for _, row := range rows {
+		if row["KEY"] == key {
+			return row["VALUE"], nil
+		}
+	}
+	return "", fmt.Errorf("frontend config %s not found", key)
+}
+
+// SetAllFrontendsConfig sets a mutable config on all the FEs. The value is not persisted
+// and is lost when an FE restarts.
+func (c *DorisClient) SetAllFrontendsConfig(ctx context.Context, key, value string) error {
+	query := fmt.Sprintf("ADMIN SET ALL FRONTENDS CONFIG ('%s' = '%s')", escapeSQLString(key), escapeSQLString(value))
+	if err := c.exec(ctx, query); err != nil {
+		return fmt.Errorf("failed to set frontend config %s: %w", key, err)
+	}
+	return nil
+}
+
+// GetTabletHealth returns the number of tablets of the cluster and how many are healthy
+func (c *DorisClient) GetTabletHealth(ctx context.Context) (*TabletHealth, error) {
+	rows, err := queryMaps(ctx, c.db, "SHOW PROC '/cluster_health/tablet_health'")
+	if err != nil {
+		return nil, fmt.Errorf("failed to show tablet health: %w", err)
+	}
+
+	health := &TabletHealth{}
+	for _, row := range rows {
+		// The total row sums up the databases
+		if row["DBID"] == "Total" {
+			continue
+		}
+		health.TabletNum += parseInt(row["TABLETNUM"])
+		health.HealthyNum += parseInt(row["HEALTHYNUM"])
+		health.ReplicaMissingNum += parseInt(row["REPLICAMISSINGNUM"])
+		health.VersionIncompleteNum += parseInt(row["VERSIONINCOMPLETENUM"])
+		health.UnrecoverableNum += parseInt(row["UNRECOVERABLENUM"])
+		health.CompactionTooSlowNum += parseInt(row["REPLICACOMPACTIONTOOSLOWNUM"])
+	}
+	return health, nil
+}
+
+// GetUnhealthyTabletIDs returns the IDs of the tablets missing healthy replicas, at most
+// limit of them. It also returns whether further tablets were left out.
+func (c *DorisClient) GetUnhealthyTabletIDs(ctx context.Context, limit int) ([]int64, bool, error) {
+	rows, err := queryMaps(ctx, c.db, "SHOW PROC '/cluster_health/tablet_health'")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to show tablet health: %w", err)
+	}
+
+	seen := map[int64]bool{}
+	var ids []int64
+	for _, row := range rows {
+		dbID := row["DBID"]
+		if dbID == "Total" || parseInt(row["HEALTHYNUM"]) >= parseInt(row["TABLETNUM"]) {
+			continue
+		}
+		if _, err := strconv.ParseInt(dbID, 10, 64); err != nil {
+			return nil, false, fmt.Errorf("invalid database ID %q in tablet health", dbID)
+		}
+		dbRows, err := queryMaps(ctx, c.db, fmt.Sprintf("SHOW PROC '/cluster_health/tablet_health/%s'", dbID))
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to show tablet health of database %s: %w", dbID, err)
+		}
+		for _, dbRow := range dbRows {
+			for _, id := range parseUnhealthyTabletIDs(dbRow) {
+				if seen[id] {
+					continue
+				}
+				if len(ids) >= limit {
+					return ids, true, nil
+				}
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, false, nil
+}
+
+// parseUnhealthyTabletIDs returns the tablet IDs listed in the unhealthy tablet columns of a
+// database tablet health row, comma separated
+func parseUnhealthyTabletIDs(row map[string]string) []int64 {
+	var ids []int64
+	for _, column := range unhealthyTabletColumns {
+		for _, field := range strings.Split(row[column], ",") {
+			if id, err := strconv.ParseInt(strings.TrimSpace(field), 10, 64); err == nil {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// GetTabletReplicas returns the replicas of a tablet, from the detail command SHOW TABLET returns
+func (c *DorisClient) GetTabletReplicas(ctx context.Context, tabletID int64) ([]TabletReplica, error) {
+	rows, err := queryMaps(ctx, c.db, fmt.Sprintf("SHOW TABLET %d", tabletID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to show tablet %d: %w", tabletID, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("tablet %d not found", tabletID)
+	}
+	detailCmd := strings.TrimSuffix(strings.TrimSpace(rows[0]["DETAILCMD"]), ";")
+	if !strings.HasPrefix(detailCmd, "SHOW PROC '/dbs/") {
+		return nil, fmt.Errorf("unexpected detail command %q of tablet %d", detailCmd, tabletID)
+	}
+
+	replicaRows, err := queryMaps(ctx, c.db, detailCmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to show replicas of tablet %d: %w", tabletID, err)
+	}
+	replicas := make([]TabletReplica, 0, len(replicaRows))
+	for _, row := range replicaRows {
+		replica := TabletReplica{LastFailedVersion: -1, Bad: strings.EqualFold(row["ISBAD"], "true")}
+		replica.BackendID, _ = strconv.ParseInt(row["BACKENDID"], 10, 64)
+		replica.Version, _ = strconv.ParseInt(row["VERSION"], 10, 64)
+		if failed, err := strconv.ParseInt(row["LSTFAILEDVERSION"], 10, 64); err == nil {
+			replica.LastFailedVersion = failed
+		}
+		replicas = append(replicas, replica)
+	}
+	return replicas, nil
+}
@@ -0,0 +1,168 @@
+package doris_client
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Backup job states reported by SHOW BACKUP
+const (
+	BackupStateFinished  = "FINISHED"
+	BackupStateCancelled = "CANCELLED"
+)
+
+// systemDatabases are internal Doris databases that cannot be backed up or hold no user loads
+var systemDatabases = map[string]bool{
+	"information_schema": true,
+	"mysql":              true,
+	"__internal_schema":  true,
+}
+
+// RoutineLoadInfo represents a Doris routine load job
+type RoutineLoadInfo struct {
+	Name   string
+	DbName string
+	State  string // NEED_SCHEDULE, RUNNING, PAUSED, STOPPED, CANCELLED
+}
+
+// BackupJobInfo represents a Doris backup job
+type BackupJobInfo struct {
+	SnapshotName string
+	DbName       string
+	State        string
+	Status       string
+}
+
+// queryer is implemented by *sql.DB and *sql.Conn
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// queryMaps executes a query and returns each row as a map keyed by upper-cased column name
+func queryMaps(ctx context.Context, q queryer, query string) ([]map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []map[string]string
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]string, len(columns))
+		for i, name := range columns {
+			row[strings.ToUpper(name)] = values[i].String
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// quoteIdentifier quotes a database, table or label name with backticks
+func quoteIdentifier(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+// IsSystemDatabase returns true for internal Doris databases
+func IsSystemDatabase(name string) bool {
+	return systemDatabases[strings.ToLower(name)]
+}
+
+// ShowDatabases returns the names of all user databases, excluding internal ones
+func (c *DorisClient) ShowDatabases(ctx context.Context) ([]string, error) {
+	rows, err := queryMaps(ctx, c.db, "SHOW DATABASES")
+	if err != nil {
+		return nil, fmt.Errorf("failed to show databases: %w", err)
+	}
+
+	var databases []string
+	for _, row := range rows {
+		name := row["DATABASE"]
+		if name == "" || IsSystemDatabase(name) {
+			continue
+		}
+		databases = append(databases, name)
+	}
+	return databases, nil
+}
+
+// ShowRoutineLoads returns the routine load jobs of a database that are not stopped or cancelled
+func (c *DorisClient) ShowRoutineLoads(ctx context.Context, database string) ([]RoutineLoadInfo, error) {
+	// SHOW ROUTINE LOAD only works on the current database, so pin a connection for USE
+	conn, err := c.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err := conn.ExecContext(ctx, "USE "+quoteIdentifier(database)); err != nil {
+		return nil, fmt.Errorf("failed to use database %s: %w", database, err)
+	}
+
+	rows, err := queryMaps(ctx, conn, "SHOW ROUTINE LOAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to show routine loads of %s: %w", database, err)
+	}
+
+	jobs := make([]RoutineLoadInfo, 0, len(rows))
+	for _, row := range rows {
+		jobs = append(jobs, RoutineLoadInfo{
+			Name:   row["NAME"],
+			DbName: database,
+			State:  row["STATE"],
+		})
+	}
+	return jobs, nil
+}
+
+// PauseRoutineLoad pauses a routine load job
+func (c *DorisClient) PauseRoutineLoad(ctx context.Context, database, name string) error {
+	query := fmt.Sprintf("PAUSE ROUTINE LOAD FOR %s.%s", quoteIdentifier(database), quoteIdentifier(name))
+	return c.exec(ctx, query)
+}
+
+// BackupDatabase starts a snapshot backup of a whole database to an existing repository
+func (c *DorisClient) BackupDatabase(ctx context.Context, database, label, repository string) error {
+	query := fmt.Sprintf("BACKUP SNAPSHOT %s.%s TO %s",
+		quoteIdentifier(database), quoteIdentifier(label), quoteIdentifier(repository))
+	return c.exec(ctx, query)
+}
+
+// GetBackupJob returns the backup job of a database with the given snapshot label, or nil if none exists
+func (c *DorisClient) GetBackupJob(ctx context.Context, database, label string) (*BackupJobInfo, error) {
+	rows, err := queryMaps(ctx, c.db, "SHOW BACKUP FROM "+quoteIdentifier(database))
+	if err != nil {
+		return nil, fmt.Errorf("failed to show backup of %s: %w", database, err)
+	}
+
+	for _, row := range rows {
+		if row["SNAPSHOTNAME"] != label {
+			continue
+		}
+		return &BackupJobInfo{
+			SnapshotName: row["SNAPSHOTNAME"],
+			DbName:       database,
+			State:        row["STATE"],
+			Status:       row["STATUS"],
+		}, nil
+	}
+	return nil, nil
+}
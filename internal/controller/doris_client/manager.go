@@ -0,0 +1,347 @@
+package doris_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// defaultHealthCheckInterval is how long a pooled client is trusted before it is pinged
+	// again and its master routing is verified
+	defaultHealthCheckInterval = 30 * time.Second
+
+	// defaultIdleTimeout is how long an unused pooled client is kept open
+	defaultIdleTimeout = 10 * time.Minute
+)
+
+// ClusterTarget describes how to reach the FEs of a Doris cluster
+type ClusterTarget struct {
+	// UID of the DorisCluster
+	UID types.UID
+
+	// ServiceHost is the load-balanced FE service name
+	ServiceHost string
+
+	// PodHosts are the FE pod addresses tried in turn when the service is unreachable
+	PodHosts []string
+
+	// Port is the FE query port
+	Port int
+
+	User     string
+	Password string
+
+	// CredentialVersion changes whenever the credentials or the CA change, e.g. the Secret
+	// resourceVersion. A new version replaces the pooled clients of the cluster.
+	CredentialVersion string
+
+	// TLSConfig is the name of the TLS configuration registered with RegisterTLSConfig.
+	// Empty when TLS is disabled.
+	TLSConfig string
+}
+
+// clientKey identifies a pooled client
+type clientKey struct {
+	uid     types.UID
+	user    string
+	version string
+}
+
+// userKey identifies the clients of a cluster and user across credential versions
+type userKey struct {
+	uid  types.UID
+	user string
+}
+
+// pooledClient is a cached client and its bookkeeping
+type pooledClient struct {
+	client      *DorisClient
+	lastChecked time.Time
+	lastUsed    time.Time
+
+	// refs is the number of callers holding the client, from Get until Put
+	refs int
+
+	// retiredAt is set when the client left the pool; it is closed once no caller holds it
+	retiredAt time.Time
+}
+
+// ClientManager keeps one long-lived DorisClient per cluster and credential version.
+// Clients are connected to the master FE, so that management statements are not
+// forwarded by a follower; they are health checked and re-routed after a master switch,
+// and closed when idle. Clients returned by Get are owned by the manager: they must not
+// be closed, and are handed back with Put once the caller is done with them.
+type ClientManager struct {
+	mu      sync.Mutex
+	clients map[clientKey]*pooledClient
+
+	// retired are the clients replaced or removed from the pool while still held
+	retired map[*DorisClient]*pooledClient
+
+	// userLocks serialize Get per cluster and user, so that concurrent callers share one
+	// connection while the lookups of other clusters are not held up by a slow FE
+	userLocks map[userKey]*sync.Mutex
+
+	healthCheckInterval time.Duration
+	idleTimeout         time.Duration
+
+	// connect, ping and master are replaced in tests
+	connect func(host string, port int, user, password, tlsConfig string) (*DorisClient, error)
+	ping    func(ctx context.Context, c *DorisClient) error
+	master  func(ctx context.Context, c *DorisClient) (*FrontendInfo, error)
+	now     func() time.Time
+}
+
+// NewClientManager creates a new ClientManager
+func NewClientManager() *ClientManager {
+	return &ClientManager{
+		clients:             make(map[clientKey]*pooledClient),
+		retired:             make(map[*DorisClient]*pooledClient),
+		userLocks:           make(map[userKey]*sync.Mutex),
+		healthCheckInterval: defaultHealthCheckInterval,
+		idleTimeout:         defaultIdleTimeout,
+		connect:             NewDorisClientWithTLS,
+		ping:                func(ctx context.Context, c *DorisClient) error { return c.Ping(ctx) },
+		master:              func(ctx context.Context, c *DorisClient) (*FrontendInfo, error) { return c.GetMasterFe(ctx) },
+		now:                 time.Now,
+	}
+}
+
+// Get returns a healthy client connected to the master FE of the cluster. The caller
+// must hand it back with Put. Pooled clients of the same cluster and user with another
+// credential version are retired.
+func (m *ClientManager) Get(ctx context.Context, target ClusterTarget) (*DorisClient, error) {
+	userLock := m.userLock(userKey{uid: target.UID, user: target.User})
+	userLock.Lock()
+	defer userLock.Unlock()
+
+	now := m.now()
+	key := clientKey{uid: target.UID, user: target.User, version: target.CredentialVersion}
+
+	m.mu.Lock()
+	m.evictIdle(now)
+	for k, pooled := range m.clients {
+		if k.uid == key.uid && k.user == key.user && k.version != key.version {
+			clientLogger.Info("Credentials changed, retiring pooled client", "cluster", target.UID, "user", target.User)
+			m.retire(k, pooled, now)
+		}
+	}
+	pooled, ok := m.clients[key]
+	if ok && now.Sub(pooled.lastChecked) < m.healthCheckInterval {
+		m.acquire(pooled, now)
+		m.mu.Unlock()
+		return pooled.client, nil
+	}
+	m.mu.Unlock()
+
+	// The network calls below are made without holding the pool lock
+	if ok {
+		healthy := m.healthy(ctx, pooled.client)
+		m.mu.Lock()
+		if healthy {
+			pooled.lastChecked = now
+			m.acquire(pooled, now)
+			m.mu.Unlock()
+			return pooled.client, nil
+		}
+		clientLogger.Info("Pooled client unhealthy or not on master anymore, reconnecting",
+			"cluster", target.UID, "host", pooled.client.Host())
+		m.retire(key, pooled, now)
+		m.mu.Unlock()
+	}
+
+	client, err := m.dial(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if previous, ok := m.clients[key]; ok {
+		m.retire(key, previous, now)
+	}
+	m.clients[key] = &pooledClient{client: client, lastChecked: now, lastUsed: now, refs: 1}
+	return client, nil
+}
+
+// Put hands back a client returned by Get. A client that left the pool in the meantime
+// is closed once the last caller holding it put it back.
+func (m *ClientManager) Put(client *DorisClient) {
+	if client == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if pooled, ok := m.retired[client]; ok {
+		pooled.refs--
+		if pooled.refs <= 0 {
+			_ = client.Close()
+			delete(m.retired, client)
+		}
+		return
+	}
+	for _, pooled := range m.clients {
+		if pooled.client == client {
+			pooled.refs--
+			pooled.lastUsed = m.now()
+			return
+		}
+	}
+}
+
+// Release retires the pooled clients of a cluster and user, e.g. once a one-off bootstrap is done.
+func (m *ClientManager) Release(uid types.UID, user string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	for k, pooled := range m.clients {
+		if k.uid == uid && k.user == user {
+			m.retire(k, pooled, now)
+		}
+	}
+}
+
+// Remove retires all pooled clients of a cluster, e.g. when it is deleted.
+func (m *ClientManager) Remove(uid types.UID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	for k, pooled := range m.clients {
+		if k.uid == uid {
+			m.retire(k, pooled, now)
+		}
+	}
+	for k := range m.userLocks {
+		if k.uid == uid {
+			delete(m.userLocks, k)
+		}
+	}
+}
+
+// userLock returns the lock serializing Get for a cluster and user
+func (m *ClientManager) userLock(key userKey) *sync.Mutex {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lock, ok := m.userLocks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.userLocks[key] = lock
+	}
+	return lock
+}
+
+// acquire records a caller holding the client. Callers hold the lock.
+func (m *ClientManager) acquire(pooled *pooledClient, now time.Time) {
+	pooled.refs++
+	pooled.lastUsed = now
+}
+
+// retire removes a client from the pool. It is closed right away when no caller holds it,
+// otherwise by the last Put. Callers hold the lock.
+func (m *ClientManager) retire(key clientKey, pooled *pooledClient, now time.Time) {
+	if m.clients[key] == pooled {
+		delete(m.clients, key)
+	}
+	if pooled.refs <= 0 {
+		_ = pooled.client.Close()
+		return
+	}
+	pooled.retiredAt = now
+	m.retired[pooled.client] = pooled
+}
+
+// evictIdle closes the clients not used within the idle timeout, and the retired clients
+// that were not put back within the idle timeout. Callers hold the lock.
+func (m *ClientManager) evictIdle(now time.Time) {
+	for k, pooled := range m.clients {
+		if pooled.refs <= 0 && now.Sub(pooled.lastUsed) > m.idleTimeout {
+			clientLogger.V(1).Info("Closing idle pooled client", "cluster", k.uid, "host", pooled.client.Host())
+			_ = pooled.client.Close()
+			delete(m.clients, k)
+		}
+	}
+	for client, pooled := range m.retired {
+		if now.Sub(pooled.retiredAt) > m.idleTimeout {
+			clientLogger.Info("Closing retired client that was not put back", "host", client.Host())
+			_ = client.Close()
+			delete(m.retired, client)
+		}
+	}
+}
+
+// Verify checks that the credentials of the target can log in to an FE, with a dedicated
+// connection that is not pooled.
+func (m *ClientManager) Verify(target ClusterTarget) error {
+	client, err := m.connectAny(target)
+	if err != nil {
+		return err
+	}
+	return client.Close()
+}
+
+// healthy pings the client and verifies that it is still connected to the master FE.
+func (m *ClientManager) healthy(ctx context.Context, client *DorisClient) bool {
+	if err := m.ping(ctx, client); err != nil {
+		return false
+	}
+	master, err := m.master(ctx, client)
+	if err != nil {
+		// No alive master, e.g. during an election: keep the connection
+		return true
+	}
+	return master.Host == client.Host()
+}
+
+// dial connects to the FE service, or to each FE pod in turn when the service is
+// unreachable, then routes the client to the current master FE.
+func (m *ClientManager) dial(ctx context.Context, target ClusterTarget) (*DorisClient, error) {
+	client, err := m.connectAny(target)
+	if err != nil {
+		return nil, err
+	}
+
+	master, err := m.master(ctx, client)
+	if err != nil {
+		clientLogger.Info("Master FE unknown, using the reachable FE", "host", client.Host(), "error", err)
+		return client, nil
+	}
+	if master.Host == client.Host() {
+		return client, nil
+	}
+
+	port := master.QueryPort
+	if port == 0 {
+		port = target.Port
+	}
+	masterClient, err := m.connect(master.Host, port, target.User, target.Password, target.TLSConfig)
+	if err != nil {
+		clientLogger.Info("Master FE not reachable, using the reachable FE",
+			"master", master.Host, "host", client.Host(), "error", err)
+		return client, nil
+	}
+	_ = client.Close()
+	return masterClient, nil
+}
+
+// connectAny connects to the FE service, or to each FE pod in turn when the service is unreachable.
+func (m *ClientManager) connectAny(target ClusterTarget) (*DorisClient, error) {
+	var errs []error
+	for _, host := range append([]string{target.ServiceHost}, target.PodHosts...) {
+		if host == "" {
+			continue
+		}
+		client, err := m.connect(host, target.Port, target.User, target.Password, target.TLSConfig)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return client, nil
+	}
+	return nil, fmt.Errorf("no FE reachable: %w", errors.Join(errs...))
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doris_client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeFEs simulates the FEs of a cluster for the ClientManager
+type fakeFEs struct {
+	reachable map[string]bool
+	master    string
+	dials     []string
+}
+
+func newTestClientManager(fes *fakeFEs, now *time.Time) *ClientManager {
+	m := NewClientManager()
+	m.connect = func(host string, port int, user, password, tlsConfig string) (*DorisClient, error) {
+		fes.dials = append(fes.dials, host)
+		if !fes.reachable[host] {
+			return nil, errors.New("connection refused")
+		}
+		return &DorisClient{host: host, port: port}, nil
+	}
+	m.ping = func(_ context.Context, c *DorisClient) error {
+		if !fes.reachable[c.Host()] {
+			return errors.New("connection reset")
+		}
+		return nil
+	}
+	m.master = func(_ context.Context, _ *DorisClient) (*FrontendInfo, error) {
+		return &FrontendInfo{Host: fes.master, QueryPort: 9030, IsMaster: true, Alive: true}, nil
+	}
+	m.now = func() time.Time { return *now }
+	return m
+}
+
+func TestClientManager_Get(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	fes := &fakeFEs{
+		reachable: map[string]bool{"fe-svc": true, "fe-0": true, "fe-1": true},
+		master:    "fe-1",
+	}
+	m := newTestClientManager(fes, &now)
+	target := ClusterTarget{
+		UID:               "uid",
+		ServiceHost:       "fe-svc",
+		PodHosts:          []string{"fe-0", "fe-1"},
+		Port:              9030,
+		User:              "admin",
+		CredentialVersion: "1",
+	}
+
+	// Routed to the master FE
+	client, err := m.Get(ctx, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.Host() != "fe-1" {
+		t.Errorf("expected client routed to master fe-1, got %s", client.Host())
+	}
+	m.Put(client)
+
+	// Cached within the health check interval
+	dials := len(fes.dials)
+	if again, _ := m.Get(ctx, target); again != client || len(fes.dials) != dials {
+		t.Error("expected the pooled client to be reused")
+	}
+	m.Put(client)
+
+	// Master switch is detected by the health check
+	fes.master = "fe-0"
+	now = now.Add(defaultHealthCheckInterval)
+	client, err = m.Get(ctx, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.Host() != "fe-0" {
+		t.Errorf("expected client re-routed to new master fe-0, got %s", client.Host())
+	}
+	m.Put(client)
+
+	// Credential change replaces the pooled client
+	target.CredentialVersion = "2"
+	client, err = m.Get(ctx, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.clients) != 1 {
+		t.Errorf("expected a single pooled client after credential change, got %d", len(m.clients))
+	}
+	m.Put(client)
+
+	// Idle clients are evicted
+	now = now.Add(defaultIdleTimeout + time.Second)
+	m.Release("other", "admin")
+	m.mu.Lock()
+	m.evictIdle(now)
+	m.mu.Unlock()
+	if len(m.clients) != 0 {
+		t.Errorf("expected idle client to be evicted, got %d", len(m.clients))
+	}
+}
+
+func TestClientManager_FallbackToPods(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	fes := &fakeFEs{
+		reachable: map[string]bool{"fe-1": true},
+		master:    "fe-1",
+	}
+	m := newTestClientManager(fes, &now)
+	target := ClusterTarget{UID: "uid", ServiceHost: "fe-svc", PodHosts: []string{"fe-0", "fe-1"}, Port: 9030}
+
+	client, err := m.Get(ctx, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.Host() != "fe-1" {
+		t.Errorf("expected fallback to fe-1, got %s", client.Host())
+	}
+
+	fes.reachable["fe-1"] = false
+	m.Remove("uid")
+	if _, err := m.Get(ctx, target); err == nil {
+		t.Error("expected an error when no FE is reachable")
+	}
+}
+
+func TestClientManager_Verify(t *testing.T) {
+	now := time.Now()
+	fes := &fakeFEs{reachable: map[string]bool{"fe-0": true}, master: "fe-0"}
+	m := newTestClientManager(fes, &now)
+	target := ClusterTarget{UID: "uid", ServiceHost: "fe-svc", PodHosts: []string{"fe-0"}, User: "admin"}
+
+	if err := m.Verify(target); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if len(m.clients) != 0 {
+		t.Errorf("Verify() must not pool the connection, got %d pooled clients", len(m.clients))
+	}
+
+	fes.reachable["fe-0"] = false
+	if err := m.Verify(target); err == nil {
+		t.Errorf("Verify() should fail when no FE accepts the login")
+	}
+}
+
+func TestClientManager_RetiredClientClosedOnPut(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	fes := &fakeFEs{reachable: map[string]bool{"fe-svc": true}, master: "fe-svc"}
+	m := newTestClientManager(fes, &now)
+	target := ClusterTarget{UID: "uid", ServiceHost: "fe-svc", Port: 9030, User: "admin", CredentialVersion: "1"}
+
+	held, err := m.Get(ctx, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A credential change while the client is held retires it instead of closing it
+	target.CredentialVersion = "2"
+	client, err := m.Get(ctx, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.retired[held]; !ok {
+		t.Fatal("expected the held client to be retired, not closed")
+	}
+
+	m.Put(held)
+	if len(m.retired) != 0 {
+		t.Errorf("expected the retired client to be closed once put back, got %d retired", len(m.retired))
+	}
+
+	// Held clients are not evicted as idle
+	now = now.Add(defaultIdleTimeout + time.Second)
+	m.mu.Lock()
+	m.evictIdle(now)
+	m.mu.Unlock()
+	if len(m.clients) != 1 {
+		t.Errorf("expected the held client to stay pooled, got %d", len(m.clients))
+	}
+	m.Put(client)
+}
+
+func TestClientManager_SlowClusterDoesNotBlockOthers(t *testing.T) {
+	ctx := context.Background()
+	m := NewClientManager()
+	unblock := make(chan struct{})
+	dialing := make(chan struct{})
+	m.connect = func(host string, port int, user, password, tlsConfig string) (*DorisClient, error) {
+		if host == "slow-svc" {
+			close(dialing)
+			<-unblock
+			return nil, errors.New("i/o timeout")
+		}
+		return &DorisClient{host: host, port: port}, nil
+	}
+	m.master = func(_ context.Context, c *DorisClient) (*FrontendInfo, error) {
+		return &FrontendInfo{Host: c.Host(), IsMaster: true, Alive: true}, nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := m.Get(ctx, ClusterTarget{UID: "slow", ServiceHost: "slow-svc", Port: 9030})
+		done <- err
+	}()
+	<-dialing
+
+	client, err := m.Get(ctx, ClusterTarget{UID: "fast", ServiceHost: "fast-svc", Port: 9030})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Put(client)
+
+	close(unblock)
+	if err := <-done; err == nil {
+		t.Error("expected the slow cluster to fail")
+	}
+}
@@ -0,0 +1,98 @@
+package doris_client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sync"
+
+	mysql "github.com/go-sql-driver/mysql"
+)
+
+// RegisterTLSConfig registers a named TLS configuration with the MySQL driver, trusting
+// only the given PEM encoded CA. With verifyFull the FE certificate must also match the
+// host name the client connects to; otherwise only the certificate chain is verified.
+// A client certificate is presented when FE requires client certificates, nil otherwise.
+// Registering the same name again replaces the configuration.
+func RegisterTLSConfig(name string, caPEM []byte, verifyFull bool, clientCert *tls.Certificate) error {
+	tlsConfig, err := newTLSConfig(caPEM, verifyFull)
+	if err != nil {
+		return err
+	}
+	if clientCert != nil {
+		tlsConfig.Certificates = []tls.Certificate{*clientCert}
+	}
+	return mysql.RegisterTLSConfig(name, tlsConfig)
+}
+
+var (
+	tlsConfigsMu sync.Mutex
+
+	// tlsConfigVersions are the versions of the configurations registered with EnsureTLSConfig
+	tlsConfigVersions = map[string]string{}
+)
+
+// EnsureTLSConfig registers a named TLS configuration like RegisterTLSConfig, unless it is
+// already registered with the same version, e.g. the versions of the CA and client
+// certificate Secrets.
+func EnsureTLSConfig(name, version string, caPEM []byte, verifyFull bool, clientCert *tls.Certificate) error {
+	tlsConfigsMu.Lock()
+	defer tlsConfigsMu.Unlock()
+	if registered, ok := tlsConfigVersions[name]; ok && registered == version {
+		return nil
+	}
+	if err := RegisterTLSConfig(name, caPEM, verifyFull, clientCert); err != nil {
+		return err
+	}
+	tlsConfigVersions[name] = version
+	return nil
+}
+
+// DeregisterTLSConfig removes a TLS configuration registered with EnsureTLSConfig
+func DeregisterTLSConfig(name string) {
+	tlsConfigsMu.Lock()
+	defer tlsConfigsMu.Unlock()
+	if _, ok := tlsConfigVersions[name]; !ok {
+		return
+	}
+	mysql.DeregisterTLSConfig(name)
+	delete(tlsConfigVersions, name)
+}
+
+// newTLSConfig builds the TLS configuration used to connect to FE
+func newTLSConfig(caPEM []byte, verifyFull bool) (*tls.Config, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("no valid CA certificate found")
+	}
+
+	tlsConfig := &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+	if verifyFull {
+		return tlsConfig, nil
+	}
+
+	// Host name verification is disabled, so verify the chain against the CA ourselves
+	tlsConfig.InsecureSkipVerify = true
+	tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("FE presented no certificate")
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range cs.PeerCertificates[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+			Roots:         pool,
+			Intermediates: intermediates,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to verify FE certificate: %w", err)
+		}
+		return nil
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doris_client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// newTestCA creates a self-signed CA and a server certificate issued by it
+func newTestCA(t *testing.T) ([]byte, *x509.Certificate) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, _ := x509.ParseCertificate(caDER)
+
+	serverKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "fe-0"},
+		DNSNames:     []string{"fe-0"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	serverDER, err := x509.CreateCertificate(rand.Reader, serverTemplate, caCert, &serverKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverCert, _ := x509.ParseCertificate(serverDER)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), serverCert
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	caPEM, serverCert := newTestCA(t)
+	otherCAPEM, _ := newTestCA(t)
+
+	if _, err := newTLSConfig([]byte("not a certificate"), true); err == nil {
+		t.Error("expected an error for an invalid CA")
+	}
+
+	full, err := newTLSConfig(caPEM, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if full.InsecureSkipVerify {
+		t.Error("expected VerifyFull to keep host name verification")
+	}
+
+	// VerifyCA accepts a certificate issued by the CA, whatever the host name
+	verifyCA, err := newTLSConfig(caPEM, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := tls.ConnectionState{PeerCertificates: []*x509.Certificate{serverCert}}
+	if err := verifyCA.VerifyConnection(state); err != nil {
+		t.Errorf("expected certificate issued by the CA to be accepted: %v", err)
+	}
+
+	// but rejects certificates issued by another CA
+	other, err := newTLSConfig(otherCAPEM, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.VerifyConnection(state); err == nil {
+		t.Error("expected certificate issued by another CA to be rejected")
+	}
+}
+
+func TestEnsureTLSConfig(t *testing.T) {
+	caPEM, _ := newTestCA(t)
+
+	if err := EnsureTLSConfig("test-ensure", "1", caPEM, true, nil); err != nil {
+		t.Fatalf("EnsureTLSConfig() error = %v", err)
+	}
+	// Same version is not registered again
+	if err := EnsureTLSConfig("test-ensure", "1", []byte("not a certificate"), true, nil); err != nil {
+		t.Errorf("expected unchanged version to skip the registration, got %v", err)
+	}
+	if err := EnsureTLSConfig("test-ensure", "2", []byte("not a certificate"), true, nil); err == nil {
+		t.Error("expected a new version to be registered again")
+	}
+
+	DeregisterTLSConfig("test-ensure")
+	if _, ok := tlsConfigVersions["test-ensure"]; ok {
+		t.Error("expected the configuration to be deregistered")
+	}
+}
@@ -26,16 +26,25 @@ import (
 
 	"github.com/zncdatadev/doris-operator/internal/controller/constants"
 	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	"github.com/zncdatadev/doris-operator/internal/controller/fe"
+	"github.com/zncdatadev/doris-operator/internal/controller/health"
+	"github.com/zncdatadev/doris-operator/internal/controller/poller"
 	"github.com/zncdatadev/doris-operator/internal/controller/scale"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	authv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/authentication/v1alpha1"
 	"github.com/zncdatadev/operator-go/pkg/client"
 	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
 	"github.com/zncdatadev/operator-go/pkg/reconciler"
@@ -46,6 +55,12 @@ type DorisClusterReconciler struct {
 	ctrlclient.Client
 	Scheme *runtime.Scheme
 	Log    logr.Logger
+
+	// DorisClients pools the Doris FE connections of all clusters
+	DorisClients *doris_client.ClientManager
+
+	// Recorder records the Events of the actions the operator takes on its own
+	Recorder events.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=doris.kubedoop.dev,resources=dorisclusters,verbs=get;list;watch;create;update;patch;delete
@@ -58,14 +73,18 @@ type DorisClusterReconciler struct {
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods/status,verbs=get;patch
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=secrets.kubedoop.dev,resources=secretclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=authentication.kubedoop.dev,resources=authenticationclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=storage.k8s.io,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 
 var logger = ctrl.Log.WithName("doriscluster-controller")
@@ -85,6 +104,14 @@ func (r *DorisClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 	logger.V(1).Info("DorisCluster found", "namespace", instance.Namespace, "name", instance.Name)
 
+	// A cluster being deleted is torn down gracefully before the finalizer is released
+	if !instance.DeletionTimestamp.IsZero() {
+		return r.reconcileTeardown(ctx, instance)
+	}
+	if err := r.ensureFinalizer(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Phase 0: Gate BE replicas if there are in-progress decommissions.
 	// By modifying the spec replicas in-memory before Phase 1, operator-go's STS
 	// reconciler will see the gated value and won't scale down prematurely.
@@ -93,6 +120,29 @@ func (r *DorisClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		defer restoreFn()
 	}
 
+	// Phase 0: Grow PVCs when the requested storage increased. StatefulSet volume claim
+	// templates are immutable, so Phase 1 is held back until the StatefulSet is recreated.
+	if result, err := r.reconcileVolumeExpansion(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	} else if !result.IsZero() {
+		return result, nil
+	}
+
+	// Phase 0: Unsupported authentication is rejected before any resource is changed
+	auth, supported, err := r.resolveAuthentication(ctx, instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !supported {
+		logger.Info("Unsupported authentication, cluster not reconciled", "cluster", instance.Name)
+		return ctrl.Result{}, nil
+	}
+
+	// The operator client certificate and its CA must exist before the FE pods mount the CA
+	if err := r.reconcileClientCertificates(ctx, instance, auth); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	resourceClient := &client.Client{
 		Client:         r.Client,
 		OwnerReference: instance,
@@ -123,27 +173,70 @@ func (r *DorisClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return result, nil
 	}
 
+	if err := r.reconcileIngress(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	flightEndpoints, err := r.reconcileArrowFlightEndpoints(ctx, instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.reconcileDiscovery(ctx, instance, flightEndpoints); err != nil {
+		return ctrl.Result{}, err
+	}
+	// Pods are not ready until their readiness gates are set, so Doris is reached before the
+	// cluster is ready. A Doris not reachable yet is retried while waiting for the cluster.
+	if err := r.reconcileReadinessGates(ctx, instance); err != nil {
+		logger.Info("Failed to set readiness gates of Doris pods", "cluster", instance.Name, "error", err.Error())
+	}
+	healthReport := r.evaluateHealth(ctx, instance)
+	restartResult, err := r.reconcileRollingRestart(ctx, instance)
+	if err != nil {
+		logger.Error(err, "Failed to pause tablet scheduling for BE restarts", "cluster", instance.Name)
+		restartResult = ctrl.Result{RequeueAfter: rollingRestartRequeueAfter}
+	}
+	healResult, err := r.reconcileSelfHealing(ctx, instance)
+	if err != nil {
+		logger.Error(err, "Failed to heal dead Doris nodes", "cluster", instance.Name)
+		healResult = ctrl.Result{RequeueAfter: selfHealingRequeueAfter}
+	}
+
 	logger.Info("Cluster resource reconciled, checking if ready.", "cluster", instance.Name, "namespace", instance.Namespace)
 
-	if result, err := clusterReconciler.Ready(ctx); err != nil {
-		return ctrl.Result{}, err
-	} else if !result.IsZero() {
-		return result, nil
+	// BE pods being decommissioned are not ready, so the decommission is driven to completion
+	// without waiting for them
+	if !gateApplied {
+		if result, err := clusterReconciler.Ready(ctx); err != nil {
+			return ctrl.Result{}, err
+		} else if !result.IsZero() {
+			return result, nil
+		}
 	}
 
 	// Phase 2: Scale management (after resources are ready)
-	scaleResult, authInitialized, err := r.reconcileScale(ctx, instance)
-	if err != nil {
-		logger.Error(err, "Scale reconciliation failed", "cluster", instance.Name)
-		return ctrl.Result{}, nil
+	// A failure still updates the status, with the bootstrap steps performed and the nodes
+	// listed from the pods, and reconciles orphan nodes before it is returned
+	scaleResult, bootstrap, scaleErr := r.reconcileScale(ctx, instance, auth, healthReport)
+	if scaleErr != nil {
+		logger.Error(scaleErr, "Scale reconciliation failed", "cluster", instance.Name)
 	}
 
 	// Update CR status with node information (single status patch)
-	if err := r.updateStatus(ctx, instance, scaleResult, authInitialized); err != nil {
+	if err := r.updateStatus(ctx, instance, scaleResult, bootstrap); err != nil {
 		logger.Error(err, "Failed to update cluster status", "cluster", instance.Name)
 		return ctrl.Result{}, err
 	}
 
+	// Orphan nodes are reported on the next reconcile when Doris cannot be queried
+	orphanResult, err := r.reconcileOrphanNodes(ctx, instance)
+	if err != nil {
+		logger.Error(err, "Failed to reconcile Doris nodes without a pod", "cluster", instance.Name)
+		orphanResult = ctrl.Result{RequeueAfter: orphanRequeueAfter}
+	}
+
+	if scaleErr != nil {
+		return ctrl.Result{}, scaleErr
+	}
+
 	if scaleResult != nil && scaleResult.NeedRequeue {
 		logger.Info("Scale operation in progress, requeuing", "cluster", instance.Name, "after", scaleResult.RequeueAfter)
 		return ctrl.Result{RequeueAfter: scaleResult.RequeueAfter}, nil
@@ -151,18 +244,49 @@ func (r *DorisClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	logger.V(1).Info("Reconcile finished.", "cluster", instance.Name, "namespace", instance.Namespace)
 
-	return ctrl.Result{}, nil
+	return soonerResult(soonerResult(restartResult, healResult), orphanResult), nil
+}
+
+// soonerResult returns the result requeuing the soonest
+func soonerResult(a, b ctrl.Result) ctrl.Result {
+	if a.RequeueAfter == 0 || (b.RequeueAfter != 0 && b.RequeueAfter < a.RequeueAfter) {
+		return b
+	}
+	return a
+}
+
+// recordEvent records an Event on the DorisCluster about a related object, when a recorder is set
+func (r *DorisClusterReconciler) recordEvent(
+	instance *dorisv1alpha1.DorisCluster,
+	related runtime.Object,
+	eventtype, reason, action, note string,
+	args ...any,
+) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(instance, related, eventtype, reason, action, note, args...)
 }
 
 // clusterScaleDownPolicy implements scale.ScaleDownPolicy using the CR spec.
 type clusterScaleDownPolicy struct {
 	spec *dorisv1alpha1.DorisClusterSpec
+	// health is the last health report of the cluster, nil when it could not be evaluated
+	health *health.Report
 }
 
 func (p *clusterScaleDownPolicy) GetDecommissionTimeout() time.Duration {
 	return scale.GetDecommissionTimeout(p.spec)
 }
 
+// DecommissionBlocked holds off draining a BE while tablets are missing replicas
+func (p *clusterScaleDownPolicy) DecommissionBlocked() string {
+	if p.health != nil && p.health.DataAtRisk() {
+		return p.health.Problems[0].Message
+	}
+	return ""
+}
+
 // decommissionTracker implements scale.DecommissionTracker by managing annotations
 // on the DorisCluster CR to track BE decommission lifecycle.
 type decommissionTracker struct {
@@ -282,93 +406,179 @@ func (t *decommissionTracker) PendingPods() []string {
 	return pods
 }
 
+// clusterDomain returns the DNS domain of the Kubernetes cluster
+func clusterDomain(instance *dorisv1alpha1.DorisCluster) string {
+	if instance.Spec.ClusterConfig != nil && instance.Spec.ClusterConfig.ClusterDomain != "" {
+		return instance.Spec.ClusterConfig.ClusterDomain
+	}
+	return "cluster.local"
+}
+
+// podFQDN returns the DNS name of a StatefulSet pod through its headless service, or its IP
+// when the pod has no subdomain
+func podFQDN(pod *corev1.Pod, domain string) string {
+	if pod.Spec.Subdomain == "" {
+		return pod.Status.PodIP
+	}
+	hostname := pod.Spec.Hostname
+	if hostname == "" {
+		hostname = pod.Name
+	}
+	return fmt.Sprintf("%s.%s.%s.svc.%s", hostname, pod.Spec.Subdomain, pod.Namespace, domain)
+}
+
+// feQueryHost returns the DNS name of the FE internal service used for MySQL connections.
+func feQueryHost(instance *dorisv1alpha1.DorisCluster) string {
+	return fmt.Sprintf("%s-fe-internal.%s.svc.%s", instance.Name, instance.Namespace, clusterDomain(instance))
+}
+
+// clusterTarget returns how the operator reaches Doris FE, with the credentials it uses
+// to manage Doris: the authSecret credentials, or root with the root password.
+func (r *DorisClusterReconciler) clusterTarget(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (doris_client.ClusterTarget, error) {
+	target := doris_client.ClusterTarget{
+		UID:         instance.UID,
+		ServiceHost: feQueryHost(instance),
+		Port:        constants.FEQueryPort,
+		User:        doris_client.DefaultAdminUser,
+	}
+
+	// FE pods are the fallback when the service has no ready endpoint, e.g. at first start
+	// while the readiness gates keep them unready. They are reached by their FQDN, which the
+	// FE certificate is issued for, so the host name is verified with TLS.
+	podList := &corev1.PodList{}
+	labelSelector := ctrlclient.MatchingLabels{
+		opgpconstants.LabelKubernetesInstance:  instance.Name,
+		opgpconstants.LabelKubernetesComponent: string(constants.ComponentTypeFE),
+	}
+	if err := r.List(ctx, podList, labelSelector, ctrlclient.InNamespace(instance.Namespace)); err != nil {
+		return target, fmt.Errorf("failed to list FE pods: %w", err)
+	}
+	sort.Slice(podList.Items, func(i, j int) bool { return podList.Items[i].Name < podList.Items[j].Name })
+	for _, pod := range podList.Items {
+		if pod.Status.PodIP != "" && pod.DeletionTimestamp.IsZero() {
+			target.PodHosts = append(target.PodHosts, podFQDN(&pod, clusterDomain(instance)))
+		}
+	}
+
+	tlsConfig, caVersion, err := r.registerTLSConfig(ctx, instance)
+	if err != nil {
+		return target, err
+	}
+	target.TLSConfig = tlsConfig
+	target.CredentialVersion = caVersion
+
+	if instance.Spec.AuthSecret == nil {
+		password, version, err := r.getRootPassword(ctx, instance)
+		if err != nil {
+			return target, err
+		}
+		target.Password = password
+		target.CredentialVersion = version + "/" + caVersion
+		return target, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      instance.Spec.AuthSecret.SecretName,
+		Namespace: instance.Namespace,
+	}, secret); err != nil {
+		return target, fmt.Errorf("failed to get authSecret: %w", err)
+	}
+	target.User, target.Password = doris_client.GetClusterAuthCredentials(secret.Data)
+	target.CredentialVersion = secret.ResourceVersion + "/" + caVersion
+	return target, nil
+}
+
 // reconcileScale performs scale reconciliation by connecting to Doris FE
 // and checking if any scale-down operations are needed.
-// It returns the scale result and whether auth bootstrap was performed.
+// It returns the scale result and the auth bootstrap steps performed. BE decommissions are
+// held off while the health report, nil when it could not be evaluated, finds data at risk.
 func (r *DorisClusterReconciler) reconcileScale(
 	ctx context.Context,
 	instance *dorisv1alpha1.DorisCluster,
-) (*scale.ScaleResult, bool, error) {
-	// Build FE service DNS name for MySQL connection
-	clusterDomain := "cluster.local"
-	if instance.Spec.ClusterConfig != nil && instance.Spec.ClusterConfig.ClusterDomain != "" {
-		clusterDomain = instance.Spec.ClusterConfig.ClusterDomain
+	auth *fe.Authentication,
+	healthReport *health.Report,
+) (*scale.ScaleResult, bootstrapResult, error) {
+	var bootstrap bootstrapResult
+	if err := r.ensureRootPasswordSecret(ctx, instance); err != nil {
+		return nil, bootstrap, err
 	}
-	feHost := fmt.Sprintf("%s-fe-internal.%s.svc.%s", instance.Name, instance.Namespace, clusterDomain)
 
 	// Resolve management credentials
-	var mgmtUser, mgmtPass string
-	needBootstrap := instance.Spec.AuthSecret != nil && !instance.Status.AuthInitialized
-
-	if instance.Spec.AuthSecret != nil {
-		secret := &corev1.Secret{}
-		if err := r.Get(ctx, types.NamespacedName{
-			Name:      instance.Spec.AuthSecret.SecretName,
-			Namespace: instance.Namespace,
-		}, secret); err != nil {
-			if ctrlclient.IgnoreNotFound(err) == nil {
-				logger.Info("AuthSecret not found yet, skipping scale reconciliation",
-					"secret", instance.Spec.AuthSecret.SecretName)
-				return nil, false, nil
-			}
-			return nil, false, fmt.Errorf("failed to get authSecret: %w", err)
+	target, err := r.clusterTarget(ctx, instance)
+	if err != nil {
+		if ctrlclient.IgnoreNotFound(err) == nil {
+			logger.Info("Credentials Secret not found yet, skipping scale reconciliation", "error", err)
+			return nil, bootstrap, nil
 		}
-		mgmtUser, mgmtPass = doris_client.GetClusterAuthCredentials(secret.Data)
-	} else {
-		mgmtUser = doris_client.DefaultAdminUser
-		mgmtPass = ""
+		return nil, bootstrap, err
 	}
 
-	// Bootstrap the admin user with root credentials if needed.
+	// Bootstrap the admin user and the root password with root credentials if needed.
+	needBootstrap := (instance.Spec.AuthSecret != nil && !instance.Status.AuthInitialized) ||
+		!instance.Status.RootPasswordInitialized
 	if needBootstrap {
-		rootClient, err := doris_client.NewDorisClient(feHost, constants.FEQueryPort, doris_client.DefaultAdminUser, "")
+		result, done, err := r.bootstrapAuth(ctx, instance, target)
 		if err != nil {
-			logger.Info("Failed to connect to Doris FE for auth bootstrap",
-				"host", feHost, "error", err)
-			return nil, false, nil
+			logger.Error(err, "Failed to bootstrap Doris authentication", "cluster", instance.Name)
+			return nil, result, nil
 		}
-
-		exists, err := rootClient.CheckUserExists(ctx, mgmtUser)
-		if err != nil {
-			_ = rootClient.Close()
-			logger.Error(err, "Failed to check if admin user exists", "user", mgmtUser)
-			return nil, false, nil
+		if !done {
+			return nil, result, nil
 		}
-		if !exists {
-			if err := rootClient.InitializeAdminUser(ctx, mgmtUser, mgmtPass); err != nil {
-				_ = rootClient.Close()
-				logger.Error(err, "Failed to initialize admin user", "user", mgmtUser)
-				return nil, false, nil
-			}
+		bootstrap = result
+	}
+
+	// Apply authSecret changes before logging in with its credentials
+	if instance.Spec.AuthSecret != nil && (instance.Status.AuthInitialized || bootstrap.adminUser) {
+		if err := r.reconcileAdminCredentials(ctx, instance, target); err != nil {
+			return nil, bootstrap, err
 		}
-		_ = rootClient.Close()
 	}
 
-	// Connect with management credentials for scale operations
-	mgmtClient, err := doris_client.NewDorisClient(feHost, constants.FEQueryPort, mgmtUser, mgmtPass)
+	// Connect with management credentials for scale operations. Once the root password
+	// is set, the cluster is expected to be reachable and a failure is an error.
+	mgmtClient, err := r.DorisClients.Get(ctx, target)
 	if err != nil {
+		if instance.Status.RootPasswordInitialized || bootstrap.rootPassword {
+			return nil, bootstrap, fmt.Errorf("failed to connect to Doris FE %s as %s: %w",
+				target.ServiceHost, target.User, err)
+		}
 		logger.Info("Failed to connect to Doris FE with management credentials",
-			"host", feHost, "user", mgmtUser, "error", err)
-		return nil, false, nil
+			"host", target.ServiceHost, "user", target.User, "error", err)
+		return nil, bootstrap, nil
+	}
+	defer r.DorisClients.Put(mgmtClient)
+
+	// Users and LDAP settings stored in Doris are retried on the next reconcile
+	if err := r.reconcileStaticUsers(ctx, instance, target, mgmtClient, auth.Static); err != nil {
+		logger.Error(err, "Failed to apply static users to Doris", "cluster", instance.Name)
+	}
+	if err := r.reconcileLDAP(ctx, instance, target, auth); err != nil {
+		logger.Error(err, "Failed to apply LDAP settings to Doris", "cluster", instance.Name)
+	}
+	if err := r.reconcileBEListeners(ctx, instance, mgmtClient); err != nil {
+		logger.Error(err, "Failed to advertise BE public endpoints to Doris", "cluster", instance.Name)
 	}
-	defer func() { _ = mgmtClient.Close() }()
 
 	scaleMgr := scale.NewScaleManager(mgmtClient)
-	defer scaleMgr.Close()
 
 	// Fetch current StatefulSets
 	replicaStates, err := r.fetchReplicaStates(ctx, instance)
 	if err != nil {
-		return nil, false, fmt.Errorf("failed to fetch replica states: %w", err)
+		return nil, bootstrap, fmt.Errorf("failed to fetch replica states: %w", err)
 	}
 
 	// Create policy and tracker for decommission lifecycle management
-	policy := &clusterScaleDownPolicy{spec: &instance.Spec}
+	policy := &clusterScaleDownPolicy{spec: &instance.Spec, health: healthReport}
 	tracker := newDecommissionTracker(instance, r.Client)
 
 	result, err := scaleMgr.ReconcileScale(ctx, &instance.Spec, replicaStates, policy, tracker)
 	if err != nil {
-		return nil, false, err
+		return nil, bootstrap, err
 	}
 
 	// Persist decommission annotation changes (records + clears)
@@ -377,7 +587,13 @@ func (r *DorisClusterReconciler) reconcileScale(
 		// Non-fatal: decommission timeout tracking will be approximate until next persistence
 	}
 
-	return result, needBootstrap, nil
+	// Remove PVCs of nodes that Doris no longer lists, according to whenScaled
+	if err := r.cleanupScaledClaims(ctx, instance, mgmtClient); err != nil {
+		logger.Error(err, "Failed to clean up PVCs of scaled-down pods", "cluster", instance.Name)
+		// Non-fatal: PVCs are kept and cleanup is retried on the next reconciliation
+	}
+
+	return result, bootstrap, nil
 }
 
 // gateBESpecReplicas checks for in-progress BE decommissions (via CR annotations)
@@ -555,7 +771,7 @@ func (r *DorisClusterReconciler) updateStatus(
 	ctx context.Context,
 	instance *dorisv1alpha1.DorisCluster,
 	result *scale.ScaleResult,
-	authBootstrap bool,
+	bootstrap bootstrapResult,
 ) error {
 	latest := &dorisv1alpha1.DorisCluster{}
 	if err := r.Get(ctx, types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, latest); err != nil {
@@ -565,9 +781,12 @@ func (r *DorisClusterReconciler) updateStatus(
 	patch := ctrlclient.MergeFrom(latest.DeepCopy())
 
 	// Mark auth initialization as complete
-	if authBootstrap {
+	if bootstrap.adminUser {
 		latest.Status.AuthInitialized = true
 	}
+	if bootstrap.rootPassword {
+		latest.Status.RootPasswordInitialized = true
+	}
 
 	// buildPodNodeList creates a sorted list of NodeStatus from pod listings.
 	buildPodNodeList := func(ct constants.ComponentType) ([]dorisv1alpha1.NodeStatus, error) {
@@ -633,9 +852,64 @@ func (r *DorisClusterReconciler) updateStatus(
 	return r.Status().Patch(ctx, latest, patch)
 }
 
+// dorisStateSnapshot returns the snapshot of the Doris node state polled by the state poller.
+func (r *DorisClusterReconciler) dorisStateSnapshot(ctx context.Context, instance *dorisv1alpha1.DorisCluster) (string, error) {
+	target, err := r.clusterTarget(ctx, instance)
+	if err != nil {
+		return "", err
+	}
+	dorisClient, err := r.DorisClients.Get(ctx, target)
+	if err != nil {
+		return "", err
+	}
+	defer r.DorisClients.Put(dorisClient)
+
+	frontends, err := dorisClient.ShowFrontends(ctx)
+	if err != nil {
+		return "", err
+	}
+	backends, err := dorisClient.ShowBackends(ctx)
+	if err != nil {
+		return "", err
+	}
+	brokers, err := dorisClient.ShowBrokers(ctx)
+	if err != nil {
+		return "", err
+	}
+	return poller.Snapshot(frontends, backends, brokers), nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *DorisClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.DorisClients == nil {
+		r.DorisClients = doris_client.NewClientManager()
+	}
+
+	if err := setupIndexes(context.Background(), mgr); err != nil {
+		return err
+	}
+
+	statePoller := poller.NewPoller(mgr.GetClient(), r.dorisStateSnapshot)
+	if err := mgr.Add(statePoller); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&dorisv1alpha1.DorisCluster{}).
+		For(&dorisv1alpha1.DorisCluster{}, builder.WithPredicates(clusterSpecPredicate)).
+		Owns(&appsv1.StatefulSet{}).
+		Owns(&corev1.Service{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&networkingv1.Ingress{}).
+		Watches(&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(mapPodToCluster),
+			builder.WithPredicates(podReadinessPredicate)).
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.mapSecretToClusters)).
+		Watches(&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.mapConfigMapToClusters)).
+		Watches(&authv1alpha1.AuthenticationClass{},
+			handler.EnqueueRequestsFromMapFunc(r.mapAuthenticationClassToClusters)).
+		WatchesRawSource(statePoller.Source()).
 		Complete(r)
 }
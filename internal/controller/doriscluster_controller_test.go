@@ -22,7 +22,10 @@ import (
 	"time"
 
 	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	"github.com/zncdatadev/doris-operator/internal/controller/health"
 	"github.com/zncdatadev/doris-operator/internal/controller/scale"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -326,8 +329,44 @@ func TestScaleDownPolicy_Timeout(t *testing.T) {
 	}
 }
 
+func TestScaleDownPolicy_DecommissionBlocked(t *testing.T) {
+	policy := &clusterScaleDownPolicy{spec: &dorisv1alpha1.DorisClusterSpec{}}
+	if reason := policy.DecommissionBlocked(); reason != "" {
+		t.Errorf("expected no block without health report, got %q", reason)
+	}
+
+	backends := []doris_client.BackendInfo{{Host: "be-0", Alive: true, MaxDiskUsedPct: 90}}
+	policy.health = health.Evaluate(nil, backends, doris_client.TabletHealth{TabletNum: 10, HealthyNum: 10})
+	if reason := policy.DecommissionBlocked(); reason != "" {
+		t.Errorf("expected high disk usage not to block decommission, got %q", reason)
+	}
+
+	policy.health = health.Evaluate(nil, backends,
+		doris_client.TabletHealth{TabletNum: 10, HealthyNum: 8, ReplicaMissingNum: 2})
+	if reason := policy.DecommissionBlocked(); reason == "" {
+		t.Error("expected under-replicated tablets to block decommission")
+	}
+}
+
 // Ensure the core types satisfy interfaces at compile time
 var (
 	_ scale.ScaleDownPolicy     = (*clusterScaleDownPolicy)(nil)
 	_ scale.DecommissionTracker = (*decommissionTracker)(nil)
 )
+
+func TestPodFQDN(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-fe-default-0", Namespace: testClusterNamespace},
+		Spec:       corev1.PodSpec{Hostname: "test-fe-default-0", Subdomain: "test-fe-default"},
+		Status:     corev1.PodStatus{PodIP: "10.0.0.1"},
+	}
+	want := "test-fe-default-0.test-fe-default.default.svc.cluster.local"
+	if got := podFQDN(pod, "cluster.local"); got != want {
+		t.Errorf("podFQDN() = %q, want %q", got, want)
+	}
+
+	pod.Spec.Subdomain = ""
+	if got := podFQDN(pod, "cluster.local"); got != "10.0.0.1" {
+		t.Errorf("podFQDN() without subdomain = %q, want the pod IP", got)
+	}
+}
@@ -0,0 +1,93 @@
+package fe
+
+import (
+	"context"
+	"fmt"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	authv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/authentication/v1alpha1"
+	"github.com/zncdatadev/operator-go/pkg/client"
+)
+
+// Authentication is the authentication of a cluster resolved from its AuthenticationClasses
+type Authentication struct {
+	// LDAP is the LDAP provider, Doris supports a single LDAP server
+	LDAP     *authv1alpha1.LDAPProvider
+	LDAPSpec *dorisv1alpha1.LDAPAuthenticationSpec
+
+	// TLS is the provider of the client certificates required on the MySQL port. Clients
+	// still log in with a password: certificates are not mapped to Doris users.
+	TLS *authv1alpha1.TLSProvider
+
+	// Static are the providers of Doris users logging in with a password
+	Static []*authv1alpha1.StaticProvider
+
+	// Errors explains why AuthenticationClasses are not supported
+	Errors []string
+}
+
+// ResolveAuthentication resolves the AuthenticationClasses in order. Further LDAP and TLS
+// classes, unresolvable and unsupported classes are reported in Errors; they are only
+// detected at reconcile time, and the cluster is not reconciled until they are fixed.
+func ResolveAuthentication(
+	ctx context.Context,
+	client *client.Client,
+	authSpec []dorisv1alpha1.AuthenticationSpec,
+) *Authentication {
+	auth := &Authentication{}
+	for _, spec := range authSpec {
+		name := spec.AuthenticationClass
+		authClass, err := resolveAuthenticationClass(ctx, client, name)
+		if err != nil {
+			auth.Errors = append(auth.Errors, fmt.Sprintf("AuthenticationClass %s: %v", name, err))
+			continue
+		}
+		provider := authClass.Spec.AuthenticationProvider
+		if provider == nil {
+			auth.Errors = append(auth.Errors, fmt.Sprintf("AuthenticationClass %s has no provider", name))
+			continue
+		}
+		if spec.LDAP != nil && provider.LDAP == nil {
+			auth.Errors = append(auth.Errors, fmt.Sprintf("ldap settings of AuthenticationClass %s need an ldap provider", name))
+		}
+
+		switch {
+		case provider.LDAP != nil:
+			if auth.LDAP != nil {
+				auth.Errors = append(auth.Errors, fmt.Sprintf("AuthenticationClass %s: Doris supports a single LDAP server", name))
+				continue
+			}
+			auth.LDAP, auth.LDAPSpec = provider.LDAP, spec.LDAP
+			if auth.LDAPSpec == nil {
+				auth.LDAPSpec = &dorisv1alpha1.LDAPAuthenticationSpec{}
+			}
+		case provider.TLS != nil:
+			if auth.TLS != nil {
+				auth.Errors = append(auth.Errors, fmt.Sprintf("AuthenticationClass %s: a single TLS client certificate class is supported", name))
+				continue
+			}
+			if provider.TLS.ClientCertSecretClass == "" {
+				auth.Errors = append(auth.Errors, fmt.Sprintf("AuthenticationClass %s: clientCertSecretClass is required", name))
+				continue
+			}
+			auth.TLS = provider.TLS
+		case provider.Static != nil:
+			auth.Static = append(auth.Static, provider.Static)
+		default:
+			auth.Errors = append(auth.Errors, fmt.Sprintf("AuthenticationClass %s: only static, ldap and tls providers are supported", name))
+		}
+	}
+	return auth
+}
+
+// ClientAuthEnabled reports whether FE requires client certificates on the MySQL port.
+// Client certificates are only requested over TLS, so clusterConfig.tls must be set as well.
+func (a *Authentication) ClientAuthEnabled(dorisCluster *dorisv1alpha1.DorisCluster) bool {
+	return a.TLS != nil && dorisCluster.Spec.ClusterConfig != nil && dorisCluster.Spec.ClusterConfig.TLS != nil
+}
+
+// OperatorClientCASecretName returns the Secret holding the CA of the operator client certificate,
+// which FE trusts besides the CA of the clientCertSecretClass
+func OperatorClientCASecretName(clusterName string) string {
+	return clusterName + "-operator-client-ca"
+}
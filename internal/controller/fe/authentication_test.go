@@ -0,0 +1,95 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fe
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	authv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/authentication/v1alpha1"
+)
+
+// newAuthenticationClass returns an AuthenticationClass in the owner namespace, since the
+// fake client does not know that AuthenticationClasses are cluster-scoped.
+func newAuthenticationClass(name string, provider *authv1alpha1.AuthenticationProvider) *authv1alpha1.AuthenticationClass {
+	return &authv1alpha1.AuthenticationClass{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       authv1alpha1.AuthenticationClassSpec{AuthenticationProvider: provider},
+	}
+}
+
+func TestResolveAuthentication(t *testing.T) {
+	cli := newLDAPTestClient(t,
+		newAuthenticationClass("static", &authv1alpha1.AuthenticationProvider{
+			Static: &authv1alpha1.StaticProvider{UserCredentialsSecret: &authv1alpha1.StaticCredentialsSecret{Name: "users"}},
+		}),
+		newAuthenticationClass("ldap", &authv1alpha1.AuthenticationProvider{
+			LDAP: &authv1alpha1.LDAPProvider{Hostname: "openldap"},
+		}),
+		newAuthenticationClass("ldap-2", &authv1alpha1.AuthenticationProvider{
+			LDAP: &authv1alpha1.LDAPProvider{Hostname: "openldap-2"},
+		}),
+		newAuthenticationClass("tls", &authv1alpha1.AuthenticationProvider{
+			TLS: &authv1alpha1.TLSProvider{ClientCertSecretClass: "tls"},
+		}),
+		newAuthenticationClass("oidc", &authv1alpha1.AuthenticationProvider{
+			OIDC: &authv1alpha1.OIDCProvider{Hostname: "keycloak"},
+		}),
+	)
+
+	auth := ResolveAuthentication(context.Background(), cli, []dorisv1alpha1.AuthenticationSpec{
+		{AuthenticationClass: "static"},
+		{AuthenticationClass: "ldap", LDAP: &dorisv1alpha1.LDAPAuthenticationSpec{GroupSearchBase: "ou=groups"}},
+		{AuthenticationClass: "ldap-2"},
+		{AuthenticationClass: "tls"},
+		{AuthenticationClass: "oidc"},
+		{AuthenticationClass: "missing"},
+	})
+
+	if len(auth.Static) != 1 || auth.Static[0].UserCredentialsSecret.Name != "users" {
+		t.Errorf("Static = %v, want the users Secret", auth.Static)
+	}
+	if auth.LDAP == nil || auth.LDAP.Hostname != "openldap" || auth.LDAPSpec.GroupSearchBase != "ou=groups" {
+		t.Errorf("LDAP = %v, %v, want the first LDAP class", auth.LDAP, auth.LDAPSpec)
+	}
+	if auth.TLS == nil || auth.TLS.ClientCertSecretClass != "tls" {
+		t.Errorf("TLS = %v, want the tls class", auth.TLS)
+	}
+
+	errors := strings.Join(auth.Errors, "\n")
+	for _, class := range []string{"ldap-2", "oidc", "missing"} {
+		if !strings.Contains(errors, "AuthenticationClass "+class) {
+			t.Errorf("Errors = %q, want %s reported", errors, class)
+		}
+	}
+	if len(auth.Errors) != 3 {
+		t.Errorf("Errors = %q, want 3 errors", auth.Errors)
+	}
+
+	cluster := &dorisv1alpha1.DorisCluster{Spec: dorisv1alpha1.DorisClusterSpec{ClusterConfig: &dorisv1alpha1.ClusterConfigSpec{}}}
+	if auth.ClientAuthEnabled(cluster) {
+		t.Error("ClientAuthEnabled() = true without clusterConfig.tls")
+	}
+	cluster.Spec.ClusterConfig.TLS = &dorisv1alpha1.TLSSpec{ServerSecretClass: "tls"}
+	if !auth.ClientAuthEnabled(cluster) {
+		t.Error("ClientAuthEnabled() = false with clusterConfig.tls")
+	}
+}
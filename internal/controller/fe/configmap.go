@@ -2,6 +2,7 @@ package fe
 
 import (
 	"context"
+	"strconv"
 	"strings"
 
 	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
@@ -19,6 +20,10 @@ type FEConfigMapBuilder struct {
 	overrides  *commonsv1alpha1.OverridesSpec
 	roleConfig *commonsv1alpha1.RoleGroupConfigSpec
 	authSpec   []dorisv1alpha1.AuthenticationSpec
+	tlsSpec    *dorisv1alpha1.TLSSpec
+
+	// arrowFlightPort is the Arrow Flight SQL port, 0 when disabled
+	arrowFlightPort int32
 }
 
 func NewFEConfigMapReconciler(
@@ -45,6 +50,9 @@ func NewFEConfigMapReconciler(
 		overrides:  overrides,
 		roleConfig: roleConfig,
 		authSpec:   authSpec,
+		tlsSpec:    common.GetTLSSpec(dorisCluster),
+
+		arrowFlightPort: common.GetArrowFlightPort(dorisCluster, constants.ComponentTypeFE),
 	}
 	commonBuilder := common.NewConfigMapBuilder(
 		ctx,
@@ -75,7 +83,7 @@ func (b *FEConfigMapBuilder) BuildConfig(ctx context.Context) (map[string]string
 		"rpc_port=9020",
 		"query_port=9030",
 		"edit_log_port=9010",
-		"arrow_flight_sql_port=-1",
+		"arrow_flight_sql_port=" + common.ArrowFlightPortConfig(b.arrowFlightPort),
 		// System log configuration
 		"sys_log_level=INFO",
 		"sys_log_mode=NORMAL",
@@ -86,10 +94,40 @@ func (b *FEConfigMapBuilder) BuildConfig(ctx context.Context) (map[string]string
 		"enable_fqdn_mode=true",
 	}
 
+	auth := ResolveAuthentication(ctx, b.Client, b.authSpec)
+
+	// HTTPS and MySQL protocol TLS with the certificates issued by the secret-operator
+	if b.tlsSpec != nil {
+		// With client certificate authentication, MySQL clients are verified with the client CA
+		// and the CA of the operator client certificate
+		caPath := constants.TLSTruststorePath
+		if auth.TLS != nil {
+			caPath = constants.ClientTrustTruststorePath
+		}
+		feConfig = append(feConfig,
+			"enable_https=true",
+			"https_port="+strconv.Itoa(constants.FEHttpsPort),
+			"key_store_path="+constants.TLSKeystorePath,
+			"key_store_password="+constants.TLSKeystorePassword,
+			"key_store_type=PKCS12",
+			"enable_ssl=true",
+			"mysql_ssl_default_server_certificate="+constants.TLSKeystorePath,
+			"mysql_ssl_default_server_certificate_password="+constants.TLSKeystorePassword,
+			"mysql_ssl_default_ca_certificate="+caPath,
+			"mysql_ssl_default_ca_certificate_password="+constants.TLSKeystorePassword,
+		)
+		if auth.TLS != nil {
+			feConfig = append(feConfig, "ssl_force_client_auth=true")
+		}
+	}
+
 	// LDAP authentication configuration
-	if IsLDAPAuth(ctx, b.Client, b.authSpec) {
+	if auth.LDAP != nil {
 		feConfig = append(feConfig, "authentication_type=ldap")
-		configs[constants.LDAPConfigFilename] = b.addLdapAuthConfig(ctx) // ldap.conf
+		if GetLDAPCASecretClass(auth.LDAP) != "" {
+			feConfig = appendJavaOpts(feConfig, ldapTrustJavaOpts)
+		}
+		configs[constants.LDAPConfigFilename] = strings.Join(CreateLDAPConfig(ctx, b.Client, auth.LDAP, auth.LDAPSpec), "\n") // ldap.conf
 	}
 
 	configs[string(constants.FEConfigFilename)] = strings.Join(feConfig, "\n")
@@ -97,12 +135,6 @@ func (b *FEConfigMapBuilder) BuildConfig(ctx context.Context) (map[string]string
 	return configs, nil
 }
 
-// LDAP authentication
-func (b *FEConfigMapBuilder) addLdapAuthConfig(ctx context.Context) string {
-	ldapConfigs := LADPAuth(ctx, b.Client, b.authSpec)
-	return strings.Join(ldapConfigs, "\n")
-}
-
 // log4j2ConfigContent returns the content for log4j2-spring.xml configuration
 
 // only fe add log4j2-spring.xml
@@ -26,6 +26,7 @@ import (
 
 	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
 	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	authv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/authentication/v1alpha1"
 	"github.com/zncdatadev/operator-go/pkg/client"
 	"github.com/zncdatadev/operator-go/pkg/reconciler"
 )
@@ -108,3 +109,106 @@ func TestNewFEConfigMapReconciler_OptionalClusterConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestNewFEConfigMapReconciler_TLS(t *testing.T) {
+	dorisCluster := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: dorisv1alpha1.DorisClusterSpec{
+			ClusterConfig: &dorisv1alpha1.ClusterConfigSpec{
+				TLS: &dorisv1alpha1.TLSSpec{ServerSecretClass: "tls"},
+			},
+		},
+	}
+	cli := client.NewClient(nil, dorisCluster)
+
+	rec := NewFEConfigMapReconciler(context.Background(), cli, newTestRoleGroupInfo(), nil, nil, dorisCluster)
+	obj, err := rec.GetBuilder().Build(context.Background())
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	feConf := obj.(*corev1.ConfigMap).Data[string(constants.FEConfigFilename)]
+
+	for _, want := range []string{
+		"enable_https=true",
+		"https_port=8050",
+		"key_store_path=" + constants.TLSKeystorePath,
+		"key_store_type=PKCS12",
+		"enable_ssl=true",
+		"mysql_ssl_default_server_certificate=" + constants.TLSKeystorePath,
+	} {
+		if !strings.Contains(feConf, want) {
+			t.Errorf("fe.conf missing %q, got:\n%s", want, feConf)
+		}
+	}
+}
+
+func TestNewFEConfigMapReconciler_ClientAuth(t *testing.T) {
+	dorisCluster := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: dorisv1alpha1.DorisClusterSpec{
+			ClusterConfig: &dorisv1alpha1.ClusterConfigSpec{
+				TLS:            &dorisv1alpha1.TLSSpec{ServerSecretClass: "tls"},
+				Authentication: []dorisv1alpha1.AuthenticationSpec{{AuthenticationClass: "tls"}},
+			},
+		},
+	}
+	cli := newLDAPTestClient(t, newAuthenticationClass("tls", &authv1alpha1.AuthenticationProvider{
+		TLS: &authv1alpha1.TLSProvider{ClientCertSecretClass: "client-tls"},
+	}))
+
+	rec := NewFEConfigMapReconciler(context.Background(), cli, newTestRoleGroupInfo(), nil, nil, dorisCluster)
+	obj, err := rec.GetBuilder().Build(context.Background())
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	feConf := obj.(*corev1.ConfigMap).Data[string(constants.FEConfigFilename)]
+
+	for _, want := range []string{
+		"mysql_ssl_default_ca_certificate=" + constants.ClientTrustTruststorePath,
+		"ssl_force_client_auth=true",
+	} {
+		if !strings.Contains(feConf, want) {
+			t.Errorf("fe.conf missing %q, got:\n%s", want, feConf)
+		}
+	}
+}
+
+func TestNewFEConfigMapReconciler_ArrowFlight(t *testing.T) {
+	dorisCluster := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: dorisv1alpha1.DorisClusterSpec{
+			ClusterConfig: &dorisv1alpha1.ClusterConfigSpec{
+				ArrowFlight: &dorisv1alpha1.ArrowFlightSpec{FEPort: 8071},
+			},
+		},
+	}
+	cli := client.NewClient(nil, dorisCluster)
+
+	rec := NewFEConfigMapReconciler(context.Background(), cli, newTestRoleGroupInfo(), nil, nil, dorisCluster)
+	obj, err := rec.GetBuilder().Build(context.Background())
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	feConf := obj.(*corev1.ConfigMap).Data[string(constants.FEConfigFilename)]
+	if !strings.Contains(feConf, "arrow_flight_sql_port=8071") || strings.Contains(feConf, "arrow_flight_sql_port=-1") {
+		t.Errorf("fe.conf should enable Arrow Flight on 8071, got:\n%s", feConf)
+	}
+
+	config := GetFEServiceConfig(false, "external-stable", 8071)
+	var names []string
+	for _, port := range config.AccessPorts {
+		names = append(names, port.Name)
+	}
+	if got, want := strings.Join(names, ","), constants.FEHttpPortName+","+constants.FEQueryPortName+","+constants.ArrowFlightPortName; got != want {
+		t.Errorf("external access ports = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,183 @@
+package fe
+
+import (
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/common"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// HTTPRouteGVK is the Gateway API HTTPRoute, created through unstructured objects
+// so that the Gateway API CRDs are only needed when a gateway is configured.
+var HTTPRouteGVK = schema.GroupVersionKind{
+	Group:   "gateway.networking.k8s.io",
+	Version: "v1",
+	Kind:    "HTTPRoute",
+}
+
+// IngressName returns the name of the FE Ingress or HTTPRoute
+func IngressName(clusterName string) string {
+	return clusterName + "-" + string(constants.ComponentTypeFE)
+}
+
+// ingressLabels returns the labels of the FE Ingress or HTTPRoute
+func ingressLabels(dorisCluster *dorisv1alpha1.DorisCluster) map[string]string {
+	return map[string]string{
+		opgpconstants.LabelKubernetesInstance:  dorisCluster.Name,
+		opgpconstants.LabelKubernetesManagedBy: dorisv1alpha1.GroupVersion.Group,
+		constants.ComponentLabelKey:            string(constants.ComponentTypeFE),
+	}
+}
+
+// ingressBackendPort returns the FE access service port exposed by the Ingress.
+// With TLS enabled FE only serves HTTPS.
+func ingressBackendPort(dorisCluster *dorisv1alpha1.DorisCluster) int32 {
+	if common.GetTLSSpec(dorisCluster) != nil {
+		return constants.FEHttpsPort
+	}
+	return constants.FEHttpPort
+}
+
+// ingressBackendProtocolAnnotation tells ingress-nginx the protocol of the backend
+const ingressBackendProtocolAnnotation = "nginx.ingress.kubernetes.io/backend-protocol"
+
+// ingressAnnotations returns the annotations of the Ingress or HTTPRoute. They replace the
+// existing annotations, so annotations removed from the spec are removed from the object.
+// With TLS enabled ingress-nginx is told to connect to the HTTPS port over HTTPS, unless
+// the spec sets the annotation.
+func ingressAnnotations(dorisCluster *dorisv1alpha1.DorisCluster, withBackendProtocol bool) map[string]string {
+	annotations := map[string]string{}
+	if withBackendProtocol && common.GetTLSSpec(dorisCluster) != nil {
+		annotations[ingressBackendProtocolAnnotation] = "HTTPS"
+	}
+	for k, v := range dorisCluster.Spec.ClusterConfig.Ingress.Annotations {
+		annotations[k] = v
+	}
+	return annotations
+}
+
+// ingressPaths returns the path prefixes routed to FE
+func ingressPaths(spec *dorisv1alpha1.IngressSpec) []string {
+	if len(spec.Paths) == 0 {
+		return []string{"/"}
+	}
+	return spec.Paths
+}
+
+// ingressHost returns the host of the Ingress or HTTPRoute
+func ingressHost(dorisCluster *dorisv1alpha1.DorisCluster) string {
+	if dorisCluster.Spec.ClusterConfig.IngressHost == "" {
+		return "example.com"
+	}
+	return dorisCluster.Spec.ClusterConfig.IngressHost
+}
+
+// GetIngressSpec returns the ingress configuration, or nil if FE is not exposed
+func GetIngressSpec(dorisCluster *dorisv1alpha1.DorisCluster) *dorisv1alpha1.IngressSpec {
+	if dorisCluster.Spec.Frontend == nil || dorisCluster.Spec.ClusterConfig == nil {
+		return nil
+	}
+	return dorisCluster.Spec.ClusterConfig.Ingress
+}
+
+// MutateIngress sets the desired state of the FE Ingress, keeping the labels set by others
+func MutateIngress(ingress *networkingv1.Ingress, dorisCluster *dorisv1alpha1.DorisCluster) {
+	spec := dorisCluster.Spec.ClusterConfig.Ingress
+	if ingress.Labels == nil {
+		ingress.Labels = map[string]string{}
+	}
+	for k, v := range ingressLabels(dorisCluster) {
+		ingress.Labels[k] = v
+	}
+	ingress.Annotations = ingressAnnotations(dorisCluster, true)
+
+	host := ingressHost(dorisCluster)
+	pathType := networkingv1.PathTypePrefix
+	backend := networkingv1.IngressBackend{
+		Service: &networkingv1.IngressServiceBackend{
+			Name: common.GetServiceName(dorisCluster.Name, constants.ComponentTypeFE, common.ServiceTypeAccess),
+			Port: networkingv1.ServiceBackendPort{Number: ingressBackendPort(dorisCluster)},
+		},
+	}
+	var paths []networkingv1.HTTPIngressPath
+	for _, path := range ingressPaths(spec) {
+		paths = append(paths, networkingv1.HTTPIngressPath{Path: path, PathType: &pathType, Backend: backend})
+	}
+
+	ingress.Spec.IngressClassName = spec.IngressClassName
+	ingress.Spec.Rules = []networkingv1.IngressRule{{
+		Host: host,
+		IngressRuleValue: networkingv1.IngressRuleValue{
+			HTTP: &networkingv1.HTTPIngressRuleValue{Paths: paths},
+		},
+	}}
+	ingress.Spec.TLS = nil
+	if spec.TLS != nil {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{host}, SecretName: spec.TLS.SecretName}}
+	}
+}
+
+// MutateHTTPRoute sets the desired state of the FE HTTPRoute, keeping the labels set by others.
+// With TLS enabled the Gateway needs a BackendTLSPolicy to connect to the HTTPS port, which
+// is left to the user since it depends on the Gateway implementation.
+func MutateHTTPRoute(route *unstructured.Unstructured, dorisCluster *dorisv1alpha1.DorisCluster) error {
+	spec := dorisCluster.Spec.ClusterConfig.Ingress
+	labels := route.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range ingressLabels(dorisCluster) {
+		labels[k] = v
+	}
+	route.SetLabels(labels)
+	route.SetAnnotations(ingressAnnotations(dorisCluster, false))
+
+	var parentRefs []any
+	for _, ref := range spec.Gateway.ParentRefs {
+		parentRef := map[string]any{"name": ref.Name}
+		if ref.Namespace != "" {
+			parentRef["namespace"] = ref.Namespace
+		}
+		if ref.SectionName != "" {
+			parentRef["sectionName"] = ref.SectionName
+		}
+		parentRefs = append(parentRefs, parentRef)
+	}
+	var matches []any
+	for _, path := range ingressPaths(spec) {
+		matches = append(matches, map[string]any{
+			"path": map[string]any{"type": "PathPrefix", "value": path},
+		})
+	}
+	backendRef := map[string]any{
+		"name": common.GetServiceName(dorisCluster.Name, constants.ComponentTypeFE, common.ServiceTypeAccess),
+		"port": int64(ingressBackendPort(dorisCluster)),
+	}
+
+	return unstructured.SetNestedField(route.Object, map[string]any{
+		"parentRefs": parentRefs,
+		"hostnames":  []any{ingressHost(dorisCluster)},
+		"rules": []any{map[string]any{
+			"matches":     matches,
+			"backendRefs": []any{backendRef},
+		}},
+	}, "spec")
+}
+
+// NewHTTPRoute returns an empty HTTPRoute with the given name
+func NewHTTPRoute(name, namespace string) *unstructured.Unstructured {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(HTTPRouteGVK)
+	route.SetName(name)
+	route.SetNamespace(namespace)
+	return route
+}
+
+// NewIngress returns an empty Ingress with the given name
+func NewIngress(name, namespace string) *networkingv1.Ingress {
+	return &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fe
+
+import (
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+)
+
+func newIngressTestCluster(ingress *dorisv1alpha1.IngressSpec) *dorisv1alpha1.DorisCluster {
+	return &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec: dorisv1alpha1.DorisClusterSpec{
+			Frontend: &dorisv1alpha1.RoleSpec{},
+			ClusterConfig: &dorisv1alpha1.ClusterConfigSpec{
+				IngressHost: "doris.example.org",
+				Ingress:     ingress,
+			},
+		},
+	}
+}
+
+func TestMutateIngress(t *testing.T) {
+	cluster := newIngressTestCluster(&dorisv1alpha1.IngressSpec{
+		IngressClassName: ptr.To("nginx"),
+		Annotations:      map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "0"},
+		Paths:            []string{"/", "/api"},
+		TLS:              &dorisv1alpha1.IngressTLSSpec{SecretName: "doris-ui-tls"},
+	})
+	ingress := NewIngress(IngressName(cluster.Name), cluster.Namespace)
+	ingress.Annotations = map[string]string{"removed-from-spec": "true"}
+	MutateIngress(ingress, cluster)
+
+	if ingress.Name != "test-fe" {
+		t.Errorf("Name = %s, want test-fe", ingress.Name)
+	}
+	// Annotations are replaced, and the backend protocol is only set with TLS
+	if len(ingress.Annotations) != 1 || ingress.Annotations["nginx.ingress.kubernetes.io/proxy-body-size"] != "0" {
+		t.Errorf("Annotations = %v, want only the spec annotations", ingress.Annotations)
+	}
+	if ptr.Deref(ingress.Spec.IngressClassName, "") != "nginx" {
+		t.Errorf("IngressClassName = %v, want nginx", ingress.Spec.IngressClassName)
+	}
+	if len(ingress.Spec.TLS) != 1 || ingress.Spec.TLS[0].SecretName != "doris-ui-tls" ||
+		ingress.Spec.TLS[0].Hosts[0] != "doris.example.org" {
+		t.Errorf("TLS = %v", ingress.Spec.TLS)
+	}
+	if len(ingress.Spec.Rules) != 1 || ingress.Spec.Rules[0].Host != "doris.example.org" {
+		t.Fatalf("Rules = %v", ingress.Spec.Rules)
+	}
+	paths := ingress.Spec.Rules[0].HTTP.Paths
+	if len(paths) != 2 || paths[1].Path != "/api" || *paths[1].PathType != networkingv1.PathTypePrefix {
+		t.Fatalf("Paths = %v", paths)
+	}
+	backend := paths[0].Backend.Service
+	if backend.Name != "test-fe-service" || backend.Port.Number != constants.FEHttpPort {
+		t.Errorf("Backend = %v, want test-fe-service:%d", backend, constants.FEHttpPort)
+	}
+
+	// FE only serves HTTPS with TLS enabled
+	cluster.Spec.ClusterConfig.TLS = &dorisv1alpha1.TLSSpec{ServerSecretClass: "tls"}
+	MutateIngress(ingress, cluster)
+	if port := ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Number; port != constants.FEHttpsPort {
+		t.Errorf("Backend port = %d with TLS, want %d", port, constants.FEHttpsPort)
+	}
+	if protocol := ingress.Annotations[ingressBackendProtocolAnnotation]; protocol != "HTTPS" {
+		t.Errorf("backend protocol = %q with TLS, want HTTPS", protocol)
+	}
+
+	// The spec annotation overrides the backend protocol
+	cluster.Spec.ClusterConfig.Ingress.Annotations[ingressBackendProtocolAnnotation] = "GRPCS"
+	MutateIngress(ingress, cluster)
+	if protocol := ingress.Annotations[ingressBackendProtocolAnnotation]; protocol != "GRPCS" {
+		t.Errorf("backend protocol = %q, want the spec annotation GRPCS", protocol)
+	}
+}
+
+func TestMutateHTTPRoute(t *testing.T) {
+	cluster := newIngressTestCluster(&dorisv1alpha1.IngressSpec{
+		Gateway: &dorisv1alpha1.GatewaySpec{ParentRefs: []dorisv1alpha1.GatewayParentReference{
+			{Name: "public", Namespace: "gateways", SectionName: "https"},
+		}},
+	})
+	route := NewHTTPRoute(IngressName(cluster.Name), cluster.Namespace)
+	if err := MutateHTTPRoute(route, cluster); err != nil {
+		t.Fatalf("MutateHTTPRoute() error = %v", err)
+	}
+
+	hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+	if len(hostnames) != 1 || hostnames[0] != "doris.example.org" {
+		t.Errorf("hostnames = %v", hostnames)
+	}
+	parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	if len(parentRefs) != 1 || parentRefs[0].(map[string]any)["sectionName"] != "https" {
+		t.Errorf("parentRefs = %v", parentRefs)
+	}
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	if len(rules) != 1 {
+		t.Fatalf("rules = %v", rules)
+	}
+	rule := rules[0].(map[string]any)
+	match := rule["matches"].([]any)[0].(map[string]any)["path"].(map[string]any)
+	if match["type"] != "PathPrefix" || match["value"] != "/" {
+		t.Errorf("path match = %v, want the default / prefix", match)
+	}
+	backendRef := rule["backendRefs"].([]any)[0].(map[string]any)
+	if backendRef["name"] != "test-fe-service" || backendRef["port"] != int64(constants.FEHttpPort) {
+		t.Errorf("backendRef = %v", backendRef)
+	}
+}
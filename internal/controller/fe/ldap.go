@@ -2,39 +2,42 @@ package fe
 
 import (
 	"context"
-
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/common"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
 	authv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/authentication/v1alpha1"
 	commonsv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/commons/v1alpha1"
+	"github.com/zncdatadev/operator-go/pkg/builder"
 	"github.com/zncdatadev/operator-go/pkg/client"
-	appsv1 "k8s.io/api/core/v1"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var authenticationLogger = ctrl.Log.WithName("authentication-log")
 
-const LDAP_ADMIN_USER_KEY = "user"
+const (
+	LDAP_ADMIN_USER_KEY     = "user"
+	LDAP_ADMIN_PASSWORD_KEY = "password"
+)
 
 // IsLDAPAuth checks if LDAP authentication is enabled in the provided authentication specifications.
 func IsLDAPAuth(
 	ctx context.Context,
 	client *client.Client,
 	authSpec []dorisv1alpha1.AuthenticationSpec) bool {
-	for _, auth := range authSpec {
-		authClass, err := resolveAuthenticationClass(ctx, client, auth.AuthenticationClass)
-		if err != nil {
-			authenticationLogger.Error(err, "Failed to resolve AuthenticationClass", "authClassRef", auth.AuthenticationClass)
-			return false
-		}
-		if authClass.Spec.AuthenticationProvider.LDAP != nil {
-			authenticationLogger.Info("LDAP authentication is enabled", "authClassRef", auth.AuthenticationClass)
-			return true
-		}
-		authenticationLogger.Info("LDAP authentication is not enabled", "authClassRef", auth.AuthenticationClass)
-	}
-	return false
+	provider, _ := ResolveLDAP(ctx, client, authSpec)
+	return provider != nil
 }
 
 // resolveAuthenticationClass retrieves the AuthenticationClass object based on the provided reference.
@@ -52,71 +55,168 @@ func resolveAuthenticationClass(
 	return
 }
 
-// because can set only one ldap authentication provider in doris cluster, so we only return the first one
-func LADPAuth(
+// ResolveLDAP returns the LDAP authentication provider and its Doris side configuration,
+// or nil when LDAP authentication is not enabled.
+func ResolveLDAP(
 	ctx context.Context,
 	client *client.Client,
-	authSpec []dorisv1alpha1.AuthenticationSpec) []string {
-	// get first LDAP authentication provider
-	if len(authSpec) == 0 {
-		authenticationLogger.Info("No authentication specifications provided")
-		return nil
-	}
-	authClass, err := resolveAuthenticationClass(ctx, client, authSpec[0].AuthenticationClass)
-	if err != nil {
-		authenticationLogger.Error(err, "Failed to resolve AuthenticationClass", "authClassRef", authSpec[0].AuthenticationClass)
-		return nil
-	}
-	if authClass.Spec.AuthenticationProvider.LDAP == nil {
-		authenticationLogger.Info("No LDAP authentication provider found", "authClassRef", authSpec[0].AuthenticationClass)
-		return nil
-	}
-	ldapProvider := authClass.Spec.AuthenticationProvider.LDAP
-	if ldapProvider == nil {
-		authenticationLogger.Info("LDAP provider is nil", "authClassRef", authSpec[0].AuthenticationClass)
-		return nil
-	}
-	authenticationLogger.Info("LDAP authentication is enabled", "authClassRef", authSpec[0].AuthenticationClass)
-	return CreateLDAPConfig(ctx, client, ldapProvider)
+	authSpec []dorisv1alpha1.AuthenticationSpec,
+) (*authv1alpha1.LDAPProvider, *dorisv1alpha1.LDAPAuthenticationSpec) {
+	auth := ResolveAuthentication(ctx, client, authSpec)
+	return auth.LDAP, auth.LDAPSpec
 }
 
+// CreateLDAPConfig returns the content of ldap.conf.
+// The bind password is not part of it: Doris stores it with `SET LDAP_ADMIN_PASSWORD`.
+// Doris has no StartTLS support, so a TLS-enabled AuthenticationClass is served over LDAPS.
 func CreateLDAPConfig(
 	ctx context.Context,
 	client *client.Client,
-	ldapProvider *authv1alpha1.LDAPProvider) []string {
+	ldapProvider *authv1alpha1.LDAPProvider,
+	ldapSpec *dorisv1alpha1.LDAPAuthenticationSpec) []string {
 
 	ldapAdminUser := GetLDAPAdminUser(ctx, client, ldapProvider.BindCredentials)
 
+	groupSearchBase := ldapProvider.SearchBase
+	if ldapSpec != nil && ldapSpec.GroupSearchBase != "" {
+		groupSearchBase = ldapSpec.GroupSearchBase
+	}
+
 	return []string{
 		"ldap_host=" + ldapProvider.Hostname,
-		"ldap_port=" + fmt.Sprint(ldapProvider.Port),
+		"ldap_port=" + strconv.Itoa(GetLDAPPort(ldapProvider)),
+		"ldap_use_ssl=" + strconv.FormatBool(ldapProvider.TLS != nil),
 		"ldap_admin_name=" + ldapAdminUser,
 		"ldap_user_basedn=" + ldapProvider.SearchBase,
 		"ldap_user_filter=" + ldapProvider.SearchFilter,
-		"ldap_group_basedn=" + ldapProvider.SearchBase,
+		"ldap_group_basedn=" + groupSearchBase,
+	}
+}
+
+// GetLDAPPort returns the LDAP server port, defaulting to the LDAPS port when TLS is enabled
+func GetLDAPPort(ldapProvider *authv1alpha1.LDAPProvider) int {
+	if ldapProvider.Port != 0 {
+		return ldapProvider.Port
+	}
+	if ldapProvider.TLS != nil {
+		return constants.LDAPDefaultSecurePort
+	}
+	return constants.LDAPDefaultPort
+}
+
+// GetLDAPCASecretClass returns the SecretClass of the CA verifying the LDAP server,
+// or an empty string when the JVM default truststore is used.
+func GetLDAPCASecretClass(ldapProvider *authv1alpha1.LDAPProvider) string {
+	if ldapProvider == nil || ldapProvider.TLS == nil || ldapProvider.TLS.Verification == nil {
+		return ""
+	}
+	server := ldapProvider.TLS.Verification.Server
+	if server == nil || server.CACert == nil {
+		return ""
+	}
+	return server.CACert.SecretClass
+}
+
+// NewTrustStoreVolume creates a secret-operator volume providing the PKCS12 truststore
+// of the CA of a SecretClass, e.g. the LDAP server CA
+func NewTrustStoreVolume(name, secretClass string) corev1.Volume {
+	volume := builder.NewSecretOperatorVolume(name, secretClass)
+	volume.SetScope(&builder.SecretVolumeScope{Pod: true})
+	volume.SetFormatName(opgpconstants.TLSP12)
+	volume.SetPKCS12Password(constants.TLSKeystorePassword)
+	return *volume.Builde()
+}
+
+// ldapTrustJavaOpts are the JVM options making FE trust the LDAP server CA.
+// The truststore replaces the JVM default one, which Doris uses for LDAPS.
+var ldapTrustJavaOpts = []string{
+	"-Djavax.net.ssl.trustStore=" + constants.LDAPTruststorePath,
+	"-Djavax.net.ssl.trustStorePassword=" + constants.TLSKeystorePassword,
+	"-Djavax.net.ssl.trustStoreType=PKCS12",
+}
+
+// appendJavaOpts appends options to the quoted JAVA_OPTS* lines of fe.conf
+func appendJavaOpts(feConfig []string, opts []string) []string {
+	suffix := " " + strings.Join(opts, " ") + "\""
+	for i, line := range feConfig {
+		if strings.HasPrefix(line, "JAVA_OPTS") && strings.HasSuffix(line, "\"") {
+			feConfig[i] = strings.TrimSuffix(line, "\"") + suffix
+		}
 	}
+	return feConfig
 }
 
-// get user from secret
+// GetLDAPAdminUser returns the bind user, logging failures like the rest of the FE configuration
 func GetLDAPAdminUser(
 	ctx context.Context,
 	client *client.Client,
 	ldapBindCredentials *commonsv1alpha1.Credentials,
 ) string {
-	if ldapBindCredentials == nil || ldapBindCredentials.SecretClass == "" {
-		authenticationLogger.Error(nil, "LDAP bind credentials are not provided")
+	user, _, err := GetLDAPBindCredentials(ctx, client, ldapBindCredentials)
+	if err != nil {
+		authenticationLogger.Error(err, "Failed to get LDAP bind credentials", "namespace", client.GetOwnerNamespace())
 		return ""
 	}
-	secret := &appsv1.Secret{}
-	err := client.GetWithOwnerNamespace(ctx, ldapBindCredentials.SecretClass, secret)
+	return user
+}
+
+// GetLDAPBindCredentials returns the bind user and password of the LDAP bind credentials.
+// The SecretClass is expected to have a k8sSearch backend: the Secret labelled with the
+// SecretClass in its search namespace holds them. A Secret named after the SecretClass
+// is still accepted when no such SecretClass exists.
+func GetLDAPBindCredentials(
+	ctx context.Context,
+	client *client.Client,
+	ldapBindCredentials *commonsv1alpha1.Credentials,
+) (string, string, error) {
+	if ldapBindCredentials == nil || ldapBindCredentials.SecretClass == "" {
+		return "", "", fmt.Errorf("LDAP bind credentials are not provided")
+	}
+	secretClassName := ldapBindCredentials.SecretClass
+
+	secret, err := getSecretClassSecret(ctx, client, secretClassName)
 	if err != nil {
-		authenticationLogger.Error(err, "Failed to get LDAP bind credentials secret", "secretClass", ldapBindCredentials.SecretClass, "namespace", client.GetOwnerNamespace())
-		return ""
+		return "", "", err
 	}
-	ldapAdminUser, ok := secret.Data[LDAP_ADMIN_USER_KEY]
-	if !ok {
-		authenticationLogger.Error(nil, "LDAP admin user not found in secret", "secretClass", ldapBindCredentials.SecretClass, "namespace", client.GetOwnerNamespace(), "key", LDAP_ADMIN_USER_KEY)
-		return ""
+	user := string(secret.Data[LDAP_ADMIN_USER_KEY])
+	if user == "" {
+		return "", "", fmt.Errorf("LDAP bind Secret %s/%s has no %q", secret.Namespace, secret.Name, LDAP_ADMIN_USER_KEY)
+	}
+	return user, string(secret.Data[LDAP_ADMIN_PASSWORD_KEY]), nil
+}
+
+// getSecretClassSecret returns the Secret a k8sSearch SecretClass resolves to
+func getSecretClassSecret(ctx context.Context, client *client.Client, secretClassName string) (*corev1.Secret, error) {
+	secretClass := &unstructured.Unstructured{}
+	secretClass.SetGroupVersionKind(common.SecretClassGVK)
+	err := client.Client.Get(ctx, types.NamespacedName{Name: secretClassName}, secretClass)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		secret := &corev1.Secret{}
+		if err := client.GetWithOwnerNamespace(ctx, secretClassName, secret); err != nil {
+			return nil, fmt.Errorf("failed to get LDAP bind SecretClass or Secret %s: %w", secretClassName, err)
+		}
+		return secret, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SecretClass %s: %w", secretClassName, err)
+	}
+
+	if _, ok, _ := unstructured.NestedMap(secretClass.Object, "spec", "backend", "k8sSearch"); !ok {
+		return nil, fmt.Errorf("SecretClass %s has no k8sSearch backend", secretClassName)
+	}
+	namespace, _, _ := unstructured.NestedString(secretClass.Object, "spec", "backend", "k8sSearch", "searchNamespace", "name")
+	if namespace == "" {
+		namespace = client.GetOwnerNamespace()
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := client.Client.List(ctx, secrets, ctrlclient.InNamespace(namespace),
+		ctrlclient.MatchingLabels{constants.SecretClassLabel: secretClassName}); err != nil {
+		return nil, fmt.Errorf("failed to list Secrets of SecretClass %s: %w", secretClassName, err)
+	}
+	if len(secrets.Items) == 0 {
+		return nil, fmt.Errorf("no Secret labelled %s=%s in namespace %s", constants.SecretClassLabel, secretClassName, namespace)
 	}
-	return string(ldapAdminUser)
+	sort.Slice(secrets.Items, func(i, j int) bool { return secrets.Items[i].Name < secrets.Items[j].Name })
+	return &secrets.Items[0], nil
 }
@@ -0,0 +1,183 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fe
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/common"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	authv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/authentication/v1alpha1"
+	commonsv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/commons/v1alpha1"
+	"github.com/zncdatadev/operator-go/pkg/client"
+)
+
+func newLDAPTestClient(t *testing.T, objs ...ctrlclient.Object) *client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := authv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := dorisv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	owner := &dorisv1alpha1.DorisCluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	return client.NewClient(fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(), owner)
+}
+
+func newK8sSearchSecretClass(name, searchNamespace string) *unstructured.Unstructured {
+	secretClass := &unstructured.Unstructured{}
+	secretClass.SetGroupVersionKind(common.SecretClassGVK)
+	secretClass.SetName(name)
+	k8sSearch := map[string]any{"searchNamespace": map[string]any{}}
+	if searchNamespace != "" {
+		k8sSearch["searchNamespace"] = map[string]any{"name": searchNamespace}
+	}
+	_ = unstructured.SetNestedMap(secretClass.Object, map[string]any{"k8sSearch": k8sSearch}, "spec", "backend")
+	return secretClass
+}
+
+func TestGetLDAPBindCredentials(t *testing.T) {
+	credentials := &commonsv1alpha1.Credentials{SecretClass: "ldap-bind"}
+
+	t.Run("k8sSearch SecretClass", func(t *testing.T) {
+		cli := newLDAPTestClient(t,
+			newK8sSearchSecretClass("ldap-bind", "ldap"),
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ldap-bind-credentials",
+					Namespace: "ldap",
+					Labels:    map[string]string{constants.SecretClassLabel: "ldap-bind"},
+				},
+				Data: map[string][]byte{"user": []byte("cn=admin,dc=example,dc=org"), "password": []byte("s3cret")},
+			},
+		)
+		user, password, err := GetLDAPBindCredentials(context.Background(), cli, credentials)
+		if err != nil {
+			t.Fatalf("GetLDAPBindCredentials() error = %v", err)
+		}
+		if user != "cn=admin,dc=example,dc=org" || password != "s3cret" {
+			t.Errorf("GetLDAPBindCredentials() = %q, %q", user, password)
+		}
+	})
+
+	t.Run("k8sSearch SecretClass without Secret", func(t *testing.T) {
+		cli := newLDAPTestClient(t, newK8sSearchSecretClass("ldap-bind", ""))
+		if _, _, err := GetLDAPBindCredentials(context.Background(), cli, credentials); err == nil {
+			t.Error("GetLDAPBindCredentials() expected an error without a labelled Secret")
+		}
+	})
+
+	t.Run("Secret named after the SecretClass", func(t *testing.T) {
+		cli := newLDAPTestClient(t, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ldap-bind", Namespace: "default"},
+			Data:       map[string][]byte{"user": []byte("cn=admin"), "password": []byte("pw")},
+		})
+		user, password, err := GetLDAPBindCredentials(context.Background(), cli, credentials)
+		if err != nil {
+			t.Fatalf("GetLDAPBindCredentials() error = %v", err)
+		}
+		if user != "cn=admin" || password != "pw" {
+			t.Errorf("GetLDAPBindCredentials() = %q, %q", user, password)
+		}
+	})
+}
+
+func TestCreateLDAPConfig(t *testing.T) {
+	cli := newLDAPTestClient(t, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ldap-bind", Namespace: "default"},
+		Data:       map[string][]byte{"user": []byte("cn=admin"), "password": []byte("pw")},
+	})
+	provider := &authv1alpha1.LDAPProvider{
+		BindCredentials: &commonsv1alpha1.Credentials{SecretClass: "ldap-bind"},
+		Hostname:        "openldap.ldap.svc",
+		SearchBase:      "ou=users,dc=example,dc=org",
+		SearchFilter:    "(&(uid={login}))",
+	}
+
+	tests := []struct {
+		name     string
+		tls      *authv1alpha1.LDAPTLS
+		ldapSpec *dorisv1alpha1.LDAPAuthenticationSpec
+		want     []string
+	}{
+		{
+			name: "plain LDAP",
+			want: []string{
+				"ldap_port=389",
+				"ldap_use_ssl=false",
+				"ldap_admin_name=cn=admin",
+				"ldap_group_basedn=ou=users,dc=example,dc=org",
+			},
+		},
+		{
+			name:     "LDAPS with group search base",
+			tls:      &authv1alpha1.LDAPTLS{Verification: &commonsv1alpha1.TLSVerificationSpec{}},
+			ldapSpec: &dorisv1alpha1.LDAPAuthenticationSpec{GroupSearchBase: "ou=groups,dc=example,dc=org"},
+			want: []string{
+				"ldap_port=636",
+				"ldap_use_ssl=true",
+				"ldap_group_basedn=ou=groups,dc=example,dc=org",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := provider.DeepCopy()
+			p.TLS = tt.tls
+			got := CreateLDAPConfig(context.Background(), cli, p, tt.ldapSpec)
+			for _, want := range tt.want {
+				if !slices.Contains(got, want) {
+					t.Errorf("ldap.conf missing %q, got:\n%s", want, strings.Join(got, "\n"))
+				}
+			}
+		})
+	}
+}
+
+func TestAppendJavaOpts(t *testing.T) {
+	feConfig := []string{
+		"LOG_DIR=/kubedoop/log",
+		"JAVA_OPTS=\"-Xmx1g\"",
+		"JAVA_OPTS_FOR_JDK_17=\"-Xmx2g\"",
+	}
+	got := appendJavaOpts(feConfig, []string{"-Da=b"})
+	want := []string{
+		"LOG_DIR=/kubedoop/log",
+		"JAVA_OPTS=\"-Xmx1g -Da=b\"",
+		"JAVA_OPTS_FOR_JDK_17=\"-Xmx2g -Da=b\"",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("appendJavaOpts() = %q, want %q", got, want)
+	}
+}
@@ -100,7 +100,9 @@ func (r *FEReconciler) CreateServiceReconcilers(
 	reconcilers = append(reconcilers, internalSvc)
 
 	// Create access service
-	accessSvc := NewFEAccessServiceReconciler(client, roleGroupInfo)
+	accessSvc := NewFEAccessServiceReconciler(client, roleGroupInfo, common.GetTLSSpec(r.DorisCluster) != nil,
+		common.GetListenerClass(r.DorisCluster.Spec.Frontend),
+		common.GetArrowFlightPort(r.DorisCluster, constants.ComponentTypeFE))
 	reconcilers = append(reconcilers, accessSvc)
 
 	return reconcilers
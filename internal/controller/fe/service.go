@@ -5,12 +5,19 @@ import (
 	"github.com/zncdatadev/doris-operator/internal/controller/constants"
 	"github.com/zncdatadev/operator-go/pkg/builder"
 	"github.com/zncdatadev/operator-go/pkg/client"
+	opconstants "github.com/zncdatadev/operator-go/pkg/constants"
 	"github.com/zncdatadev/operator-go/pkg/reconciler"
 	corev1 "k8s.io/api/core/v1"
 )
 
-// GetFEServiceConfig returns the default service configuration for FE
-func GetFEServiceConfig() *common.ComponentServiceConfig {
+// GetFEServiceConfig returns the default service configuration for FE.
+// The access service also exposes the HTTPS port when TLS is enabled, and the Arrow Flight
+// port unless it is 0. When exposed outside of Kubernetes, it only exposes the ports used by clients.
+func GetFEServiceConfig(
+	tlsEnabled bool,
+	listenerClass opconstants.ListenerClass,
+	arrowFlightPort int32,
+) *common.ComponentServiceConfig {
 	// Define the FE container ports - use the same names as in the StatefulSet!
 	feQueryPort := corev1.ContainerPort{
 		Name:          constants.FEQueryPortName, // Use constant port name
@@ -40,11 +47,29 @@ func GetFEServiceConfig() *common.ComponentServiceConfig {
 			Protocol:      corev1.ProtocolTCP,
 		},
 	}
+	if common.IsExternal(listenerClass) {
+		accessPorts = []corev1.ContainerPort{accessPorts[0], feQueryPort}
+	}
+	if tlsEnabled {
+		accessPorts = append(accessPorts, corev1.ContainerPort{
+			Name:          constants.FEHttpsPortName,
+			ContainerPort: constants.FEHttpsPort,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+	if arrowFlightPort != 0 {
+		accessPorts = append(accessPorts, corev1.ContainerPort{
+			Name:          constants.ArrowFlightPortName,
+			ContainerPort: arrowFlightPort,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
 
 	return &common.ComponentServiceConfig{
 		ComponentType: constants.ComponentTypeFE,
 		InternalPorts: internalPorts,
 		AccessPorts:   accessPorts,
+		ListenerClass: listenerClass,
 	}
 }
 
@@ -54,7 +79,7 @@ func NewFEInternalServiceReconciler(
 	roleGroupInfo *reconciler.RoleGroupInfo,
 ) reconciler.ResourceReconciler[builder.ServiceBuilder] {
 	// Use FE service configuration
-	feServiceConfig := GetFEServiceConfig()
+	feServiceConfig := GetFEServiceConfig(false, opconstants.ClusterInternal, 0)
 
 	// Create internal service using the common implementation
 	return common.NewInternalServiceReconciler(client, roleGroupInfo, feServiceConfig)
@@ -64,9 +89,12 @@ func NewFEInternalServiceReconciler(
 func NewFEAccessServiceReconciler(
 	client *client.Client,
 	roleGroupInfo *reconciler.RoleGroupInfo,
+	tlsEnabled bool,
+	listenerClass opconstants.ListenerClass,
+	arrowFlightPort int32,
 ) reconciler.ResourceReconciler[builder.ServiceBuilder] {
 	// Use FE service configuration
-	feServiceConfig := GetFEServiceConfig()
+	feServiceConfig := GetFEServiceConfig(tlsEnabled, listenerClass, arrowFlightPort)
 
 	// Create access service using the common implementation
 	return common.NewAccessServiceReconciler(client, roleGroupInfo, feServiceConfig)
@@ -2,10 +2,12 @@ package fe
 
 import (
 	"context"
+	"fmt"
 
 	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
 	"github.com/zncdatadev/doris-operator/internal/controller/common"
 	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	authv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/authentication/v1alpha1"
 	commonsv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/commons/v1alpha1"
 	"github.com/zncdatadev/operator-go/pkg/builder"
 	"github.com/zncdatadev/operator-go/pkg/client"
@@ -22,6 +24,12 @@ import (
 type FeStatefulSetBuilder struct {
 	*common.StatefulSetBuilder
 	feRole *dorisv1alpha1.ConfigSpec
+
+	// ldapProvider is the LDAP authentication provider, nil when LDAP is disabled
+	ldapProvider *authv1alpha1.LDAPProvider
+
+	// clientCertSecretClass issues the client certificates FE requires, empty when disabled
+	clientCertSecretClass string
 }
 
 // NewFeStatefulSetBuilder creates a new FE StatefulSetBuilder
@@ -65,10 +73,24 @@ func (b *FeStatefulSetBuilder) GetMainContainer() *corev1.Container {
 			Protocol:      corev1.ProtocolTCP,
 		},
 	}
+	if common.GetTLSSpec(b.GetDorisCluster()) != nil {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          constants.FEHttpsPortName,
+			ContainerPort: constants.FEHttpsPort,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+	if port := common.GetArrowFlightPort(b.GetDorisCluster(), constants.ComponentTypeFE); port != 0 {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          constants.ArrowFlightPortName,
+			ContainerPort: port,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
 
 	// FE specific health checks
 	livenessProbe := b.CreateTcpProbe(constants.FEQueryPort, constants.DefaultInitialDelaySeconds, constants.DefaultPeriodSeconds)
-	readinessProbe := b.CreateHttpProbe(constants.FEHttpPort, constants.HealthCheckPath, constants.DefaultInitialDelaySeconds, constants.DefaultPeriodSeconds)
+	readinessProbe := b.CreateHttpProbe(getFeHttpPort(b.GetDorisCluster()), constants.HealthCheckPath, constants.DefaultInitialDelaySeconds, constants.DefaultPeriodSeconds)
 
 	// Get resource requirements
 	resources := getFeResourcesSpec()
@@ -110,6 +132,10 @@ func (b *FeStatefulSetBuilder) GetMainContainer() *corev1.Container {
 		readinessProbe,
 	)
 
+	// FE may replay its metadata for long before it listens, and drains its running queries and loads before it stops
+	container.StartupProbe = b.CreateStartupProbe(livenessProbe, b.feRole, constants.FEStartupFailureThreshold)
+	container.Lifecycle = b.CreateDrainPreStopHook(getFeHttpPort(b.GetDorisCluster()), feDrainMetrics)
+
 	// Add FE specific environment variables
 	container.Env = append(container.Env, corev1.EnvVar{
 		Name:  constants.FEElectNumberEnvVar,
@@ -123,17 +149,76 @@ func (b *FeStatefulSetBuilder) GetMainContainer() *corev1.Container {
 			MountPath: constants.FEMetadataPath,
 		},
 	)
+	if GetLDAPCASecretClass(b.ldapProvider) != "" {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      constants.LDAPTLSVolumeName,
+			MountPath: constants.LDAPTLSMountPath,
+			ReadOnly:  true,
+		})
+	}
+	if b.clientCertSecretClass != "" {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      constants.ClientTrustVolumeName,
+			MountPath: constants.ClientTrustMountPath,
+			ReadOnly:  true,
+		})
+	}
 
 	return container
 }
 
-// GetInitContainers FE doesn't need initialization containers
+// GetInitContainers returns the FE init containers: with client certificate authentication,
+// the truststore of the clientCertSecretClass is extended with the CA of the operator client
+// certificate, so that FE accepts the operator besides the clients of the SecretClass.
 func (b *FeStatefulSetBuilder) GetInitContainers() []corev1.Container {
-	return []corev1.Container{} // FE has no init containers
+	if b.clientCertSecretClass == "" {
+		return []corev1.Container{}
+	}
+	command := fmt.Sprintf(`set -e
+cp %[1]s %[2]s
+"${JAVA_HOME:+$JAVA_HOME/bin/}keytool" -importcert -noprompt -alias doris-operator -file %[3]s/%[4]s \
+  -keystore %[2]s -storetype PKCS12 -storepass %[5]s`,
+		constants.ClientTLSTruststorePath, constants.ClientTrustTruststorePath,
+		constants.OperatorClientCAMountPath, constants.TLSCACertKey, constants.TLSKeystorePassword)
+	container := builder.NewContainerBuilder(constants.ClientTrustInitContainerName, b.GetImage()).
+		SetCommand([]string{"sh", "-c"}).
+		SetArgs([]string{command}).
+		AddVolumeMounts([]corev1.VolumeMount{
+			{Name: constants.ClientTLSVolumeName, MountPath: constants.ClientTLSMountPath, ReadOnly: true},
+			{Name: constants.OperatorClientCAVolumeName, MountPath: constants.OperatorClientCAMountPath, ReadOnly: true},
+			{Name: constants.ClientTrustVolumeName, MountPath: constants.ClientTrustMountPath},
+		}).
+		Build()
+	return []corev1.Container{*container}
 }
 
 // GetVolumes implements ComponentInterface, returns FE specific volumes
 func (b *FeStatefulSetBuilder) GetVolumes() []corev1.Volume {
+	var volumes []corev1.Volume
+	if secretClass := GetLDAPCASecretClass(b.ldapProvider); secretClass != "" {
+		volumes = append(volumes, NewTrustStoreVolume(constants.LDAPTLSVolumeName, secretClass))
+	}
+	if b.clientCertSecretClass != "" {
+		volumes = append(volumes,
+			NewTrustStoreVolume(constants.ClientTLSVolumeName, b.clientCertSecretClass),
+			corev1.Volume{
+				Name: constants.OperatorClientCAVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: OperatorClientCASecretName(b.GetDorisCluster().Name),
+						Items:      []corev1.KeyToPath{{Key: constants.TLSCACertKey, Path: constants.TLSCACertKey}},
+					},
+				},
+			},
+			corev1.Volume{
+				Name:         constants.ClientTrustVolumeName,
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			},
+		)
+	}
+	if len(volumes) > 0 {
+		return volumes
+	}
 	return []corev1.Volume{
 		// {
 		// 	Name: constants.ConfigVolumeName,
@@ -186,6 +271,12 @@ func (b *FeStatefulSetBuilder) GetVolumeClaimTemplates() []corev1.PersistentVolu
 	}
 }
 
+// VolumeClaimTemplates returns the desired FE PVC templates for a merged role group config.
+// It is used to detect storage changes on StatefulSets that already exist.
+func VolumeClaimTemplates(config *dorisv1alpha1.ConfigSpec) []corev1.PersistentVolumeClaim {
+	return NewFeStatefulSetBuilder(nil, config).GetVolumeClaimTemplates()
+}
+
 // GetAdditionalEnvVars implements ComponentInterface, returns FE specific environment variables
 func (b *FeStatefulSetBuilder) GetAdditionalEnvVars() []corev1.EnvVar {
 	return []corev1.EnvVar{
@@ -248,6 +339,13 @@ func NewFeStatefulSetReconciler(
 	)
 
 	feBuilder := NewFeStatefulSetBuilder(commonBuilder, roleGroupConfig)
+	if dorisCluster.Spec.ClusterConfig != nil {
+		auth := ResolveAuthentication(ctx, client, dorisCluster.Spec.ClusterConfig.Authentication)
+		feBuilder.ldapProvider = auth.LDAP
+		if auth.ClientAuthEnabled(dorisCluster) {
+			feBuilder.clientCertSecretClass = auth.TLS.ClientCertSecretClass
+		}
+	}
 	// Set stopped flag
 	stopped := clusterOperation != nil && clusterOperation.Stopped
 	return reconciler.NewStatefulSet(
@@ -256,3 +354,16 @@ func NewFeStatefulSetReconciler(
 		stopped,
 	), nil
 }
+
+// feDrainMetrics are the FE metrics of the running query fragment instances and transactions.
+// Open MySQL connections are not drained on: pooled clients keep idle connections open, so
+// the hook would always wait for the whole grace period.
+const feDrainMetrics = "doris_fe_query_instance_num|doris_fe_txn_num"
+
+// getFeHttpPort returns the port FE serves its HTTP API on: the HTTPS port when TLS is enabled
+func getFeHttpPort(dorisCluster *dorisv1alpha1.DorisCluster) int32 {
+	if common.GetTLSSpec(dorisCluster) != nil {
+		return constants.FEHttpsPort
+	}
+	return constants.FEHttpPort
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"context"
+	"math"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	"github.com/zncdatadev/doris-operator/internal/controller/health"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// healthyConditionType reports whether the data of the cluster is healthy in Doris
+const healthyConditionType = "Healthy"
+
+// evaluateHealth reports the data health before the cluster is ready, so that the Healthy
+// condition follows the rollouts keeping the cluster from being ready. It returns nil when
+// the health cannot be evaluated, e.g. while FE is not reachable yet.
+func (r *DorisClusterReconciler) evaluateHealth(ctx context.Context, instance *dorisv1alpha1.DorisCluster) *health.Report {
+	mgmtClient, err := r.preReadyClient(ctx, instance)
+	if err != nil {
+		logger.Info("Failed to connect to Doris to evaluate the cluster health", "cluster", instance.Name, "error", err.Error())
+		return nil
+	}
+	defer r.DorisClients.Put(mgmtClient)
+	report, err := r.reconcileHealth(ctx, instance, mgmtClient)
+	if err != nil {
+		logger.Error(err, "Failed to evaluate the cluster health", "cluster", instance.Name)
+	}
+	return report
+}
+
+// reconcileHealth evaluates the data health of the cluster from the nodes and tablets Doris
+// lists, and reports it in the health status and the Healthy condition.
+func (r *DorisClusterReconciler) reconcileHealth(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	mgmtClient *doris_client.DorisClient,
+) (*health.Report, error) {
+	frontends, err := mgmtClient.ShowFrontends(ctx)
+	if err != nil {
+		return nil, err
+	}
+	backends, err := mgmtClient.ShowBackends(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tablets, err := mgmtClient.GetTabletHealth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := health.Evaluate(frontends, backends, *tablets)
+
+	healthStatus := &dorisv1alpha1.HealthStatus{
+		Score:                    report.Score,
+		TabletNum:                int32(report.TabletNum),
+		UnhealthyTabletNum:       int32(report.UnhealthyTabletNum),
+		UnderReplicatedTabletNum: int32(report.UnderReplicatedTabletNum),
+		MaxDiskUsedPercent:       int32(math.Round(report.MaxDiskUsedPct)),
+		MaxJournalLag:            int64(report.MaxJournalLag),
+	}
+	condition := metav1.Condition{
+		Type:               healthyConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             report.Reason(),
+		Message:            report.Message(),
+		ObservedGeneration: instance.Generation,
+	}
+	if !report.Healthy() {
+		condition.Status = metav1.ConditionFalse
+	}
+
+	current := apimeta.FindStatusCondition(instance.Status.Conditions, condition.Type)
+	conditionChanged := current == nil || current.Status != condition.Status || current.Reason != condition.Reason ||
+		current.Message != condition.Message || current.ObservedGeneration != condition.ObservedGeneration
+	if !conditionChanged && !healthStatusChanged(instance.Status.Health, healthStatus) {
+		return report, nil
+	}
+	if conditionChanged {
+		messages := make([]string, 0, len(report.Problems))
+		for _, problem := range report.Problems {
+			messages = append(messages, problem.Message)
+		}
+		logger.Info("Cluster health changed", "cluster", instance.Name, "healthy", report.Healthy(),
+			"score", report.Score, "reason", condition.Reason, "problems", messages)
+	}
+	err = r.patchStatus(ctx, instance, func(status *dorisv1alpha1.DorisClusterStatus) {
+		status.Health = healthStatus
+		apimeta.SetStatusCondition(&status.Conditions, condition)
+	})
+	return report, err
+}
+
+// healthStatusChanged returns whether the health status must be updated. The counts keep
+// changing while tablets are repaired or FE replays its journal, so they are only updated
+// when they change by more than a tenth or reach or leave zero, to not update the status on
+// every reconcile.
+func healthStatusChanged(current, next *dorisv1alpha1.HealthStatus) bool {
+	if current == nil {
+		return true
+	}
+	if current.Score != next.Score || current.MaxDiskUsedPercent != next.MaxDiskUsedPercent {
+		return true
+	}
+	return countChanged(int64(current.TabletNum), int64(next.TabletNum)) ||
+		countChanged(int64(current.UnhealthyTabletNum), int64(next.UnhealthyTabletNum)) ||
+		countChanged(int64(current.UnderReplicatedTabletNum), int64(next.UnderReplicatedTabletNum)) ||
+		countChanged(current.MaxJournalLag, next.MaxJournalLag)
+}
+
+// countChanged returns whether a count changed by more than a tenth, or reached or left zero
+func countChanged(current, next int64) bool {
+	if (current == 0) != (next == 0) {
+		return true
+	}
+	diff := next - current
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff*10 > max(current, next)
+}
@@ -0,0 +1,215 @@
+package health
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+)
+
+const (
+	// DiskHighWatermarkPct is the default storage_high_watermark_usage_percent of BE, above
+	// which Doris stops placing new replicas on the disk
+	DiskHighWatermarkPct = 85
+	// DiskFloodStagePct is the default storage_flood_stage_usage_percent of BE, above which
+	// loads to the disk are rejected
+	DiskFloodStagePct = 95
+
+	// JournalLagThreshold is the number of edit log entries a FE may be behind the master
+	JournalLagThreshold = 1000
+)
+
+// Reasons of the Healthy condition, from the most to the least severe problem
+const (
+	ReasonHealthy                = "Healthy"
+	ReasonUnrecoverableTablets   = "UnrecoverableTablets"
+	ReasonUnderReplicatedTablets = "UnderReplicatedTablets"
+	ReasonDiskFull               = "DiskFull"
+	ReasonNodesNotAlive          = "NodesNotAlive"
+	ReasonFrontendLagging        = "FrontendLagging"
+	ReasonDiskUsageHigh          = "DiskUsageHigh"
+	ReasonCompactionTooSlow      = "CompactionTooSlow"
+	ReasonUnhealthyTablets       = "UnhealthyTablets"
+)
+
+// Problem is a health problem found in the cluster
+type Problem struct {
+	Reason  string
+	Message string
+	// Penalty is deducted from the health score
+	Penalty int32
+}
+
+// Report summarizes the data health of a cluster
+type Report struct {
+	// Score goes from 0 to 100, a cluster without problems scores 100
+	Score    int32
+	Problems []Problem
+
+	TabletNum                int
+	UnhealthyTabletNum       int
+	UnderReplicatedTabletNum int
+	MaxDiskUsedPct           float64
+	MaxJournalLag            int
+}
+
+// Healthy returns true when no problem was found
+func (r *Report) Healthy() bool {
+	return len(r.Problems) == 0
+}
+
+// Reason returns the reason of the most severe problem
+func (r *Report) Reason() string {
+	if r.Healthy() {
+		return ReasonHealthy
+	}
+	return r.Problems[0].Reason
+}
+
+// Bands of the health score
+const (
+	BandGood     = "Good"
+	BandDegraded = "Degraded"
+	BandCritical = "Critical"
+)
+
+// Band returns the band of the health score: Good from 90, Degraded from 50, Critical below
+func (r *Report) Band() string {
+	switch {
+	case r.Score >= 90:
+		return BandGood
+	case r.Score >= 50:
+		return BandDegraded
+	default:
+		return BandCritical
+	}
+}
+
+// Message returns the score band and the reasons of the problems found. The counts of the
+// problems are left out, so that the message only changes with the problems.
+func (r *Report) Message() string {
+	message := "Health " + r.Band()
+	var reasons []string
+	for _, problem := range r.Problems {
+		if !slices.Contains(reasons, problem.Reason) {
+			reasons = append(reasons, problem.Reason)
+		}
+	}
+	if len(reasons) > 0 {
+		message += ": " + strings.Join(reasons, ", ")
+	}
+	return message
+}
+
+// DataAtRisk returns true when tablets are missing replicas: moving replicas off a BE
+// then may lose the last copy of data
+func (r *Report) DataAtRisk() bool {
+	return slices.ContainsFunc(r.Problems, func(problem Problem) bool {
+		return problem.Reason == ReasonUnrecoverableTablets || problem.Reason == ReasonUnderReplicatedTablets
+	})
+}
+
+// severity orders the reasons from the most severe problem
+var severity = []string{
+	ReasonUnrecoverableTablets,
+	ReasonUnderReplicatedTablets,
+	ReasonDiskFull,
+	ReasonNodesNotAlive,
+	ReasonFrontendLagging,
+	ReasonDiskUsageHigh,
+	ReasonCompactionTooSlow,
+	ReasonUnhealthyTablets,
+}
+
+// Evaluate summarizes the nodes and the tablet health listed by Doris into a health report.
+// Decommissioned BEs are expected to go away and are not reported as not alive.
+func Evaluate(
+	frontends []doris_client.FrontendInfo,
+	backends []doris_client.BackendInfo,
+	tablets doris_client.TabletHealth,
+) *Report {
+	report := &Report{
+		TabletNum:                tablets.TabletNum,
+		UnhealthyTabletNum:       max(0, tablets.TabletNum-tablets.HealthyNum),
+		UnderReplicatedTabletNum: tablets.ReplicaMissingNum + tablets.VersionIncompleteNum,
+	}
+	add := func(reason string, penalty int32, format string, args ...any) {
+		report.Problems = append(report.Problems, Problem{Reason: reason, Message: fmt.Sprintf(format, args...), Penalty: penalty})
+	}
+
+	if tablets.UnrecoverableNum > 0 {
+		add(ReasonUnrecoverableTablets, 50, "%d tablets have no healthy replica left", tablets.UnrecoverableNum)
+	}
+	if report.UnderReplicatedTabletNum > 0 {
+		// Up to 30 in proportion to the tablets at risk, at least 10
+		ratio := float64(report.UnderReplicatedTabletNum) / float64(max(1, tablets.TabletNum))
+		add(ReasonUnderReplicatedTablets, int32(max(10, math.Ceil(30*ratio))),
+			"%d of %d tablets are under-replicated", report.UnderReplicatedTabletNum, tablets.TabletNum)
+	}
+	if tablets.CompactionTooSlowNum > 0 {
+		add(ReasonCompactionTooSlow, 10, "%d tablets have replicas with too many versions to compact",
+			tablets.CompactionTooSlowNum)
+	}
+	if other := report.UnhealthyTabletNum - report.UnderReplicatedTabletNum - tablets.UnrecoverableNum -
+		tablets.CompactionTooSlowNum; other > 0 {
+		add(ReasonUnhealthyTablets, 5, "%d tablets are being repaired or balanced", other)
+	}
+
+	var dead []string
+	var fullest string
+	for _, be := range backends {
+		if !be.Alive && !be.Decommission {
+			dead = append(dead, "BE "+be.Host)
+		}
+		if be.MaxDiskUsedPct > report.MaxDiskUsedPct {
+			report.MaxDiskUsedPct, fullest = be.MaxDiskUsedPct, be.Host
+		}
+	}
+	switch {
+	case report.MaxDiskUsedPct >= DiskFloodStagePct:
+		add(ReasonDiskFull, 30, "a disk of BE %s is %.1f%% full, loads are rejected", fullest, report.MaxDiskUsedPct)
+	case report.MaxDiskUsedPct >= DiskHighWatermarkPct:
+		add(ReasonDiskUsageHigh, 10, "a disk of BE %s is %.1f%% full", fullest, report.MaxDiskUsedPct)
+	}
+
+	masterJournal := -1
+	for _, fe := range frontends {
+		if fe.IsMaster && fe.Alive {
+			masterJournal = fe.ReplayedJournalID
+		}
+	}
+	var lagging string
+	for _, fe := range frontends {
+		if !fe.Alive {
+			dead = append(dead, "FE "+fe.Host)
+			continue
+		}
+		if masterJournal < 0 || fe.IsMaster {
+			continue
+		}
+		if lag := masterJournal - fe.ReplayedJournalID; lag > report.MaxJournalLag {
+			report.MaxJournalLag, lagging = lag, fe.Host
+		}
+	}
+	if len(frontends) > 0 && masterJournal < 0 {
+		add(ReasonNodesNotAlive, 30, "no alive master FE")
+	}
+	if len(dead) > 0 {
+		add(ReasonNodesNotAlive, int32(min(30, 10*len(dead))), "%d nodes are not alive: %v", len(dead), dead)
+	}
+	if report.MaxJournalLag > JournalLagThreshold {
+		add(ReasonFrontendLagging, 10, "FE %s is %d journal entries behind the master", lagging, report.MaxJournalLag)
+	}
+
+	slices.SortStableFunc(report.Problems, func(a, b Problem) int {
+		return slices.Index(severity, a.Reason) - slices.Index(severity, b.Reason)
+	})
+	report.Score = 100
+	for _, problem := range report.Problems {
+		report.Score -= problem.Penalty
+	}
+	report.Score = max(0, report.Score)
+	return report
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"testing"
+
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+)
+
+func TestEvaluate(t *testing.T) {
+	frontends := []doris_client.FrontendInfo{
+		{Host: "fe-0", IsMaster: true, Alive: true, ReplayedJournalID: 5000},
+		{Host: "fe-1", Alive: true, ReplayedJournalID: 4990},
+		{Host: "fe-2", Alive: true, ReplayedJournalID: 4980},
+	}
+	backends := []doris_client.BackendInfo{
+		{Host: "be-0", Alive: true, MaxDiskUsedPct: 40.5},
+		{Host: "be-1", Alive: true, MaxDiskUsedPct: 60.25},
+		{Host: "be-2", Alive: false, Decommission: true},
+	}
+	tablets := doris_client.TabletHealth{TabletNum: 100, HealthyNum: 100}
+
+	tests := []struct {
+		name       string
+		mutate     func(fes []doris_client.FrontendInfo, bes []doris_client.BackendInfo, tablets *doris_client.TabletHealth)
+		score      int32
+		reason     string
+		dataAtRisk bool
+	}{
+		{
+			name:   "healthy",
+			score:  100,
+			reason: ReasonHealthy,
+		},
+		{
+			name: "under-replicated tablets",
+			mutate: func(_ []doris_client.FrontendInfo, _ []doris_client.BackendInfo, tablets *doris_client.TabletHealth) {
+				tablets.HealthyNum, tablets.ReplicaMissingNum, tablets.VersionIncompleteNum = 50, 40, 10
+			},
+			score:      85,
+			reason:     ReasonUnderReplicatedTablets,
+			dataAtRisk: true,
+		},
+		{
+			name: "few under-replicated tablets",
+			mutate: func(_ []doris_client.FrontendInfo, _ []doris_client.BackendInfo, tablets *doris_client.TabletHealth) {
+				tablets.HealthyNum, tablets.ReplicaMissingNum = 99, 1
+			},
+			score:      90,
+			reason:     ReasonUnderReplicatedTablets,
+			dataAtRisk: true,
+		},
+		{
+			name: "unrecoverable tablets come first",
+			mutate: func(_ []doris_client.FrontendInfo, _ []doris_client.BackendInfo, tablets *doris_client.TabletHealth) {
+				tablets.HealthyNum, tablets.CompactionTooSlowNum, tablets.UnrecoverableNum = 97, 1, 2
+			},
+			score:      40,
+			reason:     ReasonUnrecoverableTablets,
+			dataAtRisk: true,
+		},
+		{
+			name: "tablets being balanced",
+			mutate: func(_ []doris_client.FrontendInfo, _ []doris_client.BackendInfo, tablets *doris_client.TabletHealth) {
+				tablets.HealthyNum = 98
+			},
+			score:  95,
+			reason: ReasonUnhealthyTablets,
+		},
+		{
+			name: "disk above high watermark",
+			mutate: func(_ []doris_client.FrontendInfo, bes []doris_client.BackendInfo, _ *doris_client.TabletHealth) {
+				bes[1].MaxDiskUsedPct = 90
+			},
+			score:  90,
+			reason: ReasonDiskUsageHigh,
+		},
+		{
+			name: "disk full",
+			mutate: func(_ []doris_client.FrontendInfo, bes []doris_client.BackendInfo, _ *doris_client.TabletHealth) {
+				bes[0].MaxDiskUsedPct = 96
+			},
+			score:  70,
+			reason: ReasonDiskFull,
+		},
+		{
+			name: "nodes not alive",
+			mutate: func(fes []doris_client.FrontendInfo, bes []doris_client.BackendInfo, _ *doris_client.TabletHealth) {
+				fes[2].Alive = false
+				bes[0].Alive = false
+			},
+			score:  80,
+			reason: ReasonNodesNotAlive,
+		},
+		{
+			name: "lagging follower",
+			mutate: func(fes []doris_client.FrontendInfo, _ []doris_client.BackendInfo, _ *doris_client.TabletHealth) {
+				fes[1].ReplayedJournalID = 3000
+			},
+			score:  90,
+			reason: ReasonFrontendLagging,
+		},
+		{
+			name: "no master",
+			mutate: func(fes []doris_client.FrontendInfo, _ []doris_client.BackendInfo, _ *doris_client.TabletHealth) {
+				fes[0].Alive = false
+			},
+			score:  60,
+			reason: ReasonNodesNotAlive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fes := append([]doris_client.FrontendInfo(nil), frontends...)
+			bes := append([]doris_client.BackendInfo(nil), backends...)
+			th := tablets
+			if tt.mutate != nil {
+				tt.mutate(fes, bes, &th)
+			}
+
+			report := Evaluate(fes, bes, th)
+			if report.Score != tt.score {
+				t.Errorf("Score = %d, want %d (problems %+v)", report.Score, tt.score, report.Problems)
+			}
+			if report.Reason() != tt.reason {
+				t.Errorf("Reason() = %q, want %q", report.Reason(), tt.reason)
+			}
+			if report.Healthy() != (tt.reason == ReasonHealthy) {
+				t.Errorf("Healthy() = %v with reason %q", report.Healthy(), tt.reason)
+			}
+			if report.DataAtRisk() != tt.dataAtRisk {
+				t.Errorf("DataAtRisk() = %v, want %v", report.DataAtRisk(), tt.dataAtRisk)
+			}
+		})
+	}
+}
+
+func TestEvaluateSummary(t *testing.T) {
+	frontends := []doris_client.FrontendInfo{
+		{Host: "fe-0", IsMaster: true, Alive: true, ReplayedJournalID: 5000},
+		{Host: "fe-1", Alive: true, ReplayedJournalID: 4900},
+	}
+	backends := []doris_client.BackendInfo{
+		{Host: "be-0", Alive: true, MaxDiskUsedPct: 40.5},
+		{Host: "be-1", Alive: true, MaxDiskUsedPct: 60.25},
+	}
+	tablets := doris_client.TabletHealth{TabletNum: 100, HealthyNum: 95, ReplicaMissingNum: 3}
+
+	report := Evaluate(frontends, backends, tablets)
+	if report.TabletNum != 100 || report.UnhealthyTabletNum != 5 || report.UnderReplicatedTabletNum != 3 {
+		t.Errorf("tablets = %d/%d/%d, want 100/5/3",
+			report.TabletNum, report.UnhealthyTabletNum, report.UnderReplicatedTabletNum)
+	}
+	if report.MaxDiskUsedPct != 60.25 {
+		t.Errorf("MaxDiskUsedPct = %v, want 60.25", report.MaxDiskUsedPct)
+	}
+	if report.MaxJournalLag != 100 {
+		t.Errorf("MaxJournalLag = %d, want 100", report.MaxJournalLag)
+	}
+}
+
+func TestReportMessage(t *testing.T) {
+	backends := []doris_client.BackendInfo{{Host: "be-0", Alive: true}, {Host: "be-1"}, {Host: "be-2"}}
+
+	report := Evaluate(nil, backends[:1], doris_client.TabletHealth{TabletNum: 100, HealthyNum: 100})
+	if got := report.Message(); got != "Health Good" {
+		t.Errorf("Message() = %q, want %q", got, "Health Good")
+	}
+
+	report = Evaluate(nil, backends, doris_client.TabletHealth{TabletNum: 100, HealthyNum: 90, ReplicaMissingNum: 10})
+	want := "Health Degraded: UnderReplicatedTablets, NodesNotAlive"
+	if got := report.Message(); got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+	// The message does not change with the counts of the problems
+	more := Evaluate(nil, backends, doris_client.TabletHealth{TabletNum: 100, HealthyNum: 88, ReplicaMissingNum: 12})
+	if more.Message() != report.Message() {
+		t.Errorf("Message() = %q, want %q", more.Message(), report.Message())
+	}
+}
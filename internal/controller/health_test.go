@@ -0,0 +1,49 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+)
+
+func TestHealthStatusChanged(t *testing.T) {
+	current := &dorisv1alpha1.HealthStatus{Score: 90, TabletNum: 1000, UnhealthyTabletNum: 100, MaxJournalLag: 500}
+
+	tests := []struct {
+		name string
+		next dorisv1alpha1.HealthStatus
+		want bool
+	}{
+		{"unchanged", *current, false},
+		{"small changes", dorisv1alpha1.HealthStatus{Score: 90, TabletNum: 1010, UnhealthyTabletNum: 95, MaxJournalLag: 540}, false},
+		{"score", dorisv1alpha1.HealthStatus{Score: 85, TabletNum: 1000, UnhealthyTabletNum: 100, MaxJournalLag: 500}, true},
+		{"tablets repaired", dorisv1alpha1.HealthStatus{Score: 90, TabletNum: 1000, UnhealthyTabletNum: 80, MaxJournalLag: 500}, true},
+		{"journal caught up", dorisv1alpha1.HealthStatus{Score: 90, TabletNum: 1000, UnhealthyTabletNum: 100}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := healthStatusChanged(current, &tt.next); got != tt.want {
+				t.Errorf("healthStatusChanged() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+	if !healthStatusChanged(nil, current) {
+		t.Error("healthStatusChanged() = false without a health status")
+	}
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/fe"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// reconcileIngress exposes the FE HTTP port with an Ingress, or an HTTPRoute when a gateway
+// is configured, and deletes the one not configured anymore.
+func (r *DorisClusterReconciler) reconcileIngress(ctx context.Context, instance *dorisv1alpha1.DorisCluster) error {
+	spec := fe.GetIngressSpec(instance)
+	name := fe.IngressName(instance.Name)
+	ingress := fe.NewIngress(name, instance.Namespace)
+	route := fe.NewHTTPRoute(name, instance.Namespace)
+
+	if spec == nil || spec.Gateway != nil {
+		if err := r.deleteOwned(ctx, instance, ingress); err != nil {
+			return err
+		}
+	}
+	if spec == nil || spec.Gateway == nil {
+		if err := r.deleteOwned(ctx, instance, route); err != nil && !apimeta.IsNoMatchError(err) {
+			return err
+		}
+	}
+	if spec == nil {
+		return nil
+	}
+
+	if spec.Gateway != nil {
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, route, func() error {
+			if err := fe.MutateHTTPRoute(route, instance); err != nil {
+				return err
+			}
+			return controllerutil.SetControllerReference(instance, route, r.Scheme)
+		}); err != nil {
+			if apimeta.IsNoMatchError(err) {
+				return fmt.Errorf("failed to reconcile HTTPRoute %s, the Gateway API CRDs are not installed: %w", name, err)
+			}
+			return fmt.Errorf("failed to reconcile HTTPRoute %s: %w", name, err)
+		}
+		return nil
+	}
+
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, ingress, func() error {
+		fe.MutateIngress(ingress, instance)
+		return controllerutil.SetControllerReference(instance, ingress, r.Scheme)
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile Ingress %s: %w", name, err)
+	}
+	return nil
+}
+
+// deleteOwned deletes the object if it exists and is controlled by the cluster
+func (r *DorisClusterReconciler) deleteOwned(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	obj ctrlclient.Object,
+) error {
+	if err := r.Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, obj); err != nil {
+		return ctrlclient.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(obj, instance) {
+		return nil
+	}
+	if err := r.Delete(ctx, obj); ctrlclient.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete %s: %w", obj.GetName(), err)
+	}
+	logger.Info("Deleted resource no longer configured", "cluster", instance.Name, "name", obj.GetName())
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/fe"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileIngress(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = dorisv1alpha1.AddToScheme(s)
+
+	instance := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testClusterName, Namespace: testClusterNamespace, UID: "uid"},
+		Spec: dorisv1alpha1.DorisClusterSpec{
+			Frontend: &dorisv1alpha1.RoleSpec{},
+			ClusterConfig: &dorisv1alpha1.ClusterConfigSpec{
+				IngressHost: "doris.example.org",
+				Ingress:     &dorisv1alpha1.IngressSpec{Paths: []string{"/"}},
+			},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(instance).Build()
+	r := &DorisClusterReconciler{Client: cli, Scheme: s}
+
+	if err := r.reconcileIngress(ctx, instance); err != nil {
+		t.Fatalf("reconcileIngress() error = %v", err)
+	}
+	key := types.NamespacedName{Name: fe.IngressName(testClusterName), Namespace: testClusterNamespace}
+	ingress := &networkingv1.Ingress{}
+	if err := cli.Get(ctx, key, ingress); err != nil {
+		t.Fatalf("expected Ingress %s: %v", key, err)
+	}
+	if !metav1.IsControlledBy(ingress, instance) {
+		t.Error("expected Ingress to be controlled by the cluster")
+	}
+	if ingress.Spec.Rules[0].Host != "doris.example.org" {
+		t.Errorf("Host = %s, want doris.example.org", ingress.Spec.Rules[0].Host)
+	}
+
+	instance.Spec.ClusterConfig.Ingress = nil
+	if err := r.reconcileIngress(ctx, instance); err != nil {
+		t.Fatalf("reconcileIngress() error = %v", err)
+	}
+	if err := cli.Get(ctx, key, &networkingv1.Ingress{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected Ingress to be deleted, got %v", err)
+	}
+}
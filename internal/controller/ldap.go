@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	"github.com/zncdatadev/doris-operator/internal/controller/fe"
+	"github.com/zncdatadev/operator-go/pkg/client"
+)
+
+// ldapHash returns the hash of the LDAP settings applied to Doris recorded in status
+func ldapHash(key []byte, bindPassword string, mappings []dorisv1alpha1.LDAPGroupRoleMapping) (string, error) {
+	data, err := json.Marshal(mappings)
+	if err != nil {
+		return "", err
+	}
+	return keyedHash(key, []byte(bindPassword), data), nil
+}
+
+// reconcileLDAP applies the LDAP settings Doris keeps in its metadata rather than in
+// ldap.conf: the bind password, and the roles of the mapped LDAP groups with their grants.
+// They are applied as root, since setting the LDAP admin password requires ADMIN_PRIV.
+func (r *DorisClusterReconciler) reconcileLDAP(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	target doris_client.ClusterTarget,
+	auth *fe.Authentication,
+) error {
+	provider, ldapSpec := auth.LDAP, auth.LDAPSpec
+	if provider == nil {
+		return nil
+	}
+	resourceClient := &client.Client{Client: r.Client, OwnerReference: instance}
+
+	_, bindPassword, err := fe.GetLDAPBindCredentials(ctx, resourceClient, provider.BindCredentials)
+	if err != nil {
+		return err
+	}
+	key, err := r.hashKey(ctx, instance)
+	if err != nil {
+		return err
+	}
+	hash, err := ldapHash(key, bindPassword, ldapSpec.GroupRoleMappings)
+	if err != nil {
+		return err
+	}
+	if hash == instance.Status.LDAPHash {
+		return nil
+	}
+
+	rootTarget, err := r.rootClusterTarget(ctx, instance, target)
+	if err != nil {
+		return err
+	}
+	rootClient, err := r.DorisClients.Get(ctx, rootTarget)
+	if err != nil {
+		return fmt.Errorf("failed to log in to Doris as root: %w", err)
+	}
+	defer r.DorisClients.Put(rootClient)
+	if target.User != doris_client.DefaultAdminUser {
+		defer r.DorisClients.Release(instance.UID, doris_client.DefaultAdminUser)
+	}
+
+	if err := rootClient.SetLDAPAdminPassword(ctx, bindPassword); err != nil {
+		return err
+	}
+	for _, mapping := range ldapSpec.GroupRoleMappings {
+		if err := rootClient.CreateRole(ctx, mapping.Group); err != nil {
+			return err
+		}
+		for _, grant := range mapping.Grants {
+			if err := rootClient.GrantToRole(ctx, mapping.Group, grant.Privileges, grant.On); err != nil {
+				return err
+			}
+		}
+	}
+
+	logger.Info("Applied LDAP settings", "cluster", instance.Name, "groupRoleMappings", len(ldapSpec.GroupRoleMappings))
+	return r.patchStatus(ctx, instance, func(status *dorisv1alpha1.DorisClusterStatus) {
+		status.LDAPHash = hash
+	})
+}
@@ -0,0 +1,239 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/be"
+	"github.com/zncdatadev/doris-operator/internal/controller/common"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// publicEndpointTag is the BE tag FE redirects Stream Load clients to with `redirect-policy: public`
+const publicEndpointTag = "public_endpoint"
+
+// wantedBEListeners returns the BE pod names exposed by a listener Service, by Service name
+func wantedBEListeners(instance *dorisv1alpha1.DorisCluster) map[string]string {
+	wanted := map[string]string{}
+	if instance.Spec.Backend == nil || !common.IsExternal(common.GetListenerClass(instance.Spec.Backend)) {
+		return wanted
+	}
+	for name, roleGroup := range instance.Spec.Backend.RoleGroups {
+		for i := range ptr.Deref(roleGroup.Replicas, 1) {
+			podName := fmt.Sprintf("%s-%s-%s-%d", instance.Name, constants.ComponentTypeBE, name, i)
+			wanted[be.PodListenerServiceName(podName)] = podName
+		}
+	}
+	return wanted
+}
+
+// reconcileBEListeners deletes the BE listener Services of removed pods and advertises the
+// address of the others to Doris as the public endpoint of their BE.
+func (r *DorisClusterReconciler) reconcileBEListeners(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	mgmtClient *doris_client.DorisClient,
+) error {
+	wanted := wantedBEListeners(instance)
+
+	services := &corev1.ServiceList{}
+	if err := r.List(ctx, services, ctrlclient.InNamespace(instance.Namespace), ctrlclient.MatchingLabels{
+		constants.OwnerReferenceLabelKey: instance.Name,
+		constants.ServiceRoleLabelKey:    string(common.ServiceTypeListener),
+	}); err != nil {
+		return fmt.Errorf("failed to list BE listener Services: %w", err)
+	}
+	endpoints := map[string]string{}
+	for i := range services.Items {
+		svc := &services.Items[i]
+		podName, ok := wanted[svc.Name]
+		if !ok {
+			if err := r.Delete(ctx, svc); ctrlclient.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to delete BE listener Service %s: %w", svc.Name, err)
+			}
+			logger.Info("Deleted BE listener Service", "cluster", instance.Name, "service", svc.Name)
+			continue
+		}
+		endpoint, err := r.listenerEndpoint(ctx, svc, podName, constants.BEHttpPortName)
+		if err != nil {
+			return err
+		}
+		if endpoint != "" {
+			endpoints[podName] = endpoint
+		}
+	}
+
+	backends, err := mgmtClient.ShowBackends(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i := range backends {
+		backend := &backends[i]
+		endpoint := backendEndpoint(backend.Host, endpoints)
+		// Endpoints not resolved yet are kept until the address of their Service is known
+		if endpoint == "" && len(wanted) > 0 {
+			continue
+		}
+		if backend.Tags[publicEndpointTag] == endpoint {
+			continue
+		}
+
+		tags := maps.Clone(backend.Tags)
+		if tags == nil {
+			tags = map[string]string{}
+		}
+		if endpoint == "" {
+			delete(tags, publicEndpointTag)
+		} else {
+			tags[publicEndpointTag] = endpoint
+		}
+		if err := mgmtClient.SetBackendTags(ctx, backend.Host, backend.Port, tags); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		logger.Info("Advertised BE public endpoint", "cluster", instance.Name, "host", backend.Host, "endpoint", endpoint)
+	}
+	return errors.Join(errs...)
+}
+
+// backendEndpoint returns the endpoint of the pod a BE is registered with, by pod name
+func backendEndpoint(host string, endpoints map[string]string) string {
+	for podName, endpoint := range endpoints {
+		if doris_client.HostOfPod(host, podName, "", "") {
+			return endpoint
+		}
+	}
+	return ""
+}
+
+// reconcileArrowFlightEndpoints annotates the BE pods with the Arrow Flight endpoint of their
+// listener Service. BE pods wait for it when they start, so it is done before they are ready.
+// A changed endpoint is only advertised once the pod restarts. It returns the sorted endpoints
+// known so far, which are published in discovery.
+func (r *DorisClusterReconciler) reconcileArrowFlightEndpoints(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) ([]string, error) {
+	if common.GetArrowFlightPort(instance, constants.ComponentTypeBE) == 0 {
+		return nil, nil
+	}
+	var endpoints []string
+	for svcName, podName := range wantedBEListeners(instance) {
+		svc := &corev1.Service{}
+		if err := r.Get(ctx, types.NamespacedName{Name: svcName, Namespace: instance.Namespace}, svc); err != nil {
+			if ctrlclient.IgnoreNotFound(err) == nil {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get BE listener Service %s: %w", svcName, err)
+		}
+		endpoint, err := r.listenerEndpoint(ctx, svc, podName, constants.ArrowFlightPortName)
+		if err != nil {
+			return nil, err
+		}
+		if endpoint == "" {
+			continue
+		}
+		endpoints = append(endpoints, endpoint)
+
+		pod := &corev1.Pod{}
+		if err := r.Get(ctx, types.NamespacedName{Name: podName, Namespace: instance.Namespace}, pod); err != nil {
+			if ctrlclient.IgnoreNotFound(err) == nil {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get BE pod %s: %w", podName, err)
+		}
+		if pod.Annotations[constants.ArrowFlightEndpointAnnotationKey] == endpoint {
+			continue
+		}
+		patch := ctrlclient.MergeFrom(pod.DeepCopy())
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[constants.ArrowFlightEndpointAnnotationKey] = endpoint
+		if err := r.Patch(ctx, pod, patch); err != nil {
+			return nil, fmt.Errorf("failed to annotate BE pod %s with its Arrow Flight endpoint: %w", podName, err)
+		}
+		logger.Info("Resolved BE Arrow Flight endpoint", "cluster", instance.Name, "pod", podName, "endpoint", endpoint)
+	}
+	slices.Sort(endpoints)
+	return endpoints, nil
+}
+
+// listenerEndpoint returns the address clients outside of Kubernetes reach a port of the pod
+// at through its listener Service, or an empty string while it is not known yet: the load
+// balancer address, or the address of the node of the pod and the node port.
+func (r *DorisClusterReconciler) listenerEndpoint(
+	ctx context.Context,
+	svc *corev1.Service,
+	podName, portName string,
+) (string, error) {
+	i := slices.IndexFunc(svc.Spec.Ports, func(p corev1.ServicePort) bool { return p.Name == portName })
+	if i < 0 {
+		return "", nil
+	}
+	port := svc.Spec.Ports[i]
+
+	switch svc.Spec.Type {
+	case corev1.ServiceTypeLoadBalancer:
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			host := ingress.IP
+			if host == "" {
+				host = ingress.Hostname
+			}
+			if host != "" {
+				return net.JoinHostPort(host, strconv.Itoa(int(port.Port))), nil
+			}
+		}
+		return "", nil
+	case corev1.ServiceTypeNodePort:
+		if port.NodePort == 0 {
+			return "", nil
+		}
+		pod := &corev1.Pod{}
+		if err := r.Get(ctx, types.NamespacedName{Name: podName, Namespace: svc.Namespace}, pod); err != nil {
+			return "", ctrlclient.IgnoreNotFound(err)
+		}
+		if pod.Spec.NodeName == "" {
+			return "", nil
+		}
+		node := &corev1.Node{}
+		if err := r.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+			return "", ctrlclient.IgnoreNotFound(err)
+		}
+		host := nodeAddress(node)
+		if host == "" {
+			return "", nil
+		}
+		return net.JoinHostPort(host, strconv.Itoa(int(port.NodePort))), nil
+	default:
+		return "", nil
+	}
+}
+
+// nodeAddress returns the external address of a node, or its internal address if it has none
+func nodeAddress(node *corev1.Node) string {
+	var internal string
+	for _, addr := range node.Status.Addresses {
+		switch addr.Type {
+		case corev1.NodeExternalIP:
+			return addr.Address
+		case corev1.NodeInternalIP:
+			if internal == "" {
+				internal = addr.Address
+			}
+		}
+	}
+	return internal
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWantedBEListeners(t *testing.T) {
+	instance := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testClusterName, Namespace: testClusterNamespace},
+		Spec: dorisv1alpha1.DorisClusterSpec{
+			Backend: &dorisv1alpha1.RoleSpec{
+				RoleGroups: map[string]dorisv1alpha1.RoleGroupSpec{
+					"default": {Replicas: ptr.To[int32](2)},
+				},
+			},
+		},
+	}
+	if got := wantedBEListeners(instance); len(got) != 0 {
+		t.Errorf("wantedBEListeners() = %v, want none for cluster-internal", got)
+	}
+
+	instance.Spec.Backend.ListenerClass = "external-unstable"
+	got := wantedBEListeners(instance)
+	want := map[string]string{
+		testClusterName + "-be-default-0-listener": testClusterName + "-be-default-0",
+		testClusterName + "-be-default-1-listener": testClusterName + "-be-default-1",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("wantedBEListeners() = %v, want %v", got, want)
+	}
+	for svc, pod := range want {
+		if got[svc] != pod {
+			t.Errorf("wantedBEListeners()[%s] = %q, want %q", svc, got[svc], pod)
+		}
+	}
+}
+
+func TestBackendEndpoint(t *testing.T) {
+	endpoints := map[string]string{
+		"doris-be-default-1":  "10.0.0.1:8040",
+		"doris-be-default-10": "10.0.0.10:8040",
+	}
+	tests := []struct {
+		host string
+		want string
+	}{
+		{host: "doris-be-default-1", want: "10.0.0.1:8040"},
+		{host: "doris-be-default-1.doris-be-default.default.svc.cluster.local", want: "10.0.0.1:8040"},
+		{host: "doris-be-default-10.doris-be-default.default.svc.cluster.local", want: "10.0.0.10:8040"},
+		{host: "doris-be-default-100.doris-be-default.default.svc.cluster.local", want: ""},
+		{host: "10.0.0.1", want: ""},
+	}
+	for _, tt := range tests {
+		if got := backendEndpoint(tt.host, endpoints); got != tt.want {
+			t.Errorf("backendEndpoint(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestListenerEndpoint(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "doris-be-default-0", Namespace: testClusterNamespace},
+		Spec:       corev1.PodSpec{NodeName: "node-1"},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: corev1.NodeStatus{Addresses: []corev1.NodeAddress{
+			{Type: corev1.NodeInternalIP, Address: "10.0.0.1"},
+			{Type: corev1.NodeExternalIP, Address: "203.0.113.1"},
+		}},
+	}
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(pod, node).Build()
+	r := &DorisClusterReconciler{Client: cli, Scheme: s}
+
+	tests := []struct {
+		name string
+		svc  *corev1.Service
+		want string
+	}{
+		{
+			name: "node port",
+			svc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testClusterNamespace},
+				Spec: corev1.ServiceSpec{
+					Type:  corev1.ServiceTypeNodePort,
+					Ports: []corev1.ServicePort{{Name: constants.BEHttpPortName, Port: 8040, NodePort: 30040}},
+				},
+			},
+			want: "203.0.113.1:30040",
+		},
+		{
+			name: "load balancer",
+			svc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testClusterNamespace},
+				Spec: corev1.ServiceSpec{
+					Type:  corev1.ServiceTypeLoadBalancer,
+					Ports: []corev1.ServicePort{{Name: constants.BEHttpPortName, Port: 8040, NodePort: 30040}},
+				},
+				Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+					Ingress: []corev1.LoadBalancerIngress{{Hostname: "be-0.example.org"}},
+				}},
+			},
+			want: "be-0.example.org:8040",
+		},
+		{
+			name: "load balancer pending",
+			svc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testClusterNamespace},
+				Spec: corev1.ServiceSpec{
+					Type:  corev1.ServiceTypeLoadBalancer,
+					Ports: []corev1.ServicePort{{Name: constants.BEHttpPortName, Port: 8040}},
+				},
+			},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.listenerEndpoint(ctx, tt.svc, pod.Name, constants.BEHttpPortName)
+			if err != nil {
+				t.Fatalf("listenerEndpoint() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("listenerEndpoint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileArrowFlightEndpoints(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = dorisv1alpha1.AddToScheme(s)
+
+	instance := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testClusterName, Namespace: testClusterNamespace},
+		Spec: dorisv1alpha1.DorisClusterSpec{
+			ClusterConfig: &dorisv1alpha1.ClusterConfigSpec{ArrowFlight: &dorisv1alpha1.ArrowFlightSpec{}},
+			Backend: &dorisv1alpha1.RoleSpec{
+				ListenerClass: "external-stable",
+				RoleGroups:    map[string]dorisv1alpha1.RoleGroupSpec{"default": {}},
+			},
+		},
+	}
+	podName := testClusterName + "-be-default-0"
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: testClusterNamespace}}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: podName + "-listener", Namespace: testClusterNamespace},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeLoadBalancer,
+			Ports: []corev1.ServicePort{
+				{Name: constants.BEHttpPortName, Port: 8040},
+				{Name: constants.ArrowFlightPortName, Port: 8050},
+			},
+		},
+		Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+			Ingress: []corev1.LoadBalancerIngress{{IP: "203.0.113.10"}},
+		}},
+	}
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(pod, svc).Build()
+	r := &DorisClusterReconciler{Client: cli, Scheme: s}
+
+	endpoints, err := r.reconcileArrowFlightEndpoints(ctx, instance)
+	if err != nil {
+		t.Fatalf("reconcileArrowFlightEndpoints() error = %v", err)
+	}
+	if len(endpoints) != 1 || endpoints[0] != "203.0.113.10:8050" {
+		t.Errorf("reconcileArrowFlightEndpoints() = %v, want [203.0.113.10:8050]", endpoints)
+	}
+	latest := &corev1.Pod{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: podName, Namespace: testClusterNamespace}, latest); err != nil {
+		t.Fatal(err)
+	}
+	if got := latest.Annotations[constants.ArrowFlightEndpointAnnotationKey]; got != "203.0.113.10:8050" {
+		t.Errorf("Arrow Flight endpoint annotation = %q, want 203.0.113.10:8050", got)
+	}
+}
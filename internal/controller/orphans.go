@@ -0,0 +1,313 @@
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultOrphanGracePeriod = 10 * time.Minute
+
+	// orphanRequeueAfter is how soon the decommission of an orphan BE is checked again
+	orphanRequeueAfter = 30 * time.Second
+
+	// orphanReasonRemoved is the reason of the Event recorded when an orphan node is removed
+	orphanReasonRemoved = "OrphanNodeRemoved"
+)
+
+// orphanNode is a node registered in Doris that no pod of the cluster backs
+type orphanNode struct {
+	status   dorisv1alpha1.OrphanNodeStatus
+	frontend doris_client.FrontendInfo
+	backend  doris_client.BackendInfo
+}
+
+// findOrphanNodes returns the FE and BE nodes listed by Doris whose host matches no FE or BE
+// pod of the cluster, sorted by component, host and port
+func findOrphanNodes(
+	pods []corev1.Pod,
+	frontends []doris_client.FrontendInfo,
+	backends []doris_client.BackendInfo,
+	dnsSuffix string,
+) []orphanNode {
+	backed := func(component constants.ComponentType, host string) bool {
+		return slices.ContainsFunc(pods, func(pod corev1.Pod) bool {
+			return pod.Labels[opgpconstants.LabelKubernetesComponent] == string(component) &&
+				doris_client.HostOfPod(host, pod.Name, pod.Status.PodIP, dnsSuffix)
+		})
+	}
+
+	var orphans []orphanNode
+	for _, fe := range frontends {
+		if backed(constants.ComponentTypeFE, fe.Host) {
+			continue
+		}
+		orphans = append(orphans, orphanNode{
+			status: dorisv1alpha1.OrphanNodeStatus{
+				Component: string(constants.ComponentTypeFE),
+				Host:      fe.Host,
+				Port:      int32(fe.EditLogPort),
+				Role:      fe.Role,
+				Alive:     fe.Alive,
+			},
+			frontend: fe,
+		})
+	}
+	for _, be := range backends {
+		if backed(constants.ComponentTypeBE, be.Host) {
+			continue
+		}
+		orphans = append(orphans, orphanNode{
+			status: dorisv1alpha1.OrphanNodeStatus{
+				Component: string(constants.ComponentTypeBE),
+				Host:      be.Host,
+				Port:      int32(be.Port),
+				Alive:     be.Alive,
+			},
+			backend: be,
+		})
+	}
+	slices.SortFunc(orphans, func(a, b orphanNode) int {
+		return cmp.Or(
+			cmp.Compare(a.status.Component, b.status.Component),
+			cmp.Compare(a.status.Host, b.status.Host),
+			cmp.Compare(a.status.Port, b.status.Port),
+		)
+	})
+	return orphans
+}
+
+// trackOrphanNodes sets since when the orphan nodes were first found, from the previous status
+func trackOrphanNodes(previous []dorisv1alpha1.OrphanNodeStatus, orphans []orphanNode, now time.Time) {
+	for i := range orphans {
+		orphans[i].status.Since = metav1.NewTime(now)
+		for _, prev := range previous {
+			if prev.Component == orphans[i].status.Component && prev.Host == orphans[i].status.Host &&
+				prev.Port == orphans[i].status.Port {
+				orphans[i].status.Since = prev.Since
+				break
+			}
+		}
+	}
+}
+
+// followerQuorumAfterDrop returns whether the alive followers left once fe is dropped are a
+// majority of the remaining followers. The master is counted as a follower.
+func followerQuorumAfterDrop(frontends []doris_client.FrontendInfo, fe doris_client.FrontendInfo) bool {
+	remaining, alive := 0, 0
+	for _, other := range frontends {
+		if other.Host == fe.Host && other.EditLogPort == fe.EditLogPort {
+			continue
+		}
+		if other.Role == "FOLLOWER" || other.IsMaster {
+			remaining++
+			if other.Alive {
+				alive++
+			}
+		}
+	}
+	return remaining > 0 && alive > remaining/2
+}
+
+// reconcileOrphanNodes lists the FE and BE nodes registered in Doris without a pod of the
+// cluster in the status, and removes them past the grace period when autoCleanup is enabled.
+func (r *DorisClusterReconciler) reconcileOrphanNodes(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (ctrl.Result, error) {
+	var spec *dorisv1alpha1.OrphanNodesSpec
+	if instance.Spec.ClusterConfig != nil {
+		spec = instance.Spec.ClusterConfig.OrphanNodes
+	}
+
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, ctrlclient.InNamespace(instance.Namespace),
+		ctrlclient.MatchingLabels{opgpconstants.LabelKubernetesInstance: instance.Name}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list pods: %w", err)
+	}
+	mgmtClient, err := r.preReadyClient(ctx, instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer r.DorisClients.Put(mgmtClient)
+	frontends, err := mgmtClient.ShowFrontends(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	backends, err := mgmtClient.ShowBackends(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	dnsSuffix := fmt.Sprintf(".%s.svc.%s", instance.Namespace, clusterDomain(instance))
+	orphans := findOrphanNodes(podList.Items, frontends, backends, dnsSuffix)
+	trackOrphanNodes(instance.Status.OrphanNodes, orphans, time.Now())
+	for _, orphan := range orphans {
+		if !slices.ContainsFunc(instance.Status.OrphanNodes, func(prev dorisv1alpha1.OrphanNodeStatus) bool {
+			return prev.Component == orphan.status.Component && prev.Host == orphan.status.Host && prev.Port == orphan.status.Port
+		}) {
+			logger.Info("Found Doris node without a pod", "cluster", instance.Name,
+				"component", orphan.status.Component, "host", orphan.status.Host, "port", orphan.status.Port)
+		}
+	}
+
+	var requeue time.Duration
+	var cleanupErr error
+	if spec != nil && spec.AutoCleanup && len(orphans) > 0 {
+		orphans, requeue, cleanupErr = r.cleanupOrphanNodes(ctx, instance, mgmtClient, spec, orphans, frontends, backends)
+	}
+
+	next := make([]dorisv1alpha1.OrphanNodeStatus, 0, len(orphans))
+	for _, orphan := range orphans {
+		next = append(next, orphan.status)
+	}
+	if len(next) == 0 {
+		next = nil
+	}
+	if !slices.EqualFunc(instance.Status.OrphanNodes, next, orphanNodeStatusEqual) {
+		if err := r.patchStatus(ctx, instance, func(status *dorisv1alpha1.DorisClusterStatus) {
+			status.OrphanNodes = next
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeue}, cleanupErr
+}
+
+// cleanupOrphanNodes removes the orphan nodes past the grace period: FE observers and
+// followers are dropped, alive BEs are decommissioned and dropped once their tablets moved,
+// and dead BEs are dropped. It returns the nodes left, with why they are kept, and when to
+// check again. The master FE, followers needed for a quorum and dead BEs holding the last
+// replica of tablets are kept, as are all nodes of a component no pod backs.
+func (r *DorisClusterReconciler) cleanupOrphanNodes(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	mgmtClient *doris_client.DorisClient,
+	spec *dorisv1alpha1.OrphanNodesSpec,
+	orphans []orphanNode,
+	frontends []doris_client.FrontendInfo,
+	backends []doris_client.BackendInfo,
+) ([]orphanNode, time.Duration, error) {
+	gracePeriod := defaultOrphanGracePeriod
+	if spec.GracePeriod != nil {
+		gracePeriod = spec.GracePeriod.Duration
+	}
+	// Nodes listed by Doris while no pod backs any node of their component are kept: the pods
+	// may be missing, or registered with hosts not recognized
+	orphanCount := map[string]int{}
+	for _, orphan := range orphans {
+		orphanCount[orphan.status.Component]++
+	}
+	total := map[string]int{
+		string(constants.ComponentTypeFE): len(frontends),
+		string(constants.ComponentTypeBE): len(backends),
+	}
+	allOrphans := func(component string) bool {
+		return orphanCount[component] == total[component]
+	}
+
+	var requeue time.Duration
+	sooner := func(d time.Duration) {
+		if requeue == 0 || d < requeue {
+			requeue = d
+		}
+	}
+	kept := make([]orphanNode, 0, len(orphans))
+	for i, orphan := range orphans {
+		if wait := gracePeriod - time.Since(orphan.status.Since.Time); wait > 0 {
+			sooner(wait)
+			kept = append(kept, orphan)
+			continue
+		}
+
+		var removed bool
+		var err error
+		switch orphan.status.Component {
+		case string(constants.ComponentTypeFE):
+			fe := orphan.frontend
+			switch {
+			case allOrphans(orphan.status.Component):
+				orphan.status.Message = "no FE pod backs any FE node"
+			case fe.IsMaster:
+				orphan.status.Message = "the master FE is never dropped"
+			case fe.Role == "FOLLOWER" && !followerQuorumAfterDrop(frontends, fe):
+				orphan.status.Message = "dropping the follower would leave the alive followers without a quorum"
+			case fe.Role == "FOLLOWER":
+				if err = mgmtClient.DropFollower(ctx, fe.Host, fe.EditLogPort); err == nil {
+					removed = true
+					frontends = slices.DeleteFunc(frontends, func(other doris_client.FrontendInfo) bool {
+						return other.Host == fe.Host && other.EditLogPort == fe.EditLogPort
+					})
+				}
+			default:
+				err = mgmtClient.DropObserver(ctx, fe.Host, fe.EditLogPort)
+				removed = err == nil
+			}
+
+		case string(constants.ComponentTypeBE):
+			be := orphan.backend
+			switch {
+			case allOrphans(orphan.status.Component):
+				orphan.status.Message = "no BE pod backs any BE node"
+			case be.Decommission && be.TabletNum == 0:
+				err = mgmtClient.DropBackend(ctx, be.Host, be.Port)
+				removed = err == nil
+			case be.Decommission:
+				orphan.status.Message = fmt.Sprintf("decommissioning, %d tablets left", be.TabletNum)
+				sooner(orphanRequeueAfter)
+			case be.Alive:
+				if err = mgmtClient.DecommissionBackend(ctx, be.Host, be.Port); err == nil {
+					logger.Info("Decommissioning Doris BE without a pod", "cluster", instance.Name, "host", be.Host)
+					orphan.status.Message = "decommissioning"
+					sooner(orphanRequeueAfter)
+				}
+			default:
+				// A tablet may have its only complete replica on the dead BE, with stale replicas elsewhere
+				var reason string
+				if reason, err = backendHoldsLastReplicas(ctx, mgmtClient, &be); err != nil {
+					break
+				}
+				if reason != "" {
+					orphan.status.Message = reason
+					sooner(orphanRequeueAfter)
+					break
+				}
+				err = mgmtClient.DropBackend(ctx, be.Host, be.Port)
+				removed = err == nil
+			}
+		}
+		if err != nil {
+			return append(kept, orphans[i:]...), requeue,
+				fmt.Errorf("failed to remove %s %s: %w", orphan.status.Component, orphan.status.Host, err)
+		}
+		if removed {
+			logger.Info("Removed Doris node without a pod", "cluster", instance.Name,
+				"component", orphan.status.Component, "host", orphan.status.Host, "port", orphan.status.Port)
+			r.recordEvent(instance, nil, corev1.EventTypeNormal, orphanReasonRemoved, "Drop",
+				"Dropped %s %s:%d, no pod of the cluster backs it", strings.ToUpper(orphan.status.Component),
+				orphan.status.Host, orphan.status.Port)
+			continue
+		}
+		kept = append(kept, orphan)
+	}
+	return kept, requeue, nil
+}
+
+// orphanNodeStatusEqual returns whether two orphan node statuses are the same
+func orphanNodeStatusEqual(a, b dorisv1alpha1.OrphanNodeStatus) bool {
+	return a.Component == b.Component && a.Host == b.Host && a.Port == b.Port && a.Role == b.Role &&
+		a.Alive == b.Alive && a.Since.Equal(&b.Since) && a.Message == b.Message
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testDNSSuffix = ".default.svc.cluster.local"
+
+func TestFindOrphanNodes(t *testing.T) {
+	pods := []corev1.Pod{
+		newTestDorisPod("test-fe-default-0", constants.ComponentTypeFE, corev1.PodRunning),
+		newTestDorisPod("test-be-default-0", constants.ComponentTypeBE, corev1.PodRunning),
+		newTestDorisPod("test-be-default-1", constants.ComponentTypeBE, corev1.PodRunning),
+	}
+	frontends := []doris_client.FrontendInfo{
+		{Host: "test-fe-default-0.test-fe-default", EditLogPort: 9010, Role: "FOLLOWER", IsMaster: true, Alive: true},
+		{Host: "test-fe-old-0.test-fe-old", EditLogPort: 9010, Role: "OBSERVER", Alive: false},
+	}
+	backends := []doris_client.BackendInfo{
+		{Host: "test-be-default-1.test-be-default", Port: 9050, Alive: true},
+		{Host: "test-be-default-0.test-be-default", Port: 9050, Alive: true},
+		{Host: "test-be-old-0.test-be-old", Port: 9050, Alive: true},
+		// A BE host names a FE pod
+		{Host: "test-fe-default-0.test-fe-default", Port: 9050, Alive: false},
+	}
+
+	orphans := findOrphanNodes(pods, frontends, backends, testDNSSuffix)
+	want := []dorisv1alpha1.OrphanNodeStatus{
+		{Component: "be", Host: "test-be-old-0.test-be-old", Port: 9050, Alive: true},
+		{Component: "be", Host: "test-fe-default-0.test-fe-default", Port: 9050},
+		{Component: "fe", Host: "test-fe-old-0.test-fe-old", Port: 9010, Role: "OBSERVER"},
+	}
+	if len(orphans) != len(want) {
+		t.Fatalf("got %d orphans, want %d: %+v", len(orphans), len(want), orphans)
+	}
+	for i := range want {
+		if !orphanNodeStatusEqual(orphans[i].status, want[i]) {
+			t.Errorf("orphan %d = %+v, want %+v", i, orphans[i].status, want[i])
+		}
+	}
+	if orphans[2].frontend.Host != "test-fe-old-0.test-fe-old" || orphans[0].backend.Host != "test-be-old-0.test-be-old" {
+		t.Errorf("orphans do not carry their Doris node: %+v", orphans)
+	}
+}
+
+func TestTrackOrphanNodes(t *testing.T) {
+	now := time.Now()
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+	previous := []dorisv1alpha1.OrphanNodeStatus{
+		{Component: "be", Host: "test-be-old-0.test-be-old", Port: 9050, Since: earlier},
+		{Component: "be", Host: "test-be-gone-0.test-be-gone", Port: 9050, Since: earlier},
+	}
+	orphans := []orphanNode{
+		{status: dorisv1alpha1.OrphanNodeStatus{Component: "be", Host: "test-be-old-0.test-be-old", Port: 9050}},
+		{status: dorisv1alpha1.OrphanNodeStatus{Component: "fe", Host: "test-be-old-0.test-be-old", Port: 9010}},
+	}
+
+	trackOrphanNodes(previous, orphans, now)
+	if !orphans[0].status.Since.Equal(&earlier) {
+		t.Errorf("known orphan since = %v, want %v", orphans[0].status.Since, earlier)
+	}
+	if !orphans[1].status.Since.Time.Equal(now) {
+		t.Errorf("new orphan since = %v, want %v", orphans[1].status.Since, now)
+	}
+}
+
+func TestFollowerQuorumAfterDrop(t *testing.T) {
+	master := doris_client.FrontendInfo{Host: "fe-0", EditLogPort: 9010, Role: "FOLLOWER", IsMaster: true, Alive: true}
+	follower := func(host string, alive bool) doris_client.FrontendInfo {
+		return doris_client.FrontendInfo{Host: host, EditLogPort: 9010, Role: "FOLLOWER", Alive: alive}
+	}
+	observer := doris_client.FrontendInfo{Host: "fe-obs", EditLogPort: 9010, Role: "OBSERVER", Alive: true}
+
+	tests := []struct {
+		name      string
+		frontends []doris_client.FrontendInfo
+		drop      doris_client.FrontendInfo
+		want      bool
+	}{
+		{
+			name:      "three followers with one orphan left by a rename",
+			frontends: []doris_client.FrontendInfo{master, follower("fe-1", true), follower("fe-old", false)},
+			drop:      follower("fe-old", false),
+			want:      true,
+		},
+		{
+			name:      "remaining followers without a majority alive",
+			frontends: []doris_client.FrontendInfo{master, follower("fe-1", false), follower("fe-old", true)},
+			drop:      follower("fe-old", true),
+			want:      false,
+		},
+		{
+			name: "two of three remaining followers alive",
+			frontends: []doris_client.FrontendInfo{
+				master, follower("fe-1", true), follower("fe-2", false), follower("fe-old", true), observer,
+			},
+			drop: follower("fe-old", true),
+			want: true,
+		},
+		{
+			name:      "observers do not count",
+			frontends: []doris_client.FrontendInfo{follower("fe-old", true), observer},
+			drop:      follower("fe-old", true),
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := followerQuorumAfterDrop(tt.frontends, tt.drop); got != tt.want {
+				t.Errorf("followerQuorumAfterDrop() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package poller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var pollerLogger = ctrl.Log.WithName("doris-state-poller")
+
+const (
+	// DefaultPollInterval is used when the cluster does not set `statePollInterval`
+	DefaultPollInterval = 30 * time.Second
+
+	// tickInterval is the resolution of the poll loop; shorter intervals are rounded up to it
+	tickInterval = 5 * time.Second
+
+	// unreachableSnapshot is the snapshot recorded when Doris cannot be queried, so that
+	// FE becoming unreachable or reachable again also triggers a reconcile
+	unreachableSnapshot = "unreachable"
+
+	// pollTimeout bounds the state query of a single cluster
+	pollTimeout = 10 * time.Second
+
+	// maxConcurrentPolls is the number of clusters queried at the same time
+	maxConcurrentPolls = 8
+)
+
+// SnapshotFunc queries Doris and returns a snapshot of the node state of the cluster.
+type SnapshotFunc func(ctx context.Context, cluster *dorisv1alpha1.DorisCluster) (string, error)
+
+// clusterState is the last observed state of a cluster
+type clusterState struct {
+	snapshot string
+	polled   bool
+	nextPoll time.Time
+}
+
+// Poller periodically polls the Doris node state of every DorisCluster and emits an
+// event on its channel when the state of a cluster changed since the previous poll.
+// It implements manager.Runnable and is fed to the controller with Source.
+type Poller struct {
+	client   ctrlclient.Client
+	snapshot SnapshotFunc
+	events   chan event.GenericEvent
+
+	mu     sync.Mutex
+	states map[types.NamespacedName]*clusterState
+}
+
+// NewPoller creates a new Poller
+func NewPoller(client ctrlclient.Client, snapshot SnapshotFunc) *Poller {
+	return &Poller{
+		client:   client,
+		snapshot: snapshot,
+		events:   make(chan event.GenericEvent, 16),
+		states:   make(map[types.NamespacedName]*clusterState),
+	}
+}
+
+// Source returns the controller source enqueuing the clusters whose state changed
+func (p *Poller) Source() source.Source {
+	return source.Channel(p.events, &handler.EnqueueRequestForObject{})
+}
+
+// NeedLeaderElection makes the poller run on the leader only
+func (p *Poller) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs the poll loop until the context is cancelled
+func (p *Poller) Start(ctx context.Context) error {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			for _, cluster := range p.Poll(ctx, now) {
+				select {
+				case p.events <- event.GenericEvent{Object: cluster}:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// Poll polls every cluster that is due and returns the clusters whose state changed.
+// The first poll of a cluster only records its state. The due clusters are collected
+// under the state lock and queried without it, concurrently and each with its own
+// timeout, so that an unreachable cluster does not hold up the others.
+func (p *Poller) Poll(ctx context.Context, now time.Time) []*dorisv1alpha1.DorisCluster {
+	clusters := &dorisv1alpha1.DorisClusterList{}
+	if err := p.client.List(ctx, clusters); err != nil {
+		pollerLogger.Error(err, "Failed to list DorisClusters")
+		return nil
+	}
+
+	due := p.dueClusters(clusters, now)
+	snapshots := make([]string, len(due))
+
+	var wg sync.WaitGroup
+	limit := make(chan struct{}, maxConcurrentPolls)
+	for i, cluster := range due {
+		wg.Add(1)
+		limit <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-limit }()
+			snapshots[i] = p.poll(ctx, cluster)
+		}()
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var changed []*dorisv1alpha1.DorisCluster
+	for i, cluster := range due {
+		key := types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}
+		state, ok := p.states[key]
+		if !ok {
+			// Deleted from the cluster list while it was polled
+			continue
+		}
+		if state.polled && state.snapshot != snapshots[i] {
+			pollerLogger.Info("Doris node state changed, enqueuing cluster", "cluster", key)
+			changed = append(changed, cluster)
+		}
+		state.snapshot = snapshots[i]
+		state.polled = true
+	}
+	return changed
+}
+
+// dueClusters returns the clusters whose next poll is due, schedules their next poll
+// and forgets the clusters that are gone or no longer polled
+func (p *Poller) dueClusters(clusters *dorisv1alpha1.DorisClusterList, now time.Time) []*dorisv1alpha1.DorisCluster {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	seen := make(map[types.NamespacedName]bool, len(clusters.Items))
+	var due []*dorisv1alpha1.DorisCluster
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		key := types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}
+		interval := PollInterval(cluster)
+		if !cluster.DeletionTimestamp.IsZero() || interval <= 0 {
+			continue
+		}
+		seen[key] = true
+
+		state, ok := p.states[key]
+		if !ok {
+			state = &clusterState{}
+			p.states[key] = state
+		} else if now.Before(state.nextPoll) {
+			continue
+		}
+		state.nextPoll = now.Add(interval)
+		due = append(due, cluster)
+	}
+
+	for key := range p.states {
+		if !seen[key] {
+			delete(p.states, key)
+		}
+	}
+	return due
+}
+
+// poll queries the state snapshot of a cluster within pollTimeout
+func (p *Poller) poll(ctx context.Context, cluster *dorisv1alpha1.DorisCluster) string {
+	ctx, cancel := context.WithTimeout(ctx, pollTimeout)
+	defer cancel()
+
+	snapshot, err := p.snapshot(ctx, cluster)
+	if err != nil {
+		pollerLogger.V(1).Info("Failed to poll Doris state",
+			"cluster", types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, "error", err)
+		return unreachableSnapshot
+	}
+	return snapshot
+}
+
+// PollInterval returns the state poll interval of the cluster
+func PollInterval(cluster *dorisv1alpha1.DorisCluster) time.Duration {
+	if cluster.Spec.ClusterConfig != nil && cluster.Spec.ClusterConfig.StatePollInterval != nil {
+		return cluster.Spec.ClusterConfig.StatePollInterval.Duration
+	}
+	return DefaultPollInterval
+}
+
+// Snapshot hashes the parts of the Doris node state that the operator acts on.
+// Counters such as the tablet number are left out so that only state transitions
+// (node alive, decommission, master switch, membership) change the snapshot.
+func Snapshot(
+	frontends []doris_client.FrontendInfo,
+	backends []doris_client.BackendInfo,
+	brokers []doris_client.BrokerInfo,
+) string {
+	entries := make([]string, 0, len(frontends)+len(backends)+len(brokers))
+	for _, fe := range frontends {
+		entries = append(entries, fmt.Sprintf("fe|%s|%s|%d|%s|%t|%t",
+			fe.Name, fe.Host, fe.EditLogPort, fe.Role, fe.IsMaster, fe.Alive))
+	}
+	for _, be := range backends {
+		entries = append(entries, fmt.Sprintf("be|%s|%d|%t|%t|%t",
+			be.Host, be.Port, be.Alive, be.Decommission, doris_client.IsDecommissionComplete(be)))
+	}
+	for _, broker := range brokers {
+		entries = append(entries, fmt.Sprintf("broker|%s|%s|%d|%t",
+			broker.Name, broker.Host, broker.Port, broker.Alive))
+	}
+	sort.Strings(entries)
+
+	sum := sha256.Sum256([]byte(strings.Join(entries, "\n")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSnapshot(t *testing.T) {
+	backends := []doris_client.BackendInfo{
+		{Host: "be-0", Port: 9050, Alive: true, TabletNum: 10},
+		{Host: "be-1", Port: 9050, Alive: true, TabletNum: 20},
+	}
+	base := Snapshot(nil, backends, nil)
+
+	// Order and tablet counters do not change the snapshot
+	reordered := []doris_client.BackendInfo{
+		{Host: "be-1", Port: 9050, Alive: true, TabletNum: 25},
+		{Host: "be-0", Port: 9050, Alive: true, TabletNum: 8},
+	}
+	if got := Snapshot(nil, reordered, nil); got != base {
+		t.Error("expected snapshot to ignore ordering and tablet counters")
+	}
+
+	// A BE going dead changes the snapshot
+	backends[1].Alive = false
+	if got := Snapshot(nil, backends, nil); got == base {
+		t.Error("expected snapshot to change when a BE goes dead")
+	}
+}
+
+func TestPoller_Poll(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = dorisv1alpha1.AddToScheme(s)
+
+	polled := &dorisv1alpha1.DorisCluster{ObjectMeta: metav1.ObjectMeta{Name: "polled", Namespace: "default"}}
+	disabled := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "disabled", Namespace: "default"},
+		Spec: dorisv1alpha1.DorisClusterSpec{
+			ClusterConfig: &dorisv1alpha1.ClusterConfigSpec{StatePollInterval: &metav1.Duration{}},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(polled, disabled).Build()
+
+	snapshot := "a"
+	var calls int
+	p := NewPoller(cli, func(_ context.Context, cluster *dorisv1alpha1.DorisCluster) (string, error) {
+		calls++
+		if cluster.Name != "polled" {
+			t.Errorf("unexpected poll of %s", cluster.Name)
+		}
+		if snapshot == "" {
+			return "", errors.New("connection refused")
+		}
+		return snapshot, nil
+	})
+
+	now := time.Now()
+	if changed := p.Poll(ctx, now); len(changed) != 0 {
+		t.Errorf("expected first poll to only record the state, got %d changes", len(changed))
+	}
+
+	// Not due yet
+	snapshot = "b"
+	if changed := p.Poll(ctx, now.Add(DefaultPollInterval/2)); len(changed) != 0 || calls != 1 {
+		t.Errorf("expected no poll before the interval elapsed, got %d changes and %d calls", len(changed), calls)
+	}
+
+	now = now.Add(DefaultPollInterval)
+	if changed := p.Poll(ctx, now); len(changed) != 1 || changed[0].Name != "polled" {
+		t.Errorf("expected polled cluster to be enqueued, got %v", changed)
+	}
+
+	// Unchanged state is not enqueued again
+	now = now.Add(DefaultPollInterval)
+	if changed := p.Poll(ctx, now); len(changed) != 0 {
+		t.Errorf("expected unchanged state not to be enqueued, got %d changes", len(changed))
+	}
+
+	// FE becoming unreachable is a change
+	snapshot = ""
+	now = now.Add(DefaultPollInterval)
+	if changed := p.Poll(ctx, now); len(changed) != 1 {
+		t.Errorf("expected unreachable FE to enqueue the cluster, got %d changes", len(changed))
+	}
+}
+
+func TestPoller_PollTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = dorisv1alpha1.AddToScheme(s)
+
+	slow := &dorisv1alpha1.DorisCluster{ObjectMeta: metav1.ObjectMeta{Name: "slow", Namespace: "default"}}
+	fast := &dorisv1alpha1.DorisCluster{ObjectMeta: metav1.ObjectMeta{Name: "fast", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(slow, fast).Build()
+
+	fastPolled := make(chan struct{})
+	p := NewPoller(cli, func(ctx context.Context, cluster *dorisv1alpha1.DorisCluster) (string, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Errorf("expected poll of %s to have a deadline", cluster.Name)
+		}
+		if cluster.Name == "fast" {
+			close(fastPolled)
+			return "a", nil
+		}
+		// The slow cluster does not hold up the fast one
+		select {
+		case <-fastPolled:
+		case <-ctx.Done():
+			t.Error("expected fast cluster to be polled while slow cluster is pending")
+		}
+		return "a", nil
+	})
+
+	if changed := p.Poll(ctx, time.Now()); len(changed) != 0 {
+		t.Errorf("expected first poll to only record the state, got %d changes", len(changed))
+	}
+	if len(p.states) != 2 {
+		t.Errorf("expected both clusters to be recorded, got %d", len(p.states))
+	}
+}
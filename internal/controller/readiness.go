@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// readinessGateConditions returns the readiness gate conditions of a FE or BE pod from the
+// nodes Doris lists. A node is matched by the exact host of the pod, so that a pod is not
+// reported serving from the node of another pod sharing its name as prefix.
+func readinessGateConditions(
+	pod *corev1.Pod,
+	frontends []doris_client.FrontendInfo,
+	backends []doris_client.BackendInfo,
+	dnsSuffix string,
+) []corev1.PodCondition {
+	registered := corev1.PodCondition{
+		Type: constants.RegisteredConditionType, Status: corev1.ConditionFalse, Reason: "NotRegistered",
+	}
+	serving := corev1.PodCondition{
+		Type: constants.ServingConditionType, Status: corev1.ConditionFalse, Reason: "NotRegistered",
+	}
+
+	switch constants.ComponentType(pod.Labels[opgpconstants.LabelKubernetesComponent]) {
+	case constants.ComponentTypeFE:
+		if i := slices.IndexFunc(frontends, func(fe doris_client.FrontendInfo) bool {
+			return doris_client.HostOfPod(fe.Host, pod.Name, pod.Status.PodIP, dnsSuffix)
+		}); i >= 0 {
+			fe := &frontends[i]
+			registered.Status, registered.Reason = corev1.ConditionTrue, "Registered"
+			switch {
+			case !fe.Alive:
+				serving.Reason = "NotAlive"
+			case !fe.Join:
+				serving.Reason = "NotJoined"
+			default:
+				serving.Status, serving.Reason = corev1.ConditionTrue, "Serving"
+			}
+		}
+	case constants.ComponentTypeBE:
+		if i := slices.IndexFunc(backends, func(be doris_client.BackendInfo) bool {
+			return doris_client.HostOfPod(be.Host, pod.Name, pod.Status.PodIP, dnsSuffix)
+		}); i >= 0 {
+			be := &backends[i]
+			registered.Status, registered.Reason = corev1.ConditionTrue, "Registered"
+			switch {
+			case !be.Alive:
+				serving.Reason = "NotAlive"
+			case be.Decommission:
+				serving.Reason = "Decommissioning"
+			default:
+				serving.Status, serving.Reason = corev1.ConditionTrue, "Serving"
+			}
+		}
+	}
+	return []corev1.PodCondition{registered, serving}
+}
+
+// hasReadinessGates returns whether the pod waits for the Doris readiness gates, pods
+// created before they were added do not
+func hasReadinessGates(pod *corev1.Pod) bool {
+	return slices.ContainsFunc(pod.Spec.ReadinessGates, func(gate corev1.PodReadinessGate) bool {
+		return gate.ConditionType == constants.ServingConditionType
+	})
+}
+
+// reconcileReadinessGates sets the readiness gate conditions of the FE and BE pods. It runs
+// before the cluster is ready, since the pods are not ready until their conditions are set;
+// the conditions are left unchanged while Doris is not reachable.
+func (r *DorisClusterReconciler) reconcileReadinessGates(ctx context.Context, instance *dorisv1alpha1.DorisCluster) error {
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, ctrlclient.InNamespace(instance.Namespace),
+		ctrlclient.MatchingLabels{opgpconstants.LabelKubernetesInstance: instance.Name}); err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+	pods := slices.DeleteFunc(podList.Items, func(pod corev1.Pod) bool {
+		return !hasReadinessGates(&pod) || !pod.DeletionTimestamp.IsZero()
+	})
+	if len(pods) == 0 {
+		return nil
+	}
+
+	mgmtClient, err := r.preReadyClient(ctx, instance)
+	if err != nil {
+		return err
+	}
+	defer r.DorisClients.Put(mgmtClient)
+	frontends, err := mgmtClient.ShowFrontends(ctx)
+	if err != nil {
+		return err
+	}
+	backends, err := mgmtClient.ShowBackends(ctx)
+	if err != nil {
+		return err
+	}
+
+	dnsSuffix := fmt.Sprintf(".%s.svc.%s", instance.Namespace, clusterDomain(instance))
+	var errs []error
+	for i := range pods {
+		conditions := readinessGateConditions(&pods[i], frontends, backends, dnsSuffix)
+		if err := r.setPodConditions(ctx, &pods[i], conditions); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// preReadyClient connects to Doris FE before the cluster is ready: with the management
+// credentials, or as root until the auth bootstrap completed
+func (r *DorisClusterReconciler) preReadyClient(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (*doris_client.DorisClient, error) {
+	if err := r.ensureRootPasswordSecret(ctx, instance); err != nil {
+		return nil, err
+	}
+	target, err := r.clusterTarget(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+	if instance.Status.RootPasswordInitialized && (instance.Spec.AuthSecret == nil || instance.Status.AuthInitialized) {
+		return r.DorisClients.Get(ctx, target)
+	}
+	rootTarget, err := r.rootClusterTarget(ctx, instance, target)
+	if err != nil {
+		return nil, err
+	}
+	mgmtClient, _, err := r.connectRoot(ctx, instance, rootTarget)
+	return mgmtClient, err
+}
+
+// setPodConditions patches the status of a pod with the conditions that changed
+func (r *DorisClusterReconciler) setPodConditions(ctx context.Context, pod *corev1.Pod, conditions []corev1.PodCondition) error {
+	patch := ctrlclient.StrategicMergeFrom(pod.DeepCopy())
+	changed := false
+	for _, condition := range conditions {
+		i := slices.IndexFunc(pod.Status.Conditions, func(c corev1.PodCondition) bool { return c.Type == condition.Type })
+		if i >= 0 && pod.Status.Conditions[i].Status == condition.Status && pod.Status.Conditions[i].Reason == condition.Reason {
+			continue
+		}
+		changed = true
+		condition.LastTransitionTime = metav1.Now()
+		if i < 0 {
+			pod.Status.Conditions = append(pod.Status.Conditions, condition)
+			continue
+		}
+		if pod.Status.Conditions[i].Status == condition.Status {
+			condition.LastTransitionTime = pod.Status.Conditions[i].LastTransitionTime
+		}
+		pod.Status.Conditions[i] = condition
+	}
+	if !changed {
+		return nil
+	}
+	if err := r.Status().Patch(ctx, pod, patch); err != nil {
+		return fmt.Errorf("failed to set readiness gate conditions of pod %s: %w", pod.Name, err)
+	}
+	logger.V(1).Info("Set readiness gate conditions", "pod", pod.Name, "conditions", conditions)
+	return nil
+}
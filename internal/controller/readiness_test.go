@@ -0,0 +1,120 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReadinessGateConditions(t *testing.T) {
+	frontends := []doris_client.FrontendInfo{
+		{Host: "test-fe-default-0.test-fe-default.default.svc.cluster.local", Alive: true, Join: true},
+		{Host: "test-fe-default-1.test-fe-default.default.svc.cluster.local", Alive: true},
+	}
+	// The BE of test-be-default-10 is listed first, its host starts with test-be-default-1
+	backends := []doris_client.BackendInfo{
+		{Host: "test-be-default-10.test-be-default.default.svc.cluster.local", Alive: true},
+		{Host: "test-be-default-0.test-be-default.default.svc.cluster.local", Alive: true},
+		{Host: "test-be-default-1.test-be-default.default.svc.cluster.local", Alive: true, Decommission: true},
+		{Host: "test-be-default-2.test-be-default.default.svc.cluster.local"},
+	}
+
+	tests := []struct {
+		pod            string
+		component      constants.ComponentType
+		wantRegistered corev1.ConditionStatus
+		wantServing    corev1.ConditionStatus
+		wantReason     string
+	}{
+		{"test-fe-default-0", constants.ComponentTypeFE, corev1.ConditionTrue, corev1.ConditionTrue, "Serving"},
+		{"test-fe-default-1", constants.ComponentTypeFE, corev1.ConditionTrue, corev1.ConditionFalse, "NotJoined"},
+		{"test-fe-default-2", constants.ComponentTypeFE, corev1.ConditionFalse, corev1.ConditionFalse, "NotRegistered"},
+		{"test-be-default-0", constants.ComponentTypeBE, corev1.ConditionTrue, corev1.ConditionTrue, "Serving"},
+		{"test-be-default-1", constants.ComponentTypeBE, corev1.ConditionTrue, corev1.ConditionFalse, "Decommissioning"},
+		{"test-be-default-2", constants.ComponentTypeBE, corev1.ConditionTrue, corev1.ConditionFalse, "NotAlive"},
+		{"test-be-default-3", constants.ComponentTypeBE, corev1.ConditionFalse, corev1.ConditionFalse, "NotRegistered"},
+		{"test-be-default-10", constants.ComponentTypeBE, corev1.ConditionTrue, corev1.ConditionTrue, "Serving"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pod, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:   tt.pod,
+				Labels: map[string]string{opgpconstants.LabelKubernetesComponent: string(tt.component)},
+			}}
+			conditions := readinessGateConditions(pod, frontends, backends, ".default.svc.cluster.local")
+			if conditions[0].Status != tt.wantRegistered {
+				t.Errorf("registered = %s, want %s", conditions[0].Status, tt.wantRegistered)
+			}
+			if conditions[1].Status != tt.wantServing || conditions[1].Reason != tt.wantReason {
+				t.Errorf("serving = %s/%s, want %s/%s", conditions[1].Status, conditions[1].Reason, tt.wantServing, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestSetPodConditions(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-be-default-0", Namespace: testClusterNamespace},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+			{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+		}},
+	}
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(pod).WithStatusSubresource(pod).Build()
+	r := &DorisClusterReconciler{Client: cli, Scheme: s}
+
+	conditions := []corev1.PodCondition{
+		{Type: constants.RegisteredConditionType, Status: corev1.ConditionTrue, Reason: "Registered"},
+		{Type: constants.ServingConditionType, Status: corev1.ConditionFalse, Reason: "Decommissioning"},
+	}
+	if err := r.setPodConditions(ctx, pod, conditions); err != nil {
+		t.Fatalf("setPodConditions() error = %v", err)
+	}
+
+	got := &corev1.Pod{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[corev1.PodConditionType]corev1.ConditionStatus{
+		corev1.ContainersReady:            corev1.ConditionTrue,
+		constants.RegisteredConditionType: corev1.ConditionTrue,
+		constants.ServingConditionType:    corev1.ConditionFalse,
+	}
+	if len(got.Status.Conditions) != len(want) {
+		t.Fatalf("conditions = %v, want %v", got.Status.Conditions, want)
+	}
+	for _, c := range got.Status.Conditions {
+		if want[c.Type] != c.Status {
+			t.Errorf("condition %s = %s, want %s", c.Type, c.Status, want[c.Type])
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// rollingRestartFrontendConfig is the FE config set while BE pods restart: no balancing, and
+// the repair of the replicas of restarting BEs delayed long enough for them to come back
+var rollingRestartFrontendConfig = map[string]string{
+	"disable_balance":                   "true",
+	"tablet_repair_delay_factor_second": "600",
+}
+
+const (
+	// rollingRestartRequeueAfter is how often the tablets are checked once the BEs restarted
+	rollingRestartRequeueAfter = 30 * time.Second
+
+	// rollingRestartTimeout bounds how long the FE config stays changed, a stuck rollout or
+	// tablets unhealthy for other reasons would otherwise keep balancing disabled
+	rollingRestartTimeout = 2 * time.Hour
+)
+
+// beRollingRestart returns whether a BE StatefulSet is rolling out a new pod template
+func (r *DorisClusterReconciler) beRollingRestart(ctx context.Context, instance *dorisv1alpha1.DorisCluster) (bool, error) {
+	stsList := &appsv1.StatefulSetList{}
+	if err := r.List(ctx, stsList, ctrlclient.InNamespace(instance.Namespace), ctrlclient.MatchingLabels{
+		opgpconstants.LabelKubernetesInstance:  instance.Name,
+		opgpconstants.LabelKubernetesComponent: string(constants.ComponentTypeBE),
+	}); err != nil {
+		return false, fmt.Errorf("failed to list BE StatefulSets: %w", err)
+	}
+	for _, sts := range stsList.Items {
+		if sts.Status.UpdateRevision != "" && sts.Status.CurrentRevision != sts.Status.UpdateRevision {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// reconcileRollingRestart pauses the tablet balancing and delays the tablet repair while BE
+// pods restart, and restores the FE config once the BEs rejoined and the tablets are healthy.
+// The previous FE config is recorded in the status before it is changed, so that it is
+// restored after an operator restart as well. After rollingRestartTimeout the FE config is
+// restored even if the rollout is stuck, e.g. on a BE crash-looping on a bad image.
+func (r *DorisClusterReconciler) reconcileRollingRestart(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (ctrl.Result, error) {
+	state := instance.Status.RollingRestart
+	if instance.Spec.Backend == nil && state == nil {
+		return ctrl.Result{}, nil
+	}
+	restarting, err := r.beRollingRestart(ctx, instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !restarting && state == nil {
+		return ctrl.Result{}, nil
+	}
+
+	mgmtClient, err := r.preReadyClient(ctx, instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer r.DorisClients.Put(mgmtClient)
+
+	expired := state != nil && state.StartTime != nil && time.Since(state.StartTime.Time) >= rollingRestartTimeout
+	if restarting && !expired {
+		if state == nil {
+			saved := map[string]string{}
+			for _, key := range slices.Sorted(maps.Keys(rollingRestartFrontendConfig)) {
+				value, err := mgmtClient.GetFrontendConfig(ctx, key)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+				saved[key] = value
+			}
+			state = &dorisv1alpha1.RollingRestartStatus{StartTime: ptr.To(metav1.Now()), FrontendConfig: saved}
+			if err := r.patchStatus(ctx, instance, func(status *dorisv1alpha1.DorisClusterStatus) {
+				status.RollingRestart = state
+			}); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to record the FE config before BE pods restart: %w", err)
+			}
+			logger.Info("Pausing tablet balancing and repair while BE pods restart", "cluster", instance.Name)
+		}
+		// Applied on every reconcile, restarted FEs lose it
+		for _, key := range slices.Sorted(maps.Keys(rollingRestartFrontendConfig)) {
+			if err := mgmtClient.SetAllFrontendsConfig(ctx, key, rollingRestartFrontendConfig[key]); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		// Checked again at the timeout, a stuck rollout may not trigger any reconcile
+		if state.StartTime == nil {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: rollingRestartTimeout - time.Since(state.StartTime.Time)}, nil
+	}
+
+	if !expired {
+		backends, err := mgmtClient.ShowBackends(ctx)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		for _, backend := range backends {
+			if !backend.Alive && !backend.Decommission {
+				logger.Info("Waiting for BE to rejoin after restart", "cluster", instance.Name, "host", backend.Host)
+				return ctrl.Result{RequeueAfter: rollingRestartRequeueAfter}, nil
+			}
+		}
+		health, err := mgmtClient.GetTabletHealth(ctx)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !health.IsHealthy() {
+			logger.Info("Waiting for tablets to be healthy after BE restart", "cluster", instance.Name,
+				"tablets", health.TabletNum, "healthy", health.HealthyNum)
+			return ctrl.Result{RequeueAfter: rollingRestartRequeueAfter}, nil
+		}
+	} else if restarting {
+		logger.Info("BE pods not restarted in time, restoring the FE config while the rollout goes on",
+			"cluster", instance.Name, "timeout", rollingRestartTimeout)
+	} else {
+		logger.Info("Tablets not healthy in time after BE restart, restoring the FE config anyway",
+			"cluster", instance.Name, "timeout", rollingRestartTimeout)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(state.FrontendConfig)) {
+		if err := mgmtClient.SetAllFrontendsConfig(ctx, key, state.FrontendConfig[key]); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	// The status is kept until the rollout ends, so that the FE config is not changed again
+	if restarting {
+		return ctrl.Result{}, nil
+	}
+	if err := r.patchStatus(ctx, instance, func(status *dorisv1alpha1.DorisClusterStatus) {
+		status.RollingRestart = nil
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	logger.Info("Restored tablet balancing and repair after BE pods restarted", "cluster", instance.Name)
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBeRollingRestart(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = dorisv1alpha1.AddToScheme(s)
+
+	instance := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testClusterName, Namespace: testClusterNamespace},
+		Spec:       dorisv1alpha1.DorisClusterSpec{Backend: &dorisv1alpha1.RoleSpec{}},
+	}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testClusterName + "-be-default",
+			Namespace: testClusterNamespace,
+			Labels: map[string]string{
+				opgpconstants.LabelKubernetesInstance:  testClusterName,
+				opgpconstants.LabelKubernetesComponent: string(constants.ComponentTypeBE),
+			},
+		},
+		Status: appsv1.StatefulSetStatus{CurrentRevision: "rev-1", UpdateRevision: "rev-1"},
+	}
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(instance, sts).WithStatusSubresource(sts).Build()
+	r := &DorisClusterReconciler{Client: cli, Scheme: s}
+
+	if restarting, err := r.beRollingRestart(ctx, instance); err != nil || restarting {
+		t.Fatalf("beRollingRestart() = %v, %v, want false", restarting, err)
+	}
+	// Nothing to pause or restore, Doris is not reached
+	if result, err := r.reconcileRollingRestart(ctx, instance); err != nil || !result.IsZero() {
+		t.Fatalf("reconcileRollingRestart() = %v, %v", result, err)
+	}
+
+	sts.Status.UpdateRevision = "rev-2"
+	if err := cli.Status().Update(ctx, sts); err != nil {
+		t.Fatal(err)
+	}
+	if restarting, err := r.beRollingRestart(ctx, instance); err != nil || !restarting {
+		t.Fatalf("beRollingRestart() = %v, %v, want true", restarting, err)
+	}
+}
@@ -104,6 +104,13 @@ func (m *BEScaleManager) ScaleDown(ctx context.Context, action ScaleAction, poli
 						"pod", podName, "host", be.Host, "tabletNum", be.TabletNum)
 				}
 			} else {
+				if policy != nil {
+					if reason := policy.DecommissionBlocked(); reason != "" {
+						beScaleLogger.Info("Waiting to start BE decommission",
+							"pod", podName, "host", be.Host, "reason", reason)
+						continue
+					}
+				}
 				// Start decommission and record start time
 				beScaleLogger.Info("Starting BE decommission",
 					"pod", podName, "host", be.Host, "port", be.Port)
@@ -163,6 +170,14 @@ func (m *BEScaleManager) GetBENodeStatuses(ctx context.Context, podNames []strin
 			status.Alive = be.Alive
 			status.Decommission = be.Decommission
 			status.TabletNum = be.TabletNum
+			status.Version = be.Version
+			status.LastStartTime = be.LastStartTime
+			status.HeartbeatError = be.ErrMsg
+			status.CPUCores = be.CPUCores
+			status.DataUsedBytes = be.DataUsedBytes
+			status.DiskUsedBytes = max(0, be.TotalBytes-be.AvailBytes)
+			status.DiskTotalBytes = be.TotalBytes
+			status.Tags = be.Tags
 		} else {
 			status.Alive = false
 		}
@@ -178,4 +193,13 @@ type BENodeStatus struct {
 	Alive        bool
 	Decommission bool
 	TabletNum    int
+
+	Version        string
+	LastStartTime  string
+	HeartbeatError string
+	CPUCores       int
+	DataUsedBytes  int64
+	DiskUsedBytes  int64
+	DiskTotalBytes int64
+	Tags           map[string]string
 }
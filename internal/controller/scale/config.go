@@ -10,6 +10,9 @@ type ScaleDownPolicy interface {
 	// GetDecommissionTimeout returns the maximum duration to wait for BE decommission.
 	// After this timeout, the operator will force-drop the node.
 	GetDecommissionTimeout() time.Duration
+	// DecommissionBlocked returns why new BE decommissions must wait, or an empty string.
+	// Decommissions already in progress are not affected.
+	DecommissionBlocked() string
 }
 
 // DecommissionTracker manages BE decommission lifecycle state.
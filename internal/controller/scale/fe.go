@@ -87,6 +87,10 @@ func (m *FEScaleManager) GetFENodeStatuses(ctx context.Context, podNames []strin
 			status.Role = fe.Role
 			status.IsMaster = fe.IsMaster
 			status.Alive = fe.Alive
+			status.Version = fe.Version
+			status.LastStartTime = fe.LastStartTime
+			status.HeartbeatError = fe.ErrMsg
+			status.ReplayedJournalID = fe.ReplayedJournalID
 		} else {
 			status.Alive = false
 		}
@@ -102,4 +106,9 @@ type FENodeStatus struct {
 	Role     string // FOLLOWER, OBSERVER, MASTER
 	IsMaster bool
 	Alive    bool
+
+	Version           string
+	LastStartTime     string
+	HeartbeatError    string
+	ReplayedJournalID int
 }
@@ -3,7 +3,6 @@ package scale
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
@@ -143,10 +142,11 @@ func buildBrokerNodeStatuses(podNames []string, brokers []doris_client.BrokerInf
 	for _, podName := range podNames {
 		s := BrokerNodeStatus{PodName: podName}
 		for _, bi := range brokers {
-			// Match by substring or exact hostname
-			if len(bi.Host) > 0 && (strings.Contains(bi.Host, podName) || bi.Host == podName) {
+			if doris_client.HostOfPod(bi.Host, podName, "", "") {
 				s.Host = bi.Host
 				s.Alive = bi.Alive
+				s.LastStartTime = bi.LastStartTime
+				s.HeartbeatError = bi.ErrMsg
 				break
 			}
 		}
@@ -157,14 +157,9 @@ func buildBrokerNodeStatuses(podNames []string, brokers []doris_client.BrokerInf
 
 // BrokerNodeStatus represents the scale-relevant status of a Broker pod
 type BrokerNodeStatus struct {
-	PodName string
-	Host    string
-	Alive   bool
-}
-
-// Close closes the underlying Doris client connection
-func (m *ScaleManager) Close() {
-	if m.dorisClient != nil {
-		_ = m.dorisClient.Close()
-	}
+	PodName        string
+	Host           string
+	Alive          bool
+	LastStartTime  string
+	HeartbeatError string
 }
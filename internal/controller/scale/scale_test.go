@@ -21,6 +21,7 @@ import (
 
 	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
 	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
 )
 
 func intPtr(v int32) *int32 { return &v }
@@ -576,3 +577,57 @@ func TestUpdateClusterStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateClusterStatus_NodeDetails(t *testing.T) {
+	var status dorisv1alpha1.DorisClusterStatus
+	UpdateClusterStatus(&status,
+		[]BENodeStatus{
+			{
+				PodName: testBEPod0, Host: testBEPod0, Alive: true, Version: "doris-2.1.7", CPUCores: 8,
+				DataUsedBytes: 1 << 30, DiskUsedBytes: 3 << 30, DiskTotalBytes: 10 << 30,
+				HeartbeatError: "connection refused", Tags: map[string]string{"location": "default"},
+			},
+			{PodName: testBEPod1},
+		},
+		[]FENodeStatus{{PodName: testFEPod0, Host: testFEPod0, Alive: true, Version: "doris-2.1.7", ReplayedJournalID: 1234}},
+		[]BrokerNodeStatus{{PodName: testBrokerPod0, LastStartTime: "2026-05-19 10:00:00"}},
+	)
+
+	be := status.BackendNodes[0]
+	if be.Version != "doris-2.1.7" || be.CPUCores != 8 || be.HeartbeatError != "connection refused" ||
+		be.Tags["location"] != "default" {
+		t.Errorf("BE node = %+v", be)
+	}
+	if be.DataUsed.String() != "1Gi" || be.DiskUsed.String() != "3Gi" || be.DiskTotal.String() != "10Gi" {
+		t.Errorf("BE capacities = %s/%s/%s, want 1Gi/3Gi/10Gi", be.DataUsed, be.DiskUsed, be.DiskTotal)
+	}
+	if unregistered := status.BackendNodes[1]; unregistered.DataUsed != nil || unregistered.DiskTotal != nil {
+		t.Errorf("expected no capacities for a BE not registered, got %+v", unregistered)
+	}
+	if fe := status.FrontendNodes[0]; fe.Version != "doris-2.1.7" || fe.ReplayedJournalID != 1234 {
+		t.Errorf("FE node = %+v", fe)
+	}
+	if broker := status.BrokerNodes[0]; broker.LastStartTime != "2026-05-19 10:00:00" {
+		t.Errorf("broker node = %+v", broker)
+	}
+}
+
+func TestBuildBrokerNodeStatuses(t *testing.T) {
+	// broker-10 is listed first, broker-1 must not take its status
+	brokers := []doris_client.BrokerInfo{
+		{Host: "broker-10.broker", Alive: false, LastStartTime: "2026-05-19 11:00:00", ErrMsg: "connection refused"},
+		{Host: "broker-1.broker", Alive: true, LastStartTime: "2026-05-19 10:00:00"},
+	}
+	statuses := buildBrokerNodeStatuses([]string{"broker-1", "broker-10", "broker-2"}, brokers)
+
+	if s := statuses[0]; s.Host != "broker-1.broker" || !s.Alive || s.HeartbeatError != "" ||
+		s.LastStartTime != "2026-05-19 10:00:00" {
+		t.Errorf("broker-1 status = %+v", s)
+	}
+	if s := statuses[1]; s.Host != "broker-10.broker" || s.Alive || s.HeartbeatError != "connection refused" {
+		t.Errorf("broker-10 status = %+v", s)
+	}
+	if s := statuses[2]; s.Host != "" || s.Alive {
+		t.Errorf("expected broker-2 not to be matched, got %+v", s)
+	}
+}
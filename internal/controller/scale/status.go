@@ -2,8 +2,17 @@ package scale
 
 import (
 	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
+// bytesQuantity returns a capacity reported by Doris as a quantity, nil when unknown
+func bytesQuantity(bytes int64) *resource.Quantity {
+	if bytes <= 0 {
+		return nil
+	}
+	return resource.NewQuantity(bytes, resource.BinarySI)
+}
+
 // UpdateClusterStatus updates the DorisCluster CR status with node information
 // from the scale reconciliation result.
 // It only updates fields when fresh data was successfully fetched (non-nil slices).
@@ -26,10 +35,18 @@ func UpdateClusterStatus(
 				phase = "Decommissioning"
 			}
 			clusterStatus.BackendNodes[i] = dorisv1alpha1.NodeStatus{
-				Name:  be.PodName,
-				Host:  be.Host,
-				Alive: be.Alive,
-				Phase: phase,
+				Name:           be.PodName,
+				Host:           be.Host,
+				Alive:          be.Alive,
+				Phase:          phase,
+				Version:        be.Version,
+				LastStartTime:  be.LastStartTime,
+				HeartbeatError: be.HeartbeatError,
+				CPUCores:       int32(be.CPUCores),
+				DataUsed:       bytesQuantity(be.DataUsedBytes),
+				DiskUsed:       bytesQuantity(be.DiskUsedBytes),
+				DiskTotal:      bytesQuantity(be.DiskTotalBytes),
+				Tags:           be.Tags,
 			}
 		}
 	}
@@ -39,10 +56,14 @@ func UpdateClusterStatus(
 		clusterStatus.FrontendNodes = make([]dorisv1alpha1.NodeStatus, len(feStatuses))
 		for i, fe := range feStatuses {
 			clusterStatus.FrontendNodes[i] = dorisv1alpha1.NodeStatus{
-				Name:  fe.PodName,
-				Host:  fe.Host,
-				Role:  fe.Role,
-				Alive: fe.Alive,
+				Name:              fe.PodName,
+				Host:              fe.Host,
+				Role:              fe.Role,
+				Alive:             fe.Alive,
+				Version:           fe.Version,
+				LastStartTime:     fe.LastStartTime,
+				HeartbeatError:    fe.HeartbeatError,
+				ReplayedJournalID: int64(fe.ReplayedJournalID),
 			}
 		}
 	}
@@ -52,9 +73,11 @@ func UpdateClusterStatus(
 		clusterStatus.BrokerNodes = make([]dorisv1alpha1.NodeStatus, len(brokerStatuses))
 		for i, b := range brokerStatuses {
 			clusterStatus.BrokerNodes[i] = dorisv1alpha1.NodeStatus{
-				Name:  b.PodName,
-				Host:  b.Host,
-				Alive: b.Alive,
+				Name:           b.PodName,
+				Host:           b.Host,
+				Alive:          b.Alive,
+				LastStartTime:  b.LastStartTime,
+				HeartbeatError: b.HeartbeatError,
 			}
 		}
 	}
@@ -0,0 +1,397 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultSelfHealingGracePeriod       = 10 * time.Minute
+	defaultSelfHealingMinActionInterval = 30 * time.Minute
+
+	// selfHealingRequeueAfter is how soon a repaired node is checked again
+	selfHealingRequeueAfter = 30 * time.Second
+
+	// maxCheckedTablets caps the unhealthy tablets checked before the storage of a BE is replaced
+	maxCheckedTablets = 1000
+)
+
+// Reasons of the self-healing Events
+const (
+	selfHealingReasonRestart        = "SelfHealingRestart"
+	selfHealingReasonReplaceStorage = "SelfHealingReplaceStorage"
+)
+
+// healAction is how a dead node is repaired
+type healAction string
+
+const (
+	// healRestart deletes the pod, the StatefulSet recreates it
+	healRestart healAction = "Restart"
+	// healReplaceStorage drops the BE from Doris and deletes its PVCs and its pod, the BE
+	// registers again with an empty disk
+	healReplaceStorage healAction = "ReplaceStorage"
+)
+
+// deadNode is a node Doris reports dead, with how to repair it
+type deadNode struct {
+	pod     *corev1.Pod
+	action  healAction
+	message string
+	// backend is the BE to drop when its storage is replaced
+	backend *doris_client.BackendInfo
+}
+
+// findDeadNodes returns the FE and BE pods whose node Doris reports dead. Running pods are
+// restarted, and BEs whose storage is lost are replaced. Nodes not registered and BEs being
+// decommissioned are left to the scale management.
+func findDeadNodes(
+	pods []corev1.Pod,
+	frontends []doris_client.FrontendInfo,
+	backends []doris_client.BackendInfo,
+	lostStorage map[string]string,
+) []deadNode {
+	var dead []deadNode
+	for i := range pods {
+		pod := &pods[i]
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		running := pod.Status.Phase == corev1.PodRunning
+
+		switch constants.ComponentType(pod.Labels[opgpconstants.LabelKubernetesComponent]) {
+		case constants.ComponentTypeFE:
+			fe := doris_client.MatchPodToFrontend(pod.Name, frontends)
+			if fe == nil || (fe.Alive && fe.ErrMsg == "") || !running {
+				continue
+			}
+			dead = append(dead, deadNode{pod: pod, action: healRestart, message: nodeDeadMessage("FE", fe.Alive, fe.ErrMsg)})
+		case constants.ComponentTypeBE:
+			be := doris_client.MatchPodToBackend(pod.Name, backends)
+			if be == nil || be.Decommission || (be.Alive && be.ErrMsg == "") {
+				continue
+			}
+			if lost, ok := lostStorage[pod.Name]; ok && !be.Alive {
+				dead = append(dead, deadNode{pod: pod, action: healReplaceStorage, message: "BE is not alive and " + lost, backend: be})
+				continue
+			}
+			if running {
+				dead = append(dead, deadNode{pod: pod, action: healRestart, message: nodeDeadMessage("BE", be.Alive, be.ErrMsg)})
+			}
+		}
+	}
+	return dead
+}
+
+// nodeDeadMessage describes why Doris reports a node dead
+func nodeDeadMessage(role string, alive bool, errMsg string) string {
+	message := role + " heartbeat failing"
+	if !alive {
+		message = role + " is not alive"
+	}
+	if errMsg != "" {
+		message += ": " + errMsg
+	}
+	return message
+}
+
+// planSelfHealing tracks since when the nodes are dead and returns the node to repair, if
+// its grace period elapsed and no action was taken within the min action interval, with
+// when to check again.
+func planSelfHealing(
+	status *dorisv1alpha1.SelfHealingStatus,
+	dead []deadNode,
+	spec *dorisv1alpha1.SelfHealingSpec,
+	now time.Time,
+) (*dorisv1alpha1.SelfHealingStatus, *deadNode, time.Duration) {
+	gracePeriod, minActionInterval := defaultSelfHealingGracePeriod, defaultSelfHealingMinActionInterval
+	if spec.GracePeriod != nil {
+		gracePeriod = spec.GracePeriod.Duration
+	}
+	if spec.MinActionInterval != nil {
+		minActionInterval = spec.MinActionInterval.Duration
+	}
+
+	next := &dorisv1alpha1.SelfHealingStatus{DeadSince: map[string]metav1.Time{}}
+	if status != nil {
+		next.LastActionTime = status.LastActionTime
+	}
+	var due *deadNode
+	var dueSince metav1.Time
+	var requeue time.Duration
+	sooner := func(d time.Duration) {
+		if requeue == 0 || d < requeue {
+			requeue = d
+		}
+	}
+	for i := range dead {
+		since := metav1.NewTime(now)
+		if status != nil {
+			if t, ok := status.DeadSince[dead[i].pod.Name]; ok {
+				since = t
+			}
+		}
+		next.DeadSince[dead[i].pod.Name] = since
+		if wait := gracePeriod - now.Sub(since.Time); wait > 0 {
+			sooner(wait)
+		} else if due == nil || since.Before(&dueSince) {
+			due, dueSince = &dead[i], since
+		}
+	}
+	if due != nil && next.LastActionTime != nil {
+		if wait := minActionInterval - now.Sub(next.LastActionTime.Time); wait > 0 {
+			sooner(wait)
+			due = nil
+		}
+	}
+	return next, due, requeue
+}
+
+// reconcileSelfHealing restarts the FE and BE pods Doris reports dead past the grace period,
+// and replaces the storage of lost BEs. It runs before the cluster is ready, since dead nodes
+// keep it from getting ready.
+func (r *DorisClusterReconciler) reconcileSelfHealing(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (ctrl.Result, error) {
+	var spec *dorisv1alpha1.SelfHealingSpec
+	if instance.Spec.ClusterConfig != nil {
+		spec = instance.Spec.ClusterConfig.SelfHealing
+	}
+	if spec == nil || !spec.Enabled {
+		if instance.Status.SelfHealing == nil {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, r.patchStatus(ctx, instance, func(status *dorisv1alpha1.DorisClusterStatus) {
+			status.SelfHealing = nil
+		})
+	}
+	// BEs restarting for a rollout or being decommissioned are expected to be dead
+	if instance.Status.RollingRestart != nil || len(newDecommissionTracker(instance, r.Client).PendingPods()) > 0 {
+		return ctrl.Result{}, nil
+	}
+
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, ctrlclient.InNamespace(instance.Namespace),
+		ctrlclient.MatchingLabels{opgpconstants.LabelKubernetesInstance: instance.Name}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list pods: %w", err)
+	}
+	lostStorage := map[string]string{}
+	if spec.ReplaceLostBackends {
+		var err error
+		if lostStorage, err = r.findLostStorage(ctx, podList.Items); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	mgmtClient, err := r.preReadyClient(ctx, instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer r.DorisClients.Put(mgmtClient)
+	frontends, err := mgmtClient.ShowFrontends(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	backends, err := mgmtClient.ShowBackends(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	dead := findDeadNodes(podList.Items, frontends, backends, lostStorage)
+	next, due, requeue := planSelfHealing(instance.Status.SelfHealing, dead, spec, time.Now())
+	var healErr error
+	if due != nil {
+		acted, err := r.healNode(ctx, instance, mgmtClient, due)
+		if acted {
+			next.LastActionTime = ptr.To(metav1.Now())
+			delete(next.DeadSince, due.pod.Name)
+		}
+		healErr = err
+		requeue = selfHealingRequeueAfter
+	}
+
+	if len(next.DeadSince) == 0 && next.LastActionTime == nil {
+		next = nil
+	}
+	if !selfHealingStatusEqual(instance.Status.SelfHealing, next) {
+		if err := r.patchStatus(ctx, instance, func(status *dorisv1alpha1.DorisClusterStatus) {
+			status.SelfHealing = next
+		}); err != nil {
+			return ctrl.Result{}, errors.Join(healErr, err)
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeue}, healErr
+}
+
+// findLostStorage returns the BE pods whose storage is lost, with why: a PVC deleted or lost,
+// or the pod annotated as having a corrupt disk
+func (r *DorisClusterReconciler) findLostStorage(ctx context.Context, pods []corev1.Pod) (map[string]string, error) {
+	lost := map[string]string{}
+	for _, pod := range pods {
+		if pod.Labels[opgpconstants.LabelKubernetesComponent] != string(constants.ComponentTypeBE) {
+			continue
+		}
+		if pod.Annotations[constants.ReplaceStorageAnnotationKey] == "true" {
+			lost[pod.Name] = "its storage is marked corrupt"
+			continue
+		}
+		for _, claimName := range podClaimNames(&pod) {
+			claim := &corev1.PersistentVolumeClaim{}
+			err := r.Get(ctx, types.NamespacedName{Name: claimName, Namespace: pod.Namespace}, claim)
+			if apierrors.IsNotFound(err) || (err == nil && claim.Status.Phase == corev1.ClaimLost) {
+				lost[pod.Name] = fmt.Sprintf("its PVC %s is lost", claimName)
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("failed to get PVC %s: %w", claimName, err)
+			}
+		}
+	}
+	return lost, nil
+}
+
+// podClaimNames returns the PVCs mounted by a pod
+func podClaimNames(pod *corev1.Pod) []string {
+	var names []string
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			names = append(names, volume.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	return names
+}
+
+// healNode repairs a dead node and records the action as an Event on the DorisCluster. It
+// returns whether an action was taken, the storage of a BE is not replaced while tablets
+// may have their last complete replica on it.
+func (r *DorisClusterReconciler) healNode(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	mgmtClient *doris_client.DorisClient,
+	node *deadNode,
+) (bool, error) {
+	pod := node.pod
+	switch node.action {
+	case healRestart:
+		if err := r.Delete(ctx, pod, ctrlclient.Preconditions{UID: &pod.UID}); ctrlclient.IgnoreNotFound(err) != nil {
+			return false, fmt.Errorf("failed to restart pod %s: %w", pod.Name, err)
+		}
+		logger.Info("Restarted dead Doris node", "cluster", instance.Name, "pod", pod.Name, "reason", node.message)
+		r.recordEvent(instance, pod, corev1.EventTypeWarning, selfHealingReasonRestart, string(node.action),
+			"Restarted pod %s: %s", pod.Name, node.message)
+
+	case healReplaceStorage:
+		atRisk, err := backendHoldsLastReplicas(ctx, mgmtClient, node.backend)
+		if err != nil {
+			return false, err
+		}
+		if atRisk != "" {
+			logger.Info("Not replacing the storage of BE, tablets would lose their last replica",
+				"cluster", instance.Name, "pod", pod.Name, "reason", atRisk)
+			return false, nil
+		}
+		if err := mgmtClient.DropBackend(ctx, node.backend.Host, node.backend.Port); err != nil {
+			return false, fmt.Errorf("failed to drop BE %s: %w", pod.Name, err)
+		}
+		// PVCs in use are only removed once the pod is deleted
+		for _, claimName := range podClaimNames(pod) {
+			claim := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: claimName, Namespace: pod.Namespace},
+			}
+			if err := r.Delete(ctx, claim); ctrlclient.IgnoreNotFound(err) != nil {
+				return true, fmt.Errorf("failed to delete PVC %s: %w", claimName, err)
+			}
+		}
+		if err := r.Delete(ctx, pod, ctrlclient.Preconditions{UID: &pod.UID}); ctrlclient.IgnoreNotFound(err) != nil {
+			return true, fmt.Errorf("failed to delete pod %s: %w", pod.Name, err)
+		}
+		logger.Info("Replaced the storage of dead BE", "cluster", instance.Name, "pod", pod.Name, "reason", node.message)
+		r.recordEvent(instance, pod, corev1.EventTypeWarning, selfHealingReasonReplaceStorage, string(node.action),
+			"Dropped BE %s and deleted its PVCs, it registers again with an empty disk: %s", pod.Name, node.message)
+	}
+	return true, nil
+}
+
+// backendHoldsLastReplicas returns why dropping a BE may lose data, or an empty string when
+// every unhealthy tablet with a replica on the BE has a complete replica on another alive BE.
+// Too many unhealthy tablets to check are reported as a risk: Doris repairs them over time.
+func backendHoldsLastReplicas(
+	ctx context.Context,
+	mgmtClient *doris_client.DorisClient,
+	backend *doris_client.BackendInfo,
+) (string, error) {
+	if backend.ID == 0 {
+		return "the ID of the BE is unknown", nil
+	}
+	tabletIDs, truncated, err := mgmtClient.GetUnhealthyTabletIDs(ctx, maxCheckedTablets)
+	if err != nil {
+		return "", err
+	}
+	if truncated {
+		return fmt.Sprintf("more than %d tablets are unhealthy", maxCheckedTablets), nil
+	}
+	backends, err := mgmtClient.ShowBackends(ctx)
+	if err != nil {
+		return "", err
+	}
+	alive := map[int64]bool{}
+	for _, be := range backends {
+		alive[be.ID] = be.Alive
+	}
+	for _, tabletID := range tabletIDs {
+		replicas, err := mgmtClient.GetTabletReplicas(ctx, tabletID)
+		if err != nil {
+			return "", err
+		}
+		if lastReplicaOn(backend.ID, replicas, alive) {
+			return fmt.Sprintf("tablet %d has no complete replica on another alive BE", tabletID), nil
+		}
+	}
+	return "", nil
+}
+
+// lastReplicaOn returns whether a tablet has a replica on the BE and no complete replica on
+// another alive BE: one not bad, without failed loads, and with the latest version.
+func lastReplicaOn(backendID int64, replicas []doris_client.TabletReplica, alive map[int64]bool) bool {
+	var onBackend bool
+	var latest int64
+	for _, replica := range replicas {
+		onBackend = onBackend || replica.BackendID == backendID
+		latest = max(latest, replica.Version)
+	}
+	if !onBackend {
+		return false
+	}
+	return !slices.ContainsFunc(replicas, func(replica doris_client.TabletReplica) bool {
+		return replica.BackendID != backendID && alive[replica.BackendID] && !replica.Bad &&
+			replica.LastFailedVersion < 0 && replica.Version >= latest
+	})
+}
+
+// selfHealingStatusEqual returns whether two self-healing statuses are the same
+func selfHealingStatusEqual(a, b *dorisv1alpha1.SelfHealingStatus) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if (a.LastActionTime == nil) != (b.LastActionTime == nil) ||
+		(a.LastActionTime != nil && !a.LastActionTime.Equal(b.LastActionTime)) {
+		return false
+	}
+	return maps.EqualFunc(a.DeadSince, b.DeadSince, func(x, y metav1.Time) bool { return x.Equal(&y) })
+}
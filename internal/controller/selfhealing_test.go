@@ -0,0 +1,270 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestDorisPod(name string, componentType constants.ComponentType, phase corev1.PodPhase) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testClusterNamespace,
+			Labels: map[string]string{
+				opgpconstants.LabelKubernetesInstance:  testClusterName,
+				opgpconstants.LabelKubernetesComponent: string(componentType),
+			},
+		},
+		Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+			Name: "data",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-" + name},
+			},
+		}}},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestFindDeadNodes(t *testing.T) {
+	pods := []corev1.Pod{
+		newTestDorisPod("test-fe-default-0", constants.ComponentTypeFE, corev1.PodRunning),
+		newTestDorisPod("test-fe-default-1", constants.ComponentTypeFE, corev1.PodRunning),
+		newTestDorisPod("test-fe-default-2", constants.ComponentTypeFE, corev1.PodPending),
+		newTestDorisPod("test-be-default-0", constants.ComponentTypeBE, corev1.PodRunning),
+		newTestDorisPod("test-be-default-1", constants.ComponentTypeBE, corev1.PodRunning),
+		newTestDorisPod("test-be-default-2", constants.ComponentTypeBE, corev1.PodRunning),
+		newTestDorisPod("test-be-default-3", constants.ComponentTypeBE, corev1.PodPending),
+		newTestDorisPod("test-be-default-4", constants.ComponentTypeBE, corev1.PodRunning),
+		newTestDorisPod("test-be-default-10", constants.ComponentTypeBE, corev1.PodRunning),
+	}
+	frontends := []doris_client.FrontendInfo{
+		{Host: "test-fe-default-0.test-fe-default", Alive: true},
+		{Host: "test-fe-default-1.test-fe-default", Alive: true, ErrMsg: "java.net.ConnectException"},
+		// Not running, restarting it would not help
+		{Host: "test-fe-default-2.test-fe-default", Alive: false},
+	}
+	backends := []doris_client.BackendInfo{
+		// Listed first, its host starts with the name of test-be-default-1
+		{Host: "test-be-default-10.test-be-default", Alive: true},
+		{Host: "test-be-default-0.test-be-default", Alive: true},
+		{Host: "test-be-default-1.test-be-default", Alive: false},
+		{Host: "test-be-default-2.test-be-default", Alive: false, Decommission: true},
+		{Host: "test-be-default-3.test-be-default", Alive: false},
+		// Alive BEs are not replaced, their replicas cannot be checked
+		{Host: "test-be-default-4.test-be-default", Alive: true},
+	}
+	lostStorage := map[string]string{
+		"test-be-default-3": "its PVC data-test-be-default-3 is lost",
+		"test-be-default-4": "its storage is marked corrupt",
+	}
+
+	dead := findDeadNodes(pods, frontends, backends, lostStorage)
+	want := map[string]healAction{
+		"test-fe-default-1": healRestart,
+		"test-be-default-1": healRestart,
+		"test-be-default-3": healReplaceStorage,
+	}
+	if len(dead) != len(want) {
+		t.Fatalf("findDeadNodes() = %d nodes, want %d: %+v", len(dead), len(want), dead)
+	}
+	for _, node := range dead {
+		if want[node.pod.Name] != node.action {
+			t.Errorf("node %s action = %q, want %q", node.pod.Name, node.action, want[node.pod.Name])
+		}
+	}
+	if dead[2].backend == nil || dead[2].backend.Host != "test-be-default-3.test-be-default" {
+		t.Errorf("replaced BE = %+v", dead[2].backend)
+	}
+}
+
+func TestLastReplicaOn(t *testing.T) {
+	alive := map[int64]bool{1: false, 2: true, 3: true, 4: false}
+
+	tests := []struct {
+		name     string
+		replicas []doris_client.TabletReplica
+		want     bool
+	}{
+		{"no replica on the BE", []doris_client.TabletReplica{
+			{BackendID: 2, Version: 5, LastFailedVersion: -1},
+		}, false},
+		{"complete replica elsewhere", []doris_client.TabletReplica{
+			{BackendID: 1, Version: 5, LastFailedVersion: -1},
+			{BackendID: 2, Version: 5, LastFailedVersion: -1},
+		}, false},
+		{"only replica", []doris_client.TabletReplica{
+			{BackendID: 1, Version: 5, LastFailedVersion: -1},
+		}, true},
+		{"other replica missing versions", []doris_client.TabletReplica{
+			{BackendID: 1, Version: 5, LastFailedVersion: -1},
+			{BackendID: 2, Version: 4, LastFailedVersion: -1},
+		}, true},
+		{"other replicas bad, failed or dead", []doris_client.TabletReplica{
+			{BackendID: 1, Version: 5, LastFailedVersion: -1},
+			{BackendID: 2, Version: 5, LastFailedVersion: -1, Bad: true},
+			{BackendID: 3, Version: 5, LastFailedVersion: 6},
+			{BackendID: 4, Version: 5, LastFailedVersion: -1},
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lastReplicaOn(1, tt.replicas, alive); got != tt.want {
+				t.Errorf("lastReplicaOn() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanSelfHealing(t *testing.T) {
+	now := time.Date(2026, 5, 19, 10, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) metav1.Time { return metav1.NewTime(now.Add(-d)) }
+	pods := []corev1.Pod{
+		newTestDorisPod("test-be-default-0", constants.ComponentTypeBE, corev1.PodRunning),
+		newTestDorisPod("test-be-default-1", constants.ComponentTypeBE, corev1.PodRunning),
+	}
+	dead := []deadNode{{pod: &pods[0], action: healRestart}, {pod: &pods[1], action: healRestart}}
+	spec := &dorisv1alpha1.SelfHealingSpec{Enabled: true}
+
+	// Newly dead nodes wait for the grace period
+	next, due, requeue := planSelfHealing(nil, dead, spec, now)
+	if due != nil || requeue != defaultSelfHealingGracePeriod || len(next.DeadSince) != 2 {
+		t.Fatalf("planSelfHealing() = %+v, %v, %v", next, due, requeue)
+	}
+
+	// The node dead the longest is repaired first
+	status := &dorisv1alpha1.SelfHealingStatus{DeadSince: map[string]metav1.Time{
+		"test-be-default-0": ago(11 * time.Minute),
+		"test-be-default-1": ago(20 * time.Minute),
+	}}
+	if _, due, _ := planSelfHealing(status, dead, spec, now); due == nil || due.pod.Name != "test-be-default-1" {
+		t.Fatalf("due = %+v, want test-be-default-1", due)
+	}
+
+	// Actions are rate limited
+	status.LastActionTime = ptr.To(ago(10 * time.Minute))
+	if _, due, requeue := planSelfHealing(status, dead, spec, now); due != nil || requeue != 20*time.Minute {
+		t.Fatalf("planSelfHealing() = %v, %v, want rate limited for 20m", due, requeue)
+	}
+	spec.MinActionInterval = &metav1.Duration{Duration: 5 * time.Minute}
+	if _, due, _ := planSelfHealing(status, dead, spec, now); due == nil {
+		t.Fatal("expected a node to repair once the min action interval elapsed")
+	}
+
+	// Nodes back alive are no longer tracked
+	next, _, _ = planSelfHealing(status, dead[:1], spec, now)
+	if _, ok := next.DeadSince["test-be-default-1"]; ok || len(next.DeadSince) != 1 {
+		t.Errorf("DeadSince = %v, want test-be-default-0 only", next.DeadSince)
+	}
+}
+
+func TestFindLostStorage(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+
+	bound := newTestDorisPod("test-be-default-0", constants.ComponentTypeBE, corev1.PodRunning)
+	lost := newTestDorisPod("test-be-default-1", constants.ComponentTypeBE, corev1.PodRunning)
+	missing := newTestDorisPod("test-be-default-2", constants.ComponentTypeBE, corev1.PodPending)
+	corrupt := newTestDorisPod("test-be-default-3", constants.ComponentTypeBE, corev1.PodRunning)
+	corrupt.Annotations = map[string]string{constants.ReplaceStorageAnnotationKey: "true"}
+	// FE pods never have their storage replaced
+	fe := newTestDorisPod("test-fe-default-0", constants.ComponentTypeFE, corev1.PodRunning)
+
+	claim := func(name string, phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testClusterNamespace},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+	}
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		claim("data-test-be-default-0", corev1.ClaimBound),
+		claim("data-test-be-default-1", corev1.ClaimLost),
+		claim("data-test-be-default-3", corev1.ClaimBound),
+	).Build()
+	r := &DorisClusterReconciler{Client: cli, Scheme: s}
+
+	got, err := r.findLostStorage(ctx, []corev1.Pod{bound, lost, missing, corrupt, fe})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"test-be-default-1", "test-be-default-2", "test-be-default-3"} {
+		if _, ok := got[name]; !ok {
+			t.Errorf("expected the storage of %s to be lost, got %v", name, got)
+		}
+	}
+	if len(got) != 3 {
+		t.Errorf("findLostStorage() = %v, want 3 pods", got)
+	}
+}
+
+func TestReconcileSelfHealingDisabled(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = dorisv1alpha1.AddToScheme(s)
+
+	instance := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testClusterName, Namespace: testClusterNamespace},
+		Status: dorisv1alpha1.DorisClusterStatus{SelfHealing: &dorisv1alpha1.SelfHealingStatus{
+			DeadSince: map[string]metav1.Time{"test-be-default-0": metav1.Now()},
+		}},
+	}
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(instance).WithStatusSubresource(instance).Build()
+	r := &DorisClusterReconciler{Client: cli, Scheme: s}
+
+	// Doris is not reached when self-healing is disabled, and the tracked nodes are cleared
+	if result, err := r.reconcileSelfHealing(ctx, instance); err != nil || !result.IsZero() {
+		t.Fatalf("reconcileSelfHealing() = %v, %v", result, err)
+	}
+	if instance.Status.SelfHealing != nil {
+		t.Errorf("SelfHealing status = %+v, want nil", instance.Status.SelfHealing)
+	}
+}
+
+func TestSoonerResult(t *testing.T) {
+	none := ctrl.Result{}
+	short := ctrl.Result{RequeueAfter: 30 * time.Second}
+	long := ctrl.Result{RequeueAfter: 10 * time.Minute}
+	for _, tt := range []struct{ a, b, want ctrl.Result }{
+		{none, none, none},
+		{none, short, short},
+		{long, none, long},
+		{long, short, short},
+		{short, long, short},
+	} {
+		if got := soonerResult(tt.a, tt.b); got != tt.want {
+			t.Errorf("soonerResult(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
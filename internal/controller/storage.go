@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/be"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	"github.com/zncdatadev/doris-operator/internal/controller/fe"
+	"github.com/zncdatadev/doris-operator/internal/controller/storage"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	opgoutil "github.com/zncdatadev/operator-go/pkg/util"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// desiredClaimTemplates returns the desired volume claim templates of every FE and BE
+// role group, keyed by StatefulSet name.
+func desiredClaimTemplates(instance *dorisv1alpha1.DorisCluster) (map[string][]corev1.PersistentVolumeClaim, error) {
+	templates := make(map[string][]corev1.PersistentVolumeClaim)
+
+	add := func(
+		ct constants.ComponentType,
+		spec *dorisv1alpha1.RoleSpec,
+		build func(*dorisv1alpha1.ConfigSpec) []corev1.PersistentVolumeClaim,
+	) error {
+		if spec == nil {
+			return nil
+		}
+		for name, roleGroup := range spec.RoleGroups {
+			mergedConfig, err := opgoutil.MergeObject(spec.Config, roleGroup.Config)
+			if err != nil {
+				return err
+			}
+			templates[fmt.Sprintf("%s-%s-%s", instance.Name, ct, name)] = build(mergedConfig)
+		}
+		return nil
+	}
+
+	if err := add(constants.ComponentTypeFE, instance.Spec.Frontend, fe.VolumeClaimTemplates); err != nil {
+		return nil, err
+	}
+	if err := add(constants.ComponentTypeBE, instance.Spec.Backend, be.VolumeClaimTemplates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// reconcileVolumeExpansion grows the FE metadata and BE storage PVCs when the requested
+// capacity increases. It runs before Phase 1 because the StatefulSet volume claim templates
+// are immutable: while an expansion is in progress the StatefulSet must not be updated, and
+// once the PVCs are resized the StatefulSet is orphan-deleted so Phase 1 recreates it.
+func (r *DorisClusterReconciler) reconcileVolumeExpansion(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (ctrl.Result, error) {
+	templates, err := desiredClaimTemplates(instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	expansionMgr := storage.NewExpansionManager(r.Client)
+	for _, ct := range []constants.ComponentType{constants.ComponentTypeFE, constants.ComponentTypeBE} {
+		stsList := &appsv1.StatefulSetList{}
+		labelSelector := ctrlclient.MatchingLabels{
+			opgpconstants.LabelKubernetesInstance:  instance.Name,
+			opgpconstants.LabelKubernetesComponent: string(ct),
+		}
+		if err := r.List(ctx, stsList, labelSelector, ctrlclient.InNamespace(instance.Namespace)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to list StatefulSets for %s: %w", ct, err)
+		}
+
+		for i := range stsList.Items {
+			sts := &stsList.Items[i]
+			desired, ok := templates[sts.Name]
+			if !ok {
+				continue
+			}
+
+			result, err := expansionMgr.Reconcile(ctx, sts, desired)
+			if err != nil {
+				return ctrl.Result{}, fmt.Errorf("volume expansion of %s failed: %w", sts.Name, err)
+			}
+			if result.InProgress {
+				logger.Info("Volume expansion in progress, requeuing",
+					"cluster", instance.Name, "statefulset", sts.Name, "after", result.RequeueAfter)
+				return ctrl.Result{RequeueAfter: result.RequeueAfter}, nil
+			}
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// cleanupScaledClaims deletes the PVCs of FE and BE pods removed by a scale-down when
+// `whenScaled` is Delete. Doris is queried directly: a PVC is kept as long as the node
+// is still listed by SHOW BACKENDS / SHOW FRONTENDS, or when Doris cannot be queried.
+func (r *DorisClusterReconciler) cleanupScaledClaims(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	dorisClient *doris_client.DorisClient,
+) error {
+	if storage.GetWhenScaled(&instance.Spec) != storage.RetentionPolicyDelete {
+		return nil
+	}
+
+	backends, err := dorisClient.ShowBackends(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list backends for PVC cleanup: %w", err)
+	}
+	frontends, err := dorisClient.ShowFrontends(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list frontends for PVC cleanup: %w", err)
+	}
+
+	registered := map[constants.ComponentType]func(string) bool{
+		constants.ComponentTypeBE: func(podName string) bool {
+			return doris_client.MatchPodToBackend(podName, backends) != nil
+		},
+		constants.ComponentTypeFE: func(podName string) bool {
+			return doris_client.MatchPodToFrontend(podName, frontends) != nil
+		},
+	}
+
+	retentionMgr := storage.NewRetentionManager(r.Client)
+	for _, ct := range []constants.ComponentType{constants.ComponentTypeFE, constants.ComponentTypeBE} {
+		stsList := &appsv1.StatefulSetList{}
+		labelSelector := ctrlclient.MatchingLabels{
+			opgpconstants.LabelKubernetesInstance:  instance.Name,
+			opgpconstants.LabelKubernetesComponent: string(ct),
+		}
+		if err := r.List(ctx, stsList, labelSelector, ctrlclient.InNamespace(instance.Namespace)); err != nil {
+			return fmt.Errorf("failed to list StatefulSets for %s: %w", ct, err)
+		}
+
+		for i := range stsList.Items {
+			deleted, err := retentionMgr.CleanupScaledClaims(ctx, &stsList.Items[i], registered[ct])
+			if err != nil {
+				return err
+			}
+			if len(deleted) > 0 {
+				logger.Info("Deleted PVCs of scaled-down pods", "cluster", instance.Name, "pvcs", deleted)
+			}
+		}
+	}
+	return nil
+}
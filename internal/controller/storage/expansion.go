@@ -0,0 +1,269 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var expansionLogger = ctrl.Log.WithName("storage-expansion")
+
+// DefaultExpansionRequeueInterval is the interval used to poll PVC resize progress
+const DefaultExpansionRequeueInterval = 10 * time.Second
+
+// ExpansionResult contains the result of a volume expansion step
+type ExpansionResult struct {
+	// InProgress is true while PVCs are being resized or the StatefulSet is being recreated
+	InProgress bool
+	// RequeueAfter is the duration to wait before checking progress again
+	RequeueAfter time.Duration
+}
+
+// claim is an existing PVC created from a StatefulSet volume claim template
+type claim struct {
+	pvc      *corev1.PersistentVolumeClaim
+	ordinal  int
+	template string
+	target   resource.Quantity
+}
+
+// ExpansionManager grows the PVCs of a StatefulSet when the requested storage
+// of its volume claim templates increases.
+//
+// The volume claim templates of a StatefulSet are immutable, so the expansion runs in steps:
+//  1. every existing PVC is patched to the new size, one pod ordinal at a time,
+//     waiting for the filesystem resize of the mounted volumes before moving on;
+//  2. the StatefulSet is deleted with orphan propagation, keeping its pods and PVCs;
+//  3. the StatefulSet is recreated with the new templates by the regular reconciliation.
+type ExpansionManager struct {
+	client ctrlclient.Client
+}
+
+// NewExpansionManager creates a new ExpansionManager
+func NewExpansionManager(client ctrlclient.Client) *ExpansionManager {
+	return &ExpansionManager{client: client}
+}
+
+// GrowingTemplates returns the volume claim templates of the StatefulSet whose desired
+// storage request is larger than the current one, keyed by template name.
+// Shrinking is not supported by Kubernetes and is ignored.
+func GrowingTemplates(sts *appsv1.StatefulSet, desired []corev1.PersistentVolumeClaim) map[string]resource.Quantity {
+	current := make(map[string]resource.Quantity, len(sts.Spec.VolumeClaimTemplates))
+	for _, tpl := range sts.Spec.VolumeClaimTemplates {
+		current[tpl.Name] = tpl.Spec.Resources.Requests[corev1.ResourceStorage]
+	}
+
+	growing := make(map[string]resource.Quantity)
+	for _, tpl := range desired {
+		size, ok := current[tpl.Name]
+		if !ok {
+			continue
+		}
+		target := tpl.Spec.Resources.Requests[corev1.ResourceStorage]
+		if target.Cmp(size) > 0 {
+			growing[tpl.Name] = target
+		}
+	}
+	return growing
+}
+
+// ClaimOrdinal parses the pod ordinal from a PVC name created by a StatefulSet
+// volume claim template, i.e. `<template>-<statefulset>-<ordinal>`.
+func ClaimOrdinal(pvcName, template, stsName string) (int, bool) {
+	prefix := template + "-" + stsName + "-"
+	if !strings.HasPrefix(pvcName, prefix) {
+		return 0, false
+	}
+	ordinal, err := strconv.Atoi(pvcName[len(prefix):])
+	if err != nil || ordinal < 0 {
+		return 0, false
+	}
+	return ordinal, true
+}
+
+// IsResized returns true when the PVC reports the target capacity and no resize is pending
+func IsResized(pvc *corev1.PersistentVolumeClaim, target resource.Quantity) bool {
+	capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+	if !ok || capacity.Cmp(target) < 0 {
+		return false
+	}
+	for _, cond := range pvc.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		if cond.Type == corev1.PersistentVolumeClaimResizing || cond.Type == corev1.PersistentVolumeClaimFileSystemResizePending {
+			return false
+		}
+	}
+	return true
+}
+
+// Reconcile runs one expansion step for the StatefulSet.
+// It returns a zero result when there is nothing to expand.
+func (m *ExpansionManager) Reconcile(
+	ctx context.Context,
+	sts *appsv1.StatefulSet,
+	desired []corev1.PersistentVolumeClaim,
+) (*ExpansionResult, error) {
+	if sts.DeletionTimestamp != nil {
+		// Orphan delete from a previous step is still in progress
+		return &ExpansionResult{InProgress: true, RequeueAfter: DefaultExpansionRequeueInterval}, nil
+	}
+
+	growing := GrowingTemplates(sts, desired)
+	if len(growing) == 0 {
+		return &ExpansionResult{}, nil
+	}
+
+	claims, err := m.listClaims(ctx, sts, growing)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fail before touching anything if a PVC cannot be expanded
+	if err := m.checkExpandable(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	var replicas int
+	if sts.Spec.Replicas != nil {
+		replicas = int(*sts.Spec.Replicas)
+	}
+
+	for i := 0; i < len(claims); {
+		// Claims are sorted by ordinal; handle one ordinal at a time
+		ordinal := claims[i].ordinal
+		j := i
+		for j < len(claims) && claims[j].ordinal == ordinal {
+			j++
+		}
+
+		patched := false
+		for _, c := range claims[i:j] {
+			requested := c.pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+			if requested.Cmp(c.target) >= 0 {
+				continue
+			}
+			if err := m.patchClaim(ctx, c); err != nil {
+				return nil, err
+			}
+			patched = true
+		}
+		if patched {
+			return &ExpansionResult{InProgress: true, RequeueAfter: DefaultExpansionRequeueInterval}, nil
+		}
+
+		// Volumes without a pod are resized by the kubelet on their next mount,
+		// so only wait for the ordinals that are currently running.
+		if ordinal < replicas {
+			for _, c := range claims[i:j] {
+				if c.pvc.Status.Phase == corev1.ClaimBound && !IsResized(c.pvc, c.target) {
+					expansionLogger.V(1).Info("Waiting for PVC resize",
+						"pvc", c.pvc.Name, "target", c.target.String())
+					return &ExpansionResult{InProgress: true, RequeueAfter: DefaultExpansionRequeueInterval}, nil
+				}
+			}
+		}
+		i = j
+	}
+
+	// All PVCs have the new size, recreate the StatefulSet with the new templates
+	expansionLogger.Info("PVCs expanded, recreating StatefulSet with orphan delete",
+		"statefulset", sts.Name, "namespace", sts.Namespace)
+	if err := m.client.Delete(ctx, sts, ctrlclient.PropagationPolicy(metav1.DeletePropagationOrphan)); err != nil {
+		return nil, ctrlclient.IgnoreNotFound(err)
+	}
+	return &ExpansionResult{InProgress: true, RequeueAfter: DefaultExpansionRequeueInterval}, nil
+}
+
+// listClaims returns the existing PVCs of the growing templates, sorted by ordinal and template name
+func (m *ExpansionManager) listClaims(
+	ctx context.Context,
+	sts *appsv1.StatefulSet,
+	growing map[string]resource.Quantity,
+) ([]claim, error) {
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err := m.client.List(ctx, pvcList, ctrlclient.InNamespace(sts.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list PVCs: %w", err)
+	}
+
+	var claims []claim
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		for template, target := range growing {
+			if ordinal, ok := ClaimOrdinal(pvc.Name, template, sts.Name); ok {
+				claims = append(claims, claim{pvc: pvc, ordinal: ordinal, template: template, target: target})
+				break
+			}
+		}
+	}
+
+	sort.Slice(claims, func(i, j int) bool {
+		if claims[i].ordinal != claims[j].ordinal {
+			return claims[i].ordinal < claims[j].ordinal
+		}
+		return claims[i].template < claims[j].template
+	})
+	return claims, nil
+}
+
+// checkExpandable verifies that the StorageClass of every PVC that still needs to grow allows expansion
+func (m *ExpansionManager) checkExpandable(ctx context.Context, claims []claim) error {
+	checked := make(map[string]bool)
+	for _, c := range claims {
+		requested := c.pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		if requested.Cmp(c.target) >= 0 {
+			continue
+		}
+
+		className := ""
+		if c.pvc.Spec.StorageClassName != nil {
+			className = *c.pvc.Spec.StorageClassName
+		}
+		if className == "" {
+			return fmt.Errorf("PVC %s has no storage class, volume expansion is not supported", c.pvc.Name)
+		}
+		if checked[className] {
+			continue
+		}
+
+		sc := &storagev1.StorageClass{}
+		if err := m.client.Get(ctx, types.NamespacedName{Name: className}, sc); err != nil {
+			return fmt.Errorf("failed to get storage class %s: %w", className, err)
+		}
+		if sc.AllowVolumeExpansion == nil || !*sc.AllowVolumeExpansion {
+			return fmt.Errorf("storage class %s of PVC %s does not allow volume expansion", className, c.pvc.Name)
+		}
+		checked[className] = true
+	}
+	return nil
+}
+
+// patchClaim sets the storage request of the PVC to the target size
+func (m *ExpansionManager) patchClaim(ctx context.Context, c claim) error {
+	expansionLogger.Info("Expanding PVC",
+		"pvc", c.pvc.Name, "ordinal", c.ordinal,
+		"from", c.pvc.Spec.Resources.Requests.Storage().String(), "to", c.target.String())
+
+	patch := ctrlclient.MergeFrom(c.pvc.DeepCopy())
+	if c.pvc.Spec.Resources.Requests == nil {
+		c.pvc.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	c.pvc.Spec.Resources.Requests[corev1.ResourceStorage] = c.target
+	if err := m.client.Patch(ctx, c.pvc, patch); err != nil {
+		return fmt.Errorf("failed to expand PVC %s: %w", c.pvc.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNamespace = "default"
+	testSts       = "test-be-default"
+	testTemplate  = "be-storage"
+	testClass     = "standard"
+)
+
+func newTemplate(size string) corev1.PersistentVolumeClaim {
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: testTemplate},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+			},
+		},
+	}
+}
+
+func newStatefulSet(size string, replicas int32) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: testSts, Namespace: testNamespace},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:             ptr.To(replicas),
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{newTemplate(size)},
+		},
+	}
+}
+
+func newClaim(name, requested, capacity string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: ptr.To(testClass),
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(requested)},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Phase:    corev1.ClaimBound,
+			Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)},
+		},
+	}
+}
+
+func TestGrowingTemplates(t *testing.T) {
+	sts := newStatefulSet("20Gi", 1)
+
+	if got := GrowingTemplates(sts, []corev1.PersistentVolumeClaim{newTemplate("20Gi")}); len(got) != 0 {
+		t.Errorf("expected no growing templates for unchanged size, got %v", got)
+	}
+	if got := GrowingTemplates(sts, []corev1.PersistentVolumeClaim{newTemplate("10Gi")}); len(got) != 0 {
+		t.Errorf("expected shrink to be ignored, got %v", got)
+	}
+
+	got := GrowingTemplates(sts, []corev1.PersistentVolumeClaim{newTemplate("50Gi")})
+	target, ok := got[testTemplate]
+	if !ok || !target.Equal(resource.MustParse("50Gi")) {
+		t.Errorf("expected %s to grow to 50Gi, got %v", testTemplate, got)
+	}
+}
+
+func TestClaimOrdinal(t *testing.T) {
+	tests := []struct {
+		pvc     string
+		ordinal int
+		ok      bool
+	}{
+		{pvc: "be-storage-test-be-default-0", ordinal: 0, ok: true},
+		{pvc: "be-storage-test-be-default-12", ordinal: 12, ok: true},
+		{pvc: "be-storage-test-be-default-x", ok: false},
+		{pvc: "be-storage-nvme-test-be-default-0", ok: false},
+	}
+	for _, tt := range tests {
+		ordinal, ok := ClaimOrdinal(tt.pvc, testTemplate, testSts)
+		if ok != tt.ok || ordinal != tt.ordinal {
+			t.Errorf("ClaimOrdinal(%q) = %d, %v; want %d, %v", tt.pvc, ordinal, ok, tt.ordinal, tt.ok)
+		}
+	}
+}
+
+func TestExpansionManager_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	sts := newStatefulSet("20Gi", 2)
+	pvc0 := newClaim("be-storage-test-be-default-0", "20Gi", "20Gi")
+	pvc1 := newClaim("be-storage-test-be-default-1", "20Gi", "20Gi")
+	sc := &storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: testClass},
+		AllowVolumeExpansion: ptr.To(true),
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(sts, pvc0, pvc1, sc).Build()
+	mgr := NewExpansionManager(cli)
+	desired := []corev1.PersistentVolumeClaim{newTemplate("50Gi")}
+
+	getClaim := func(name string) *corev1.PersistentVolumeClaim {
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := cli.Get(ctx, types.NamespacedName{Name: name, Namespace: testNamespace}, pvc); err != nil {
+			t.Fatalf("failed to get PVC %s: %v", name, err)
+		}
+		return pvc
+	}
+	requested := func(name string) string {
+		return getClaim(name).Spec.Resources.Requests.Storage().String()
+	}
+
+	// Step 1: only ordinal 0 is patched
+	result, err := mgr.Reconcile(ctx, sts, desired)
+	if err != nil || !result.InProgress {
+		t.Fatalf("expected in-progress result, got %+v, err=%v", result, err)
+	}
+	if requested(pvc0.Name) != "50Gi" || requested(pvc1.Name) != "20Gi" {
+		t.Fatalf("expected only ordinal 0 to be patched, got %s and %s", requested(pvc0.Name), requested(pvc1.Name))
+	}
+
+	// Step 2: ordinal 0 not resized yet, ordinal 1 must wait
+	if _, err := mgr.Reconcile(ctx, sts, desired); err != nil {
+		t.Fatal(err)
+	}
+	if requested(pvc1.Name) != "20Gi" {
+		t.Fatal("expected ordinal 1 to wait for the filesystem resize of ordinal 0")
+	}
+
+	// Simulate filesystem resize of ordinal 0, then ordinal 1 is patched
+	resized := getClaim(pvc0.Name)
+	resized.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("50Gi")}
+	if err := cli.Status().Update(ctx, resized); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.Reconcile(ctx, sts, desired); err != nil {
+		t.Fatal(err)
+	}
+	if requested(pvc1.Name) != "50Gi" {
+		t.Fatal("expected ordinal 1 to be patched")
+	}
+
+	// Once every PVC is resized, the StatefulSet is orphan-deleted
+	resized = getClaim(pvc1.Name)
+	resized.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("50Gi")}
+	if err := cli.Status().Update(ctx, resized); err != nil {
+		t.Fatal(err)
+	}
+	result, err = mgr.Reconcile(ctx, sts, desired)
+	if err != nil || !result.InProgress {
+		t.Fatalf("expected in-progress result after delete, got %+v, err=%v", result, err)
+	}
+	if err := cli.Get(ctx, types.NamespacedName{Name: testSts, Namespace: testNamespace}, &appsv1.StatefulSet{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected StatefulSet to be deleted, got err=%v", err)
+	}
+}
+
+func TestExpansionManager_StorageClassNotExpandable(t *testing.T) {
+	ctx := context.Background()
+	sts := newStatefulSet("20Gi", 1)
+	pvc := newClaim("be-storage-test-be-default-0", "20Gi", "20Gi")
+	sc := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: testClass}}
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(sts, pvc, sc).Build()
+
+	_, err := NewExpansionManager(cli).Reconcile(ctx, sts, []corev1.PersistentVolumeClaim{newTemplate("50Gi")})
+	if err == nil {
+		t.Fatal("expected an error for a storage class without volume expansion")
+	}
+
+	got := &corev1.PersistentVolumeClaim{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: pvc.Name, Namespace: testNamespace}, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Spec.Resources.Requests.Storage().String() != "20Gi" {
+		t.Error("expected PVC to be left untouched")
+	}
+}
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var retentionLogger = ctrl.Log.WithName("storage-retention")
+
+// PVC retention policies
+const (
+	RetentionPolicyRetain = "Retain"
+	RetentionPolicyDelete = "Delete"
+)
+
+// GetWhenScaled returns the PVC retention policy applied after a scale-down
+func GetWhenScaled(spec *dorisv1alpha1.DorisClusterSpec) string {
+	if spec.ClusterConfig != nil && spec.ClusterConfig.PersistentVolumeClaimRetentionPolicy != nil &&
+		spec.ClusterConfig.PersistentVolumeClaimRetentionPolicy.WhenScaled != "" {
+		return spec.ClusterConfig.PersistentVolumeClaimRetentionPolicy.WhenScaled
+	}
+	return RetentionPolicyRetain
+}
+
+// GetWhenDeleted returns the PVC retention policy applied when the cluster is deleted
+func GetWhenDeleted(spec *dorisv1alpha1.DorisClusterSpec) string {
+	if spec.ClusterConfig != nil && spec.ClusterConfig.PersistentVolumeClaimRetentionPolicy != nil &&
+		spec.ClusterConfig.PersistentVolumeClaimRetentionPolicy.WhenDeleted != "" {
+		return spec.ClusterConfig.PersistentVolumeClaimRetentionPolicy.WhenDeleted
+	}
+	return RetentionPolicyRetain
+}
+
+// StatefulSetRetentionPolicy returns the native StatefulSet PVC retention policy.
+// `whenDeleted` is delegated to the StatefulSet controller. `whenScaled` is always
+// Retain because the StatefulSet controller cannot know whether Doris has released
+// the node; scaled PVCs are removed by RetentionManager instead.
+func StatefulSetRetentionPolicy(spec *dorisv1alpha1.DorisClusterSpec) *appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy {
+	whenDeleted := appsv1.RetainPersistentVolumeClaimRetentionPolicyType
+	if GetWhenDeleted(spec) == RetentionPolicyDelete {
+		whenDeleted = appsv1.DeletePersistentVolumeClaimRetentionPolicyType
+	}
+	return &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+		WhenDeleted: whenDeleted,
+		WhenScaled:  appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+	}
+}
+
+// RetentionManager removes the PVCs of pods that were scaled away
+type RetentionManager struct {
+	client ctrlclient.Client
+}
+
+// NewRetentionManager creates a new RetentionManager
+func NewRetentionManager(client ctrlclient.Client) *RetentionManager {
+	return &RetentionManager{client: client}
+}
+
+// CleanupScaledClaims deletes the PVCs of the StatefulSet whose ordinal is beyond its replicas.
+// A PVC is only deleted when its pod no longer exists and isRegistered reports that Doris
+// does not list the node anymore, i.e. the node was decommissioned or dropped.
+// It returns the names of the deleted PVCs.
+func (m *RetentionManager) CleanupScaledClaims(
+	ctx context.Context,
+	sts *appsv1.StatefulSet,
+	isRegistered func(podName string) bool,
+) ([]string, error) {
+	if sts.DeletionTimestamp != nil {
+		return nil, nil
+	}
+
+	replicas := 1
+	if sts.Spec.Replicas != nil {
+		replicas = int(*sts.Spec.Replicas)
+	}
+
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err := m.client.List(ctx, pvcList, ctrlclient.InNamespace(sts.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list PVCs: %w", err)
+	}
+
+	var deleted []string
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
+
+		ordinal, ok := -1, false
+		for _, tpl := range sts.Spec.VolumeClaimTemplates {
+			if ordinal, ok = ClaimOrdinal(pvc.Name, tpl.Name, sts.Name); ok {
+				break
+			}
+		}
+		if !ok || ordinal < replicas {
+			continue
+		}
+
+		podName := fmt.Sprintf("%s-%d", sts.Name, ordinal)
+		pod := &corev1.Pod{}
+		err := m.client.Get(ctx, types.NamespacedName{Name: podName, Namespace: sts.Namespace}, pod)
+		if err == nil {
+			retentionLogger.V(1).Info("Pod still exists, keeping PVC", "pvc", pvc.Name, "pod", podName)
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			return deleted, fmt.Errorf("failed to get pod %s: %w", podName, err)
+		}
+
+		if isRegistered(podName) {
+			retentionLogger.Info("Node is still registered in Doris, keeping PVC", "pvc", pvc.Name, "pod", podName)
+			continue
+		}
+
+		retentionLogger.Info("Deleting PVC of scaled-down pod", "pvc", pvc.Name, "pod", podName)
+		if err := m.client.Delete(ctx, pvc); ctrlclient.IgnoreNotFound(err) != nil {
+			return deleted, fmt.Errorf("failed to delete PVC %s: %w", pvc.Name, err)
+		}
+		deleted = append(deleted, pvc.Name)
+	}
+	return deleted, nil
+}
+
+// DeleteClaims deletes every PVC created from the volume claim templates of the StatefulSet.
+// It is used during teardown, once all pods are gone, when `whenDeleted` is Delete.
+func (m *RetentionManager) DeleteClaims(ctx context.Context, sts *appsv1.StatefulSet) ([]string, error) {
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err := m.client.List(ctx, pvcList, ctrlclient.InNamespace(sts.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list PVCs: %w", err)
+	}
+
+	var deleted []string
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
+		for _, tpl := range sts.Spec.VolumeClaimTemplates {
+			if _, ok := ClaimOrdinal(pvc.Name, tpl.Name, sts.Name); !ok {
+				continue
+			}
+			retentionLogger.Info("Deleting PVC of deleted cluster", "pvc", pvc.Name)
+			if err := m.client.Delete(ctx, pvc); ctrlclient.IgnoreNotFound(err) != nil {
+				return deleted, fmt.Errorf("failed to delete PVC %s: %w", pvc.Name, err)
+			}
+			deleted = append(deleted, pvc.Name)
+			break
+		}
+	}
+	return deleted, nil
+}
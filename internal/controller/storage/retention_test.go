@@ -0,0 +1,85 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestStatefulSetRetentionPolicy(t *testing.T) {
+	spec := &dorisv1alpha1.DorisClusterSpec{}
+	policy := StatefulSetRetentionPolicy(spec)
+	if policy.WhenDeleted != appsv1.RetainPersistentVolumeClaimRetentionPolicyType {
+		t.Errorf("expected default whenDeleted Retain, got %s", policy.WhenDeleted)
+	}
+
+	spec.ClusterConfig = &dorisv1alpha1.ClusterConfigSpec{
+		PersistentVolumeClaimRetentionPolicy: &dorisv1alpha1.PersistentVolumeClaimRetentionPolicySpec{
+			WhenScaled:  RetentionPolicyDelete,
+			WhenDeleted: RetentionPolicyDelete,
+		},
+	}
+	policy = StatefulSetRetentionPolicy(spec)
+	if policy.WhenDeleted != appsv1.DeletePersistentVolumeClaimRetentionPolicyType {
+		t.Errorf("expected whenDeleted Delete, got %s", policy.WhenDeleted)
+	}
+	// whenScaled is managed by the operator, never by the StatefulSet controller
+	if policy.WhenScaled != appsv1.RetainPersistentVolumeClaimRetentionPolicyType {
+		t.Errorf("expected native whenScaled Retain, got %s", policy.WhenScaled)
+	}
+}
+
+func TestRetentionManager_CleanupScaledClaims(t *testing.T) {
+	ctx := context.Background()
+	sts := newStatefulSet("20Gi", 1)
+	pvc0 := newClaim("be-storage-test-be-default-0", "20Gi", "20Gi")
+	pvc1 := newClaim("be-storage-test-be-default-1", "20Gi", "20Gi")
+	pvc2 := newClaim("be-storage-test-be-default-2", "20Gi", "20Gi")
+	pvc3 := newClaim("be-storage-test-be-default-3", "20Gi", "20Gi")
+	// Pod of ordinal 2 is still terminating
+	pod2 := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-be-default-2", Namespace: testNamespace}}
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(sts, pvc0, pvc1, pvc2, pvc3, pod2).Build()
+
+	// Ordinal 3 is still listed by SHOW BACKENDS
+	isRegistered := func(podName string) bool { return podName == "test-be-default-3" }
+
+	deleted, err := NewRetentionManager(cli).CleanupScaledClaims(ctx, sts, isRegistered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(deleted)
+	if len(deleted) != 1 || deleted[0] != pvc1.Name {
+		t.Errorf("expected only %s to be deleted, got %v", pvc1.Name, deleted)
+	}
+
+	remaining := &corev1.PersistentVolumeClaimList{}
+	if err := cli.List(ctx, remaining); err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining.Items) != 3 {
+		t.Errorf("expected 3 remaining PVCs, got %d", len(remaining.Items))
+	}
+}
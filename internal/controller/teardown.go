@@ -0,0 +1,388 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	"github.com/zncdatadev/doris-operator/internal/controller/storage"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Teardown phases, run in this order
+const (
+	teardownPhaseStoppingLoads     = "StoppingLoads"
+	teardownPhaseBackingUp         = "BackingUp"
+	teardownPhaseStoppingBackends  = "StoppingBackends"
+	teardownPhaseStoppingFrontends = "StoppingFrontends"
+	teardownPhaseReleasingStorage  = "ReleasingStorage"
+	teardownPhaseClearingAuth      = "ClearingAuth"
+	teardownPhaseCompleted         = "Completed"
+)
+
+const (
+	// teardownRequeueInterval is the interval used to poll teardown progress
+	teardownRequeueInterval = 10 * time.Second
+
+	// defaultTeardownBackupTimeout is used when the backup timeout is not set
+	defaultTeardownBackupTimeout = time.Hour
+)
+
+// teardownPhases maps each phase to the next one
+var teardownPhases = map[string]string{
+	teardownPhaseStoppingLoads:     teardownPhaseBackingUp,
+	teardownPhaseBackingUp:         teardownPhaseStoppingBackends,
+	teardownPhaseStoppingBackends:  teardownPhaseStoppingFrontends,
+	teardownPhaseStoppingFrontends: teardownPhaseReleasingStorage,
+	teardownPhaseReleasingStorage:  teardownPhaseClearingAuth,
+	teardownPhaseClearingAuth:      teardownPhaseCompleted,
+}
+
+// ensureFinalizer adds the teardown finalizer to the DorisCluster.
+func (r *DorisClusterReconciler) ensureFinalizer(ctx context.Context, instance *dorisv1alpha1.DorisCluster) error {
+	if controllerutil.ContainsFinalizer(instance, constants.DorisClusterFinalizer) {
+		return nil
+	}
+	patch := ctrlclient.MergeFrom(instance.DeepCopy())
+	controllerutil.AddFinalizer(instance, constants.DorisClusterFinalizer)
+	return r.Patch(ctx, instance, patch)
+}
+
+// reconcileTeardown runs the teardown state machine of a DorisCluster being deleted.
+// Every phase is reported in status.teardown before it runs, and the finalizer is only
+// released once all phases completed. Owned resources are then garbage collected.
+func (r *DorisClusterReconciler) reconcileTeardown(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(instance, constants.DorisClusterFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	phase := ""
+	if instance.Status.Teardown != nil {
+		phase = instance.Status.Teardown.Phase
+	}
+	if phase == "" {
+		phase = teardownPhaseStoppingLoads
+		if err := r.setTeardownPhase(ctx, instance, phase); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for phase != teardownPhaseCompleted {
+		logger.Info("Running teardown phase", "cluster", instance.Name, "phase", phase)
+
+		var done bool
+		var message string
+		var err error
+		switch phase {
+		case teardownPhaseStoppingLoads:
+			done, message, err = r.teardownStopLoads(ctx, instance)
+		case teardownPhaseBackingUp:
+			done, message, err = r.teardownBackup(ctx, instance)
+		case teardownPhaseStoppingBackends:
+			done, message, err = r.teardownStopComponents(ctx, instance,
+				constants.ComponentTypeBE, constants.ComponentTypeBroker)
+		case teardownPhaseStoppingFrontends:
+			done, message, err = r.teardownStopComponents(ctx, instance, constants.ComponentTypeFE)
+		case teardownPhaseReleasingStorage:
+			done, message, err = r.teardownReleaseStorage(ctx, instance)
+		case teardownPhaseClearingAuth:
+			done, message, err = r.teardownClearAuth(ctx, instance)
+		default:
+			return ctrl.Result{}, fmt.Errorf("unknown teardown phase %q", phase)
+		}
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if !done {
+			if err := r.setTeardownMessage(ctx, instance, message); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: teardownRequeueInterval}, nil
+		}
+
+		phase = teardownPhases[phase]
+		if err := r.setTeardownPhase(ctx, instance, phase); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	logger.Info("Teardown completed, releasing finalizer", "cluster", instance.Name)
+	patch := ctrlclient.MergeFrom(instance.DeepCopy())
+	controllerutil.RemoveFinalizer(instance, constants.DorisClusterFinalizer)
+	if err := r.Patch(ctx, instance, patch); err != nil {
+		return ctrl.Result{}, ctrlclient.IgnoreNotFound(err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// setTeardownPhase records a new teardown phase in status.
+func (r *DorisClusterReconciler) setTeardownPhase(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	phase string,
+) error {
+	patch := ctrlclient.MergeFrom(instance.DeepCopy())
+	if instance.Status.Teardown == nil {
+		instance.Status.Teardown = &dorisv1alpha1.TeardownStatus{}
+	}
+	instance.Status.Teardown.Phase = phase
+	instance.Status.Teardown.Message = ""
+	instance.Status.Teardown.PhaseStartTime = ptr.To(metav1.Now())
+	return r.Status().Patch(ctx, instance, patch)
+}
+
+// setTeardownMessage records the progress message of the current teardown phase.
+func (r *DorisClusterReconciler) setTeardownMessage(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	message string,
+) error {
+	if instance.Status.Teardown == nil || instance.Status.Teardown.Message == message {
+		return nil
+	}
+	patch := ctrlclient.MergeFrom(instance.DeepCopy())
+	instance.Status.Teardown.Message = message
+	return r.Status().Patch(ctx, instance, patch)
+}
+
+// teardownClearAuth closes the pooled Doris connections logged in with the cluster credentials
+// and deregisters the TLS configuration of the cluster. The bootstrap state in status and the
+// annotations go away with the DorisCluster.
+func (r *DorisClusterReconciler) teardownClearAuth(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (bool, string, error) {
+	r.DorisClients.Remove(instance.UID)
+	doris_client.DeregisterTLSConfig(tlsConfigName(instance))
+	return true, "", nil
+}
+
+// newTeardownClient returns the pooled Doris FE client with the management credentials.
+func (r *DorisClusterReconciler) newTeardownClient(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (*doris_client.DorisClient, error) {
+	target, err := r.clusterTarget(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+	return r.DorisClients.Get(ctx, target)
+}
+
+// teardownStopLoads pauses all running routine load jobs.
+// An unreachable FE does not block the teardown: no load can run without FE. A job that
+// cannot be paused blocks it, so that the final backup does not miss its data.
+func (r *DorisClusterReconciler) teardownStopLoads(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (bool, string, error) {
+	teardown := getTeardownSpec(instance)
+	if teardown != nil && teardown.StopRoutineLoads != nil && !*teardown.StopRoutineLoads {
+		return true, "", nil
+	}
+
+	dorisClient, err := r.newTeardownClient(ctx, instance)
+	if err != nil {
+		logger.Info("Doris FE not reachable, skipping routine load stop", "cluster", instance.Name, "error", err)
+		return true, "", nil
+	}
+	defer r.DorisClients.Put(dorisClient)
+
+	databases, err := dorisClient.ShowDatabases(ctx)
+	if err != nil {
+		logger.Info("Doris FE not reachable, skipping routine load stop", "cluster", instance.Name, "error", err)
+		return true, "", nil
+	}
+
+	var paused int
+	for _, db := range databases {
+		jobs, err := dorisClient.ShowRoutineLoads(ctx, db)
+		if err != nil {
+			logger.Info("Doris FE not reachable, skipping routine load stop", "cluster", instance.Name, "error", err)
+			return true, "", nil
+		}
+		for _, job := range jobs {
+			if job.State != "RUNNING" && job.State != "NEED_SCHEDULE" {
+				continue
+			}
+			if err := dorisClient.PauseRoutineLoad(ctx, db, job.Name); err != nil {
+				return false, fmt.Sprintf("failed to pause routine load %s.%s: %v", db, job.Name, err), nil
+			}
+			paused++
+		}
+	}
+	logger.Info("Paused routine load jobs", "cluster", instance.Name, "count", paused)
+	return true, "", nil
+}
+
+// teardownBackup takes the final backup of the configured databases.
+// Unlike the other phases, a backup that cannot run blocks the teardown.
+func (r *DorisClusterReconciler) teardownBackup(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (bool, string, error) {
+	teardown := getTeardownSpec(instance)
+	if teardown == nil || teardown.Backup == nil {
+		return true, "", nil
+	}
+	backup := teardown.Backup
+
+	timeout := defaultTeardownBackupTimeout
+	if backup.Timeout != nil {
+		timeout = backup.Timeout.Duration
+	}
+	if start := instance.Status.Teardown.PhaseStartTime; start != nil && time.Since(start.Time) > timeout {
+		return false, fmt.Sprintf("backup to repository %s timed out after %s, remove teardown.backup to continue",
+			backup.Repository, timeout), nil
+	}
+
+	dorisClient, err := r.newTeardownClient(ctx, instance)
+	if err != nil {
+		return false, fmt.Sprintf("waiting for Doris FE to take the final backup: %v", err), nil
+	}
+	defer r.DorisClients.Put(dorisClient)
+
+	label := instance.Status.Teardown.BackupLabel
+	if label == "" {
+		label = fmt.Sprintf("%s_teardown_%d", strings.ReplaceAll(instance.Name, "-", "_"), time.Now().Unix())
+		patch := ctrlclient.MergeFrom(instance.DeepCopy())
+		instance.Status.Teardown.BackupLabel = label
+		if err := r.Status().Patch(ctx, instance, patch); err != nil {
+			return false, "", err
+		}
+	}
+
+	databases := backup.Databases
+	if len(databases) == 0 {
+		if databases, err = dorisClient.ShowDatabases(ctx); err != nil {
+			return false, err.Error(), nil
+		}
+	}
+
+	var pending []string
+	for _, db := range databases {
+		job, err := dorisClient.GetBackupJob(ctx, db, label)
+		if err != nil {
+			return false, err.Error(), nil
+		}
+		switch {
+		case job == nil:
+			if err := dorisClient.BackupDatabase(ctx, db, label, backup.Repository); err != nil {
+				return false, fmt.Sprintf("failed to start backup of %s: %v", db, err), nil
+			}
+			logger.Info("Started final backup", "cluster", instance.Name, "database", db, "label", label)
+			pending = append(pending, db)
+		case job.State == doris_client.BackupStateCancelled:
+			return false, fmt.Sprintf("backup of %s was cancelled: %s, remove teardown.backup to continue",
+				db, job.Status), nil
+		case job.State != doris_client.BackupStateFinished:
+			pending = append(pending, db)
+		}
+	}
+
+	if len(pending) > 0 {
+		return false, fmt.Sprintf("backing up %s to repository %s", strings.Join(pending, ", "), backup.Repository), nil
+	}
+	return true, "", nil
+}
+
+// teardownStopComponents scales the StatefulSets of the given components to zero
+// and waits until all their pods are gone.
+func (r *DorisClusterReconciler) teardownStopComponents(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	components ...constants.ComponentType,
+) (bool, string, error) {
+	var running []string
+	for _, ct := range components {
+		stsList, err := r.listComponentStatefulSets(ctx, instance, ct)
+		if err != nil {
+			return false, "", err
+		}
+		for i := range stsList.Items {
+			sts := &stsList.Items[i]
+			if sts.Spec.Replicas == nil || *sts.Spec.Replicas != 0 {
+				patch := ctrlclient.MergeFrom(sts.DeepCopy())
+				sts.Spec.Replicas = ptr.To(int32(0))
+				if err := r.Patch(ctx, sts, patch); err != nil {
+					return false, "", fmt.Errorf("failed to scale down %s: %w", sts.Name, err)
+				}
+			}
+			if sts.Status.Replicas > 0 {
+				running = append(running, fmt.Sprintf("%s (%d)", sts.Name, sts.Status.Replicas))
+			}
+		}
+	}
+
+	if len(running) > 0 {
+		return false, "waiting for pods to stop: " + strings.Join(running, ", "), nil
+	}
+	return true, "", nil
+}
+
+// teardownReleaseStorage applies the `whenDeleted` PVC retention policy.
+func (r *DorisClusterReconciler) teardownReleaseStorage(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (bool, string, error) {
+	if storage.GetWhenDeleted(&instance.Spec) != storage.RetentionPolicyDelete {
+		return true, "", nil
+	}
+
+	retentionMgr := storage.NewRetentionManager(r.Client)
+	for _, ct := range []constants.ComponentType{constants.ComponentTypeFE, constants.ComponentTypeBE} {
+		stsList, err := r.listComponentStatefulSets(ctx, instance, ct)
+		if err != nil {
+			return false, "", err
+		}
+		for i := range stsList.Items {
+			deleted, err := retentionMgr.DeleteClaims(ctx, &stsList.Items[i])
+			if err != nil {
+				return false, "", err
+			}
+			if len(deleted) > 0 {
+				logger.Info("Deleted PVCs", "cluster", instance.Name, "pvcs", deleted)
+			}
+		}
+	}
+	return true, "", nil
+}
+
+// listComponentStatefulSets lists the StatefulSets of a cluster component.
+func (r *DorisClusterReconciler) listComponentStatefulSets(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	ct constants.ComponentType,
+) (*appsv1.StatefulSetList, error) {
+	stsList := &appsv1.StatefulSetList{}
+	labelSelector := ctrlclient.MatchingLabels{
+		opgpconstants.LabelKubernetesInstance:  instance.Name,
+		opgpconstants.LabelKubernetesComponent: string(ct),
+	}
+	if err := r.List(ctx, stsList, labelSelector, ctrlclient.InNamespace(instance.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list StatefulSets for %s: %w", ct, err)
+	}
+	return stsList, nil
+}
+
+// getTeardownSpec returns the teardown configuration of the cluster, if any.
+func getTeardownSpec(instance *dorisv1alpha1.DorisCluster) *dorisv1alpha1.TeardownSpec {
+	if instance.Spec.ClusterConfig == nil {
+		return nil
+	}
+	return instance.Spec.ClusterConfig.Teardown
+}
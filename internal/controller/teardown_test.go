@@ -0,0 +1,118 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileTeardown(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = dorisv1alpha1.AddToScheme(s)
+
+	now := metav1.Now()
+	instance := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              testClusterName,
+			Namespace:         testClusterNamespace,
+			Finalizers:        []string{constants.DorisClusterFinalizer},
+			DeletionTimestamp: &now,
+			Annotations:       map[string]string{testAnnoBE0: testTimestamp},
+		},
+		Spec: dorisv1alpha1.DorisClusterSpec{
+			ClusterConfig: &dorisv1alpha1.ClusterConfigSpec{
+				Teardown: &dorisv1alpha1.TeardownSpec{StopRoutineLoads: ptr.To(false)},
+			},
+		},
+		Status: dorisv1alpha1.DorisClusterStatus{AuthInitialized: true},
+	}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testClusterName + "-be-default",
+			Namespace: testClusterNamespace,
+			Labels: map[string]string{
+				opgpconstants.LabelKubernetesInstance:  testClusterName,
+				opgpconstants.LabelKubernetesComponent: string(constants.ComponentTypeBE),
+			},
+		},
+		Spec:   appsv1.StatefulSetSpec{Replicas: ptr.To(int32(3))},
+		Status: appsv1.StatefulSetStatus{Replicas: 3},
+	}
+
+	cli := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(instance, sts).
+		WithStatusSubresource(&dorisv1alpha1.DorisCluster{}, &appsv1.StatefulSet{}).
+		Build()
+	r := &DorisClusterReconciler{Client: cli, Scheme: s, DorisClients: doris_client.NewClientManager()}
+
+	// BE pods are still running: teardown waits in StoppingBackends
+	result, err := r.reconcileTeardown(ctx, instance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RequeueAfter != teardownRequeueInterval {
+		t.Errorf("expected requeue after %s, got %v", teardownRequeueInterval, result)
+	}
+	if instance.Status.Teardown == nil || instance.Status.Teardown.Phase != teardownPhaseStoppingBackends {
+		t.Fatalf("expected phase %s, got %+v", teardownPhaseStoppingBackends, instance.Status.Teardown)
+	}
+	if instance.Status.Teardown.Message == "" {
+		t.Error("expected a progress message while waiting for pods")
+	}
+
+	current := &appsv1.StatefulSet{}
+	if err := cli.Get(ctx, ctrlclient.ObjectKeyFromObject(sts), current); err != nil {
+		t.Fatal(err)
+	}
+	if current.Spec.Replicas == nil || *current.Spec.Replicas != 0 {
+		t.Fatalf("expected BE StatefulSet scaled to 0, got %v", current.Spec.Replicas)
+	}
+
+	// Pods are gone: teardown runs to completion and releases the finalizer
+	current.Status.Replicas = 0
+	if err := cli.Status().Update(ctx, current); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.reconcileTeardown(ctx, instance); err != nil {
+		t.Fatal(err)
+	}
+	if instance.Status.Teardown.Phase != teardownPhaseCompleted {
+		t.Errorf("expected phase %s, got %s", teardownPhaseCompleted, instance.Status.Teardown.Phase)
+	}
+	err = cli.Get(ctx, ctrlclient.ObjectKeyFromObject(instance), &dorisv1alpha1.DorisCluster{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected DorisCluster to be gone once the finalizer is released, got %v", err)
+	}
+}
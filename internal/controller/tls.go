@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/common"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	"github.com/zncdatadev/doris-operator/internal/controller/doris_client"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// registerTLSConfig registers the MySQL TLS configuration of the cluster with the CA that
+// issued the FE certificates, and the operator client certificate when FE requires one.
+// It returns the configuration name and the version of the CA and client certificate
+// Secrets, or empty strings when TLS is disabled.
+func (r *DorisClusterReconciler) registerTLSConfig(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+) (string, string, error) {
+	clientCert, clientCertVersion, err := r.getOperatorClientCertificate(ctx, instance)
+	if err != nil {
+		return "", "", err
+	}
+
+	name := tlsConfigName(instance)
+	tlsSpec := common.GetTLSSpec(instance)
+	if tlsSpec == nil {
+		doris_client.DeregisterTLSConfig(name)
+		return "", "", nil
+	}
+
+	caSecret, err := r.getCASecret(ctx, instance, tlsSpec)
+	if err != nil {
+		return "", "", err
+	}
+	caPEM, ok := caSecret.Data[constants.TLSCACertKey]
+	if !ok {
+		return "", "", fmt.Errorf("CA Secret %s/%s has no %s", caSecret.Namespace, caSecret.Name, constants.TLSCACertKey)
+	}
+
+	version := caSecret.ResourceVersion
+	if clientCertVersion != "" {
+		version += "+" + clientCertVersion
+	}
+
+	// The configuration is only registered again when the certificates or the verification change
+	verifyFull := tlsSpec.Verification != constants.TLSVerifyCA
+	if err := doris_client.EnsureTLSConfig(name, fmt.Sprintf("%s/%t", version, verifyFull),
+		caPEM, verifyFull, clientCert); err != nil {
+		return "", "", fmt.Errorf("failed to register TLS configuration: %w", err)
+	}
+	return name, version, nil
+}
+
+// tlsConfigName returns the name of the MySQL TLS configuration of the cluster
+func tlsConfigName(instance *dorisv1alpha1.DorisCluster) string {
+	return "doris-" + string(instance.UID)
+}
+
+// getCASecret returns the Secret holding the CA certificate of the FE certificates: the
+// configured CA Secret, or the CA Secret of the autoTls backend of the server SecretClass.
+func (r *DorisClusterReconciler) getCASecret(
+	ctx context.Context,
+	instance *dorisv1alpha1.DorisCluster,
+	tlsSpec *dorisv1alpha1.TLSSpec,
+) (*corev1.Secret, error) {
+	if tlsSpec.CASecret == "" {
+		secret, err := r.getAutoTLSCASecret(ctx, common.GetTLSSecretClass(tlsSpec))
+		if err != nil {
+			return nil, fmt.Errorf("%w, set clusterConfig.tls.caSecret", err)
+		}
+		return secret, nil
+	}
+
+	key := types.NamespacedName{Name: tlsSpec.CASecret, Namespace: instance.Namespace}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, key, secret); err != nil {
+		return nil, fmt.Errorf("failed to get CA Secret %s: %w", key, err)
+	}
+	return secret, nil
+}
+
+// getAutoTLSCASecret returns the CA Secret of the autoTls backend of a SecretClass
+func (r *DorisClusterReconciler) getAutoTLSCASecret(ctx context.Context, secretClassName string) (*corev1.Secret, error) {
+	secretClass := &unstructured.Unstructured{}
+	secretClass.SetGroupVersionKind(common.SecretClassGVK)
+	if err := r.Get(ctx, types.NamespacedName{Name: secretClassName}, secretClass); err != nil {
+		return nil, fmt.Errorf("failed to get SecretClass %s: %w", secretClassName, err)
+	}
+	name, _, _ := unstructured.NestedString(secretClass.Object, "spec", "backend", "autoTls", "ca", "secret", "name")
+	namespace, _, _ := unstructured.NestedString(secretClass.Object, "spec", "backend", "autoTls", "ca", "secret", "namespace")
+	if name == "" || namespace == "" {
+		return nil, fmt.Errorf("SecretClass %s has no autoTls CA", secretClassName)
+	}
+
+	key := types.NamespacedName{Name: name, Namespace: namespace}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, key, secret); err != nil {
+		return nil, fmt.Errorf("failed to get CA Secret %s: %w", key, err)
+	}
+	return secret, nil
+}
@@ -0,0 +1,249 @@
+package controller
+
+import (
+	"context"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	"github.com/zncdatadev/doris-operator/internal/controller/constants"
+	authv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/authentication/v1alpha1"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Field indexes used to map referenced objects back to the DorisClusters using them
+const (
+	// authSecretIndex indexes DorisClusters by `spec.authSecret.secretName`
+	authSecretIndex = ".spec.authSecret.secretName"
+
+	// rootPasswordSecretIndex indexes DorisClusters by `spec.rootPasswordSecret.secretName`
+	rootPasswordSecretIndex = ".spec.rootPasswordSecret.secretName"
+
+	// caSecretIndex indexes DorisClusters by `spec.clusterConfig.tls.caSecret`
+	caSecretIndex = ".spec.clusterConfig.tls.caSecret"
+
+	// vectorConfigMapIndex indexes DorisClusters by `spec.clusterConfig.vectorAggregatorConfigMapName`
+	vectorConfigMapIndex = ".spec.clusterConfig.vectorAggregatorConfigMapName"
+
+	// authenticationClassIndex indexes DorisClusters by `spec.clusterConfig.authentication[].authenticationClass`
+	authenticationClassIndex = ".spec.clusterConfig.authentication.authenticationClass"
+
+	// ldapBindSecretIndex indexes AuthenticationClasses by the SecretClass, or the Secret, holding the LDAP bind credentials
+	ldapBindSecretIndex = ".spec.provider.ldap.bindCredentials.secretClass"
+
+	// staticUsersSecretIndex indexes AuthenticationClasses by `spec.provider.static.userCredentialsSecret.name`
+	staticUsersSecretIndex = ".spec.provider.static.userCredentialsSecret.name"
+)
+
+// setupIndexes registers the field indexers used by the watches of the DorisCluster controller.
+func setupIndexes(ctx context.Context, mgr ctrl.Manager) error {
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(ctx, &dorisv1alpha1.DorisCluster{}, authSecretIndex, indexAuthSecret); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(ctx, &dorisv1alpha1.DorisCluster{}, rootPasswordSecretIndex, indexRootPasswordSecret); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(ctx, &dorisv1alpha1.DorisCluster{}, caSecretIndex, indexCASecret); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(ctx, &dorisv1alpha1.DorisCluster{}, vectorConfigMapIndex, indexVectorConfigMap); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(ctx, &dorisv1alpha1.DorisCluster{}, authenticationClassIndex, indexAuthenticationClasses); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(ctx, &authv1alpha1.AuthenticationClass{}, ldapBindSecretIndex, indexLDAPBindSecret); err != nil {
+		return err
+	}
+	return indexer.IndexField(ctx, &authv1alpha1.AuthenticationClass{}, staticUsersSecretIndex, indexStaticUsersSecret)
+}
+
+func indexAuthSecret(obj ctrlclient.Object) []string {
+	instance := obj.(*dorisv1alpha1.DorisCluster)
+	if instance.Spec.AuthSecret == nil || instance.Spec.AuthSecret.SecretName == "" {
+		return nil
+	}
+	return []string{instance.Spec.AuthSecret.SecretName}
+}
+
+func indexRootPasswordSecret(obj ctrlclient.Object) []string {
+	instance := obj.(*dorisv1alpha1.DorisCluster)
+	if instance.Spec.RootPasswordSecret == nil || instance.Spec.RootPasswordSecret.SecretName == "" {
+		return nil
+	}
+	return []string{instance.Spec.RootPasswordSecret.SecretName}
+}
+
+func indexCASecret(obj ctrlclient.Object) []string {
+	instance := obj.(*dorisv1alpha1.DorisCluster)
+	if instance.Spec.ClusterConfig == nil || instance.Spec.ClusterConfig.TLS == nil ||
+		instance.Spec.ClusterConfig.TLS.CASecret == "" {
+		return nil
+	}
+	return []string{instance.Spec.ClusterConfig.TLS.CASecret}
+}
+
+func indexVectorConfigMap(obj ctrlclient.Object) []string {
+	instance := obj.(*dorisv1alpha1.DorisCluster)
+	if instance.Spec.ClusterConfig == nil || instance.Spec.ClusterConfig.VectorAggregatorConfigMapName == nil {
+		return nil
+	}
+	return []string{*instance.Spec.ClusterConfig.VectorAggregatorConfigMapName}
+}
+
+func indexAuthenticationClasses(obj ctrlclient.Object) []string {
+	instance := obj.(*dorisv1alpha1.DorisCluster)
+	if instance.Spec.ClusterConfig == nil {
+		return nil
+	}
+	var names []string
+	for _, auth := range instance.Spec.ClusterConfig.Authentication {
+		if auth.AuthenticationClass != "" {
+			names = append(names, auth.AuthenticationClass)
+		}
+	}
+	return names
+}
+
+func indexLDAPBindSecret(obj ctrlclient.Object) []string {
+	authClass := obj.(*authv1alpha1.AuthenticationClass)
+	provider := authClass.Spec.AuthenticationProvider
+	if provider == nil || provider.LDAP == nil || provider.LDAP.BindCredentials == nil ||
+		provider.LDAP.BindCredentials.SecretClass == "" {
+		return nil
+	}
+	return []string{provider.LDAP.BindCredentials.SecretClass}
+}
+
+func indexStaticUsersSecret(obj ctrlclient.Object) []string {
+	authClass := obj.(*authv1alpha1.AuthenticationClass)
+	provider := authClass.Spec.AuthenticationProvider
+	if provider == nil || provider.Static == nil || provider.Static.UserCredentialsSecret == nil ||
+		provider.Static.UserCredentialsSecret.Name == "" {
+		return nil
+	}
+	return []string{provider.Static.UserCredentialsSecret.Name}
+}
+
+// clusterRequests lists the DorisClusters matching the field index and returns a request for each.
+func (r *DorisClusterReconciler) clusterRequests(
+	ctx context.Context,
+	index, value string,
+	opts ...ctrlclient.ListOption,
+) []reconcile.Request {
+	clusters := &dorisv1alpha1.DorisClusterList{}
+	opts = append(opts, ctrlclient.MatchingFields{index: value})
+	if err := r.List(ctx, clusters, opts...); err != nil {
+		logger.Error(err, "Failed to list DorisClusters by index", "index", index, "value", value)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(clusters.Items))
+	for _, cluster := range clusters.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace},
+		})
+	}
+	return requests
+}
+
+// mapSecretToClusters maps a Secret to the clusters using it as authSecret, as root password,
+// as TLS CA or, through their AuthenticationClass, as LDAP bind credentials or static users.
+func (r *DorisClusterReconciler) mapSecretToClusters(ctx context.Context, obj ctrlclient.Object) []reconcile.Request {
+	requests := r.clusterRequests(ctx, authSecretIndex, obj.GetName(), ctrlclient.InNamespace(obj.GetNamespace()))
+	requests = append(requests,
+		r.clusterRequests(ctx, rootPasswordSecretIndex, obj.GetName(), ctrlclient.InNamespace(obj.GetNamespace()))...)
+	requests = append(requests,
+		r.clusterRequests(ctx, caSecretIndex, obj.GetName(), ctrlclient.InNamespace(obj.GetNamespace()))...)
+
+	requests = append(requests,
+		r.authClassRequests(ctx, ldapBindSecretIndex, obj.GetName(), ctrlclient.InNamespace(obj.GetNamespace()))...)
+	requests = append(requests,
+		r.authClassRequests(ctx, staticUsersSecretIndex, obj.GetName(), ctrlclient.InNamespace(obj.GetNamespace()))...)
+
+	// Secrets found by a k8sSearch SecretClass may live in another namespace than the clusters
+	if secretClass := obj.GetLabels()[constants.SecretClassLabel]; secretClass != "" {
+		requests = append(requests, r.authClassRequests(ctx, ldapBindSecretIndex, secretClass)...)
+	}
+	return requests
+}
+
+// authClassRequests lists the AuthenticationClasses matching the field index and returns a
+// request for each cluster using one of them.
+func (r *DorisClusterReconciler) authClassRequests(
+	ctx context.Context,
+	index, value string,
+	opts ...ctrlclient.ListOption,
+) []reconcile.Request {
+	authClasses := &authv1alpha1.AuthenticationClassList{}
+	if err := r.List(ctx, authClasses, ctrlclient.MatchingFields{index: value}); err != nil {
+		logger.Error(err, "Failed to list AuthenticationClasses by index", "index", index, "value", value)
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, authClass := range authClasses.Items {
+		requests = append(requests, r.clusterRequests(ctx, authenticationClassIndex, authClass.Name, opts...)...)
+	}
+	return requests
+}
+
+// mapConfigMapToClusters maps the vector aggregator discovery ConfigMap to the clusters using it.
+func (r *DorisClusterReconciler) mapConfigMapToClusters(ctx context.Context, obj ctrlclient.Object) []reconcile.Request {
+	return r.clusterRequests(ctx, vectorConfigMapIndex, obj.GetName(), ctrlclient.InNamespace(obj.GetNamespace()))
+}
+
+// mapAuthenticationClassToClusters maps a cluster-scoped AuthenticationClass to the clusters using it.
+func (r *DorisClusterReconciler) mapAuthenticationClassToClusters(ctx context.Context, obj ctrlclient.Object) []reconcile.Request {
+	return r.clusterRequests(ctx, authenticationClassIndex, obj.GetName())
+}
+
+// mapPodToCluster maps a Doris pod to its cluster through the instance label.
+// Pods are owned by StatefulSets, so they cannot be watched with Owns.
+func mapPodToCluster(_ context.Context, obj ctrlclient.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	if labels[opgpconstants.LabelKubernetesManagedBy] != dorisv1alpha1.GroupVersion.Group {
+		return nil
+	}
+	name := labels[opgpconstants.LabelKubernetesInstance]
+	if name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: obj.GetNamespace()}}}
+}
+
+// clusterSpecPredicate ignores the status updates of a DorisCluster: the status the
+// controller records, e.g. the node journal and disk usage, changes on every reconcile and
+// would requeue the cluster forever. Spec changes and deletions bump the generation, and
+// annotations request operations such as restarts.
+var clusterSpecPredicate = predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})
+
+// podReadinessPredicate only passes pod creations, deletions and readiness changes.
+var podReadinessPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldPod, ok := e.ObjectOld.(*corev1.Pod)
+		if !ok {
+			return false
+		}
+		newPod, ok := e.ObjectNew.(*corev1.Pod)
+		if !ok {
+			return false
+		}
+		return isPodReady(oldPod) != isPodReady(newPod)
+	},
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2025 zncdatadev.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	dorisv1alpha1 "github.com/zncdatadev/doris-operator/api/v1alpha1"
+	authv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/authentication/v1alpha1"
+	commonsv1alpha1 "github.com/zncdatadev/operator-go/pkg/apis/commons/v1alpha1"
+	opgpconstants "github.com/zncdatadev/operator-go/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestWatchMappers(t *testing.T) {
+	ctx := context.Background()
+
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = dorisv1alpha1.AddToScheme(s)
+	_ = authv1alpha1.AddToScheme(s)
+
+	instance := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testClusterName, Namespace: testClusterNamespace},
+		Spec: dorisv1alpha1.DorisClusterSpec{
+			AuthSecret: &dorisv1alpha1.AuthSecretSpec{SecretName: "doris-admin"},
+			ClusterConfig: &dorisv1alpha1.ClusterConfigSpec{
+				VectorAggregatorConfigMapName: ptr.To("vector-aggregator"),
+				Authentication: []dorisv1alpha1.AuthenticationSpec{
+					{AuthenticationClass: "ldap"},
+					{AuthenticationClass: "static"},
+				},
+			},
+		},
+	}
+	other := &dorisv1alpha1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: testClusterNamespace},
+	}
+	authClass := &authv1alpha1.AuthenticationClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "ldap"},
+		Spec: authv1alpha1.AuthenticationClassSpec{
+			AuthenticationProvider: &authv1alpha1.AuthenticationProvider{
+				LDAP: &authv1alpha1.LDAPProvider{
+					Hostname:        "openldap",
+					BindCredentials: &commonsv1alpha1.Credentials{SecretClass: "ldap-bind"},
+				},
+			},
+		},
+	}
+
+	staticClass := &authv1alpha1.AuthenticationClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "static"},
+		Spec: authv1alpha1.AuthenticationClassSpec{
+			AuthenticationProvider: &authv1alpha1.AuthenticationProvider{
+				Static: &authv1alpha1.StaticProvider{
+					UserCredentialsSecret: &authv1alpha1.StaticCredentialsSecret{Name: "doris-users"},
+				},
+			},
+		},
+	}
+
+	cli := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(instance, other, authClass, staticClass).
+		WithIndex(&dorisv1alpha1.DorisCluster{}, authSecretIndex, indexAuthSecret).
+		WithIndex(&dorisv1alpha1.DorisCluster{}, rootPasswordSecretIndex, indexRootPasswordSecret).
+		WithIndex(&dorisv1alpha1.DorisCluster{}, caSecretIndex, indexCASecret).
+		WithIndex(&dorisv1alpha1.DorisCluster{}, vectorConfigMapIndex, indexVectorConfigMap).
+		WithIndex(&dorisv1alpha1.DorisCluster{}, authenticationClassIndex, indexAuthenticationClasses).
+		WithIndex(&authv1alpha1.AuthenticationClass{}, ldapBindSecretIndex, indexLDAPBindSecret).
+		WithIndex(&authv1alpha1.AuthenticationClass{}, staticUsersSecretIndex, indexStaticUsersSecret).
+		Build()
+	r := &DorisClusterReconciler{Client: cli, Scheme: s}
+
+	objectMeta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: testClusterNamespace}
+	}
+
+	tests := []struct {
+		name     string
+		requests int
+		got      func() int
+	}{
+		{"auth secret", 1, func() int {
+			return len(r.mapSecretToClusters(ctx, &corev1.Secret{ObjectMeta: objectMeta("doris-admin")}))
+		}},
+		{"ldap bind secret", 1, func() int {
+			return len(r.mapSecretToClusters(ctx, &corev1.Secret{ObjectMeta: objectMeta("ldap-bind")}))
+		}},
+		{"static users secret", 1, func() int {
+			return len(r.mapSecretToClusters(ctx, &corev1.Secret{ObjectMeta: objectMeta("doris-users")}))
+		}},
+		{"unrelated secret", 0, func() int {
+			return len(r.mapSecretToClusters(ctx, &corev1.Secret{ObjectMeta: objectMeta("unrelated")}))
+		}},
+		{"vector configmap", 1, func() int {
+			return len(r.mapConfigMapToClusters(ctx, &corev1.ConfigMap{ObjectMeta: objectMeta("vector-aggregator")}))
+		}},
+		{"authentication class", 1, func() int {
+			return len(r.mapAuthenticationClassToClusters(ctx, authClass))
+		}},
+	}
+	for _, tt := range tests {
+		if got := tt.got(); got != tt.requests {
+			t.Errorf("%s: expected %d requests, got %d", tt.name, tt.requests, got)
+		}
+	}
+
+	pod := &corev1.Pod{ObjectMeta: objectMeta(testClusterName + "-be-default-0")}
+	if requests := mapPodToCluster(ctx, pod); len(requests) != 0 {
+		t.Errorf("expected unmanaged pod to be ignored, got %v", requests)
+	}
+	pod.Labels = map[string]string{
+		opgpconstants.LabelKubernetesManagedBy: dorisv1alpha1.GroupVersion.Group,
+		opgpconstants.LabelKubernetesInstance:  testClusterName,
+	}
+	requests := mapPodToCluster(ctx, pod)
+	if len(requests) != 1 || requests[0].Name != testClusterName {
+		t.Errorf("expected pod to map to %s, got %v", testClusterName, requests)
+	}
+}
+
+func TestClusterSpecPredicate(t *testing.T) {
+	old := &dorisv1alpha1.DorisCluster{ObjectMeta: metav1.ObjectMeta{Name: testClusterName, Generation: 1}}
+
+	statusOnly := old.DeepCopy()
+	statusOnly.Status.Health = &dorisv1alpha1.HealthStatus{Score: 90}
+	if clusterSpecPredicate.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: statusOnly}) {
+		t.Error("expected a status update to be ignored")
+	}
+
+	specChanged := old.DeepCopy()
+	specChanged.Generation = 2
+	if !clusterSpecPredicate.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: specChanged}) {
+		t.Error("expected a spec change to pass")
+	}
+
+	annotated := old.DeepCopy()
+	annotated.Annotations = map[string]string{"restart": "now"}
+	if !clusterSpecPredicate.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: annotated}) {
+		t.Error("expected an annotation change to pass")
+	}
+}